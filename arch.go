@@ -0,0 +1,194 @@
+package lpad
+
+import (
+	"net/url"
+)
+
+// Architectures returns the list of architectures supported by
+// this distribution series.
+func (s *DistroSeries) Architectures() (*DistroArchSeriesList, error) {
+	v, err := s.Link("architectures_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroArchSeriesList{v}, nil
+}
+
+// Architecture returns the architecture of this distribution series
+// with the given tag (e.g. "amd64").
+func (s *DistroSeries) Architecture(tag string) (*DistroArchSeries, error) {
+	v, err := s.Location(url.QueryEscape(tag)).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroArchSeries{v}, nil
+}
+
+// The DistroArchSeries type represents a particular architecture
+// of a distribution series.
+type DistroArchSeries struct {
+	*Value
+}
+
+// Tag returns the architecture tag (e.g. "amd64" or "armhf").
+func (das *DistroArchSeries) Tag() string {
+	return das.StringField("architecture_tag")
+}
+
+// DisplayName returns the architecture name as it would be displayed
+// in a paragraph (e.g. "Ubuntu Oneiric i386").
+func (das *DistroArchSeries) DisplayName() string {
+	return das.StringField("display_name")
+}
+
+// Title returns the architecture context title for pages.
+func (das *DistroArchSeries) Title() string {
+	return das.StringField("title")
+}
+
+// WebPage returns the URL for accessing this architecture in a browser.
+func (das *DistroArchSeries) WebPage() string {
+	return das.StringField("web_link")
+}
+
+// NominatedArchIndep returns true if this architecture is the one used
+// to build architecture independent packages in the series.
+func (das *DistroArchSeries) NominatedArchIndep() bool {
+	return das.BoolField("is_nominated_arch_indep")
+}
+
+// Official returns true if the architecture is officially supported
+// by the distribution.
+func (das *DistroArchSeries) Official() bool {
+	return das.BoolField("official")
+}
+
+// SupportsVirtualized returns true if packages for this architecture
+// may be built on virtualized builders, and thus in PPAs.
+func (das *DistroArchSeries) SupportsVirtualized() bool {
+	return das.BoolField("supports_virtualized")
+}
+
+// ChrootURL returns the URL for the chroot tarball used to build
+// packages for this architecture, if there is one.
+func (das *DistroArchSeries) ChrootURL() string {
+	return das.StringField("chroot_url")
+}
+
+// PackageCount returns the number of binary packages published
+// for this architecture.
+func (das *DistroArchSeries) PackageCount() int {
+	return das.IntField("package_count")
+}
+
+// DistroSeries returns the distribution series this architecture is part of.
+func (das *DistroArchSeries) DistroSeries() (*DistroSeries, error) {
+	v, err := das.Link("distroseries_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroSeries{v}, nil
+}
+
+// BinaryPublications returns the publication history of the named
+// binary package for this architecture in the given archive that has
+// the given status.  If status is the empty string, publications are
+// returned regardless of their status.
+func (das *DistroArchSeries) BinaryPublications(archive *Archive, binaryName string, status PublishStatus) (*BinaryPublicationList, error) {
+	params := Params{
+		"ws.op":              "getPublishedBinaries",
+		"binary_name":        binaryName,
+		"exact_match":        "true",
+		"distro_arch_series": das.AbsLoc(),
+	}
+	if status != "" {
+		params["status"] = string(status)
+	}
+	v, err := archive.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &BinaryPublicationList{v}, nil
+}
+
+// The DistroArchSeriesList type represents a list of DistroArchSeries objects.
+type DistroArchSeriesList struct {
+	*Value
+}
+
+// For iterates over the list of architectures and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *DistroArchSeriesList) For(f func(das *DistroArchSeries) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&DistroArchSeries{v})
+	})
+}
+
+// The BinaryPublication type holds a binary package's publication record.
+type BinaryPublication struct {
+	*Value
+}
+
+// PackageName returns the name of the published binary package.
+func (p *BinaryPublication) PackageName() string {
+	return p.StringField("binary_package_name")
+}
+
+// PackageVersion returns the version of the published binary package.
+func (p *BinaryPublication) PackageVersion() string {
+	return p.StringField("binary_package_version")
+}
+
+// Component returns the component name published into.
+func (p *BinaryPublication) Component() string {
+	return p.StringField("component_name")
+}
+
+// Pocket returns the pocket published into.
+func (p *BinaryPublication) Pocket() Pocket {
+	return Pocket(p.StringField("pocket"))
+}
+
+// Status returns the status of the publication.
+func (p *BinaryPublication) Status() PublishStatus {
+	return PublishStatus(p.StringField("status"))
+}
+
+// ArchSpecific returns true if the binary package is built
+// separately for each architecture.
+func (p *BinaryPublication) ArchSpecific() bool {
+	return p.BoolField("architecture_specific")
+}
+
+// DistroArchSeries returns the architecture published into.
+func (p *BinaryPublication) DistroArchSeries() (*DistroArchSeries, error) {
+	v, err := p.Link("distro_arch_series_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroArchSeries{v}, nil
+}
+
+// Build returns the build that produced the binary package.
+func (p *BinaryPublication) Build() (*Build, error) {
+	v, err := p.Link("build_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Build{v}, nil
+}
+
+// BinaryPublicationList represents a list of BinaryPublication objects.
+type BinaryPublicationList struct {
+	*Value
+}
+
+// For iterates over the list of publication objects and calls f for
+// each one. If f returns a non-nil error, iteration will stop and the
+// error will be returned as the result of For.
+func (list *BinaryPublicationList) For(f func(p *BinaryPublication) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&BinaryPublication{v})
+	})
+}
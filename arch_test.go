@@ -0,0 +1,168 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestDistroArchSeries(c *C) {
+	m := M{
+		"architecture_tag":        "amd64",
+		"display_name":            "Ubuntu Oneiric amd64",
+		"title":                   "Title",
+		"web_link":                "http://page",
+		"is_nominated_arch_indep": true,
+		"official":                true,
+		"supports_virtualized":    true,
+		"chroot_url":              "http://chroot",
+		"package_count":           42.0,
+		"distroseries_link":       testServer.URL + "/distroseries_link",
+	}
+	das := &lpad.DistroArchSeries{lpad.NewValue(nil, "", "", m)}
+	c.Assert(das.Tag(), Equals, "amd64")
+	c.Assert(das.DisplayName(), Equals, "Ubuntu Oneiric amd64")
+	c.Assert(das.Title(), Equals, "Title")
+	c.Assert(das.WebPage(), Equals, "http://page")
+	c.Assert(das.NominatedArchIndep(), Equals, true)
+	c.Assert(das.Official(), Equals, true)
+	c.Assert(das.SupportsVirtualized(), Equals, true)
+	c.Assert(das.ChrootURL(), Equals, "http://chroot")
+	c.Assert(das.PackageCount(), Equals, 42)
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "seriesname"}`)
+	series, err := das.DistroSeries()
+	c.Assert(err, IsNil)
+	c.Assert(series.Name(), Equals, "seriesname")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/distroseries_link")
+}
+
+func (s *ModelS) TestDistroSeriesArchitectures(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"architecture_tag": "i386"
+		}, {
+			"self_link": "http://self1",
+			"architecture_tag": "amd64"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{
+		"architectures_collection_link": testServer.URL + "/col_link",
+	}
+	series := &lpad.DistroSeries{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := series.Architectures()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	tags := []string{}
+	list.For(func(das *lpad.DistroArchSeries) error {
+		tags = append(tags, das.Tag())
+		return nil
+	})
+	c.Assert(tags, DeepEquals, []string{"i386", "amd64"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/col_link")
+}
+
+func (s *ModelS) TestDistroSeriesArchitecture(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"architecture_tag": "armhf"}`)
+	series := &lpad.DistroSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu/oneiric", nil)}
+	das, err := series.Architecture("armhf")
+	c.Assert(err, IsNil)
+	c.Assert(das.Tag(), Equals, "armhf")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/ubuntu/oneiric/armhf")
+}
+
+func (s *ModelS) TestDistroArchSeriesBinaryPublications(c *C) {
+	data := `{
+		"total_size": 1,
+		"start": 0,
+		"entries": [{
+			"binary_package_name": "pkgname",
+			"binary_package_version": "1.0",
+			"component_name": "main",
+			"pocket": "Release",
+			"status": "Published",
+			"architecture_specific": true
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	das := &lpad.DistroArchSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu/oneiric/amd64", nil)}
+	archive := &lpad.Archive{lpad.NewValue(nil, testServer.URL, testServer.URL+"/archive", nil)}
+	list, err := das.BinaryPublications(archive, "pkgname", lpad.PubPublished)
+	c.Assert(err, IsNil)
+
+	var pubs []*lpad.BinaryPublication
+	list.For(func(p *lpad.BinaryPublication) error {
+		pubs = append(pubs, p)
+		return nil
+	})
+	c.Assert(pubs, HasLen, 1)
+	c.Assert(pubs[0].PackageName(), Equals, "pkgname")
+	c.Assert(pubs[0].PackageVersion(), Equals, "1.0")
+	c.Assert(pubs[0].Component(), Equals, "main")
+	c.Assert(pubs[0].Pocket(), Equals, lpad.PocketRelease)
+	c.Assert(pubs[0].Status(), Equals, lpad.PubPublished)
+	c.Assert(pubs[0].ArchSpecific(), Equals, true)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/archive")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getPublishedBinaries"})
+	c.Assert(req.Form["binary_name"], DeepEquals, []string{"pkgname"})
+	c.Assert(req.Form["exact_match"], DeepEquals, []string{"true"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"Published"})
+	c.Assert(req.Form["distro_arch_series"], DeepEquals, []string{testServer.URL + "/ubuntu/oneiric/amd64"})
+}
+
+func (s *ModelS) TestBinaryPublicationLinks(c *C) {
+	m := M{
+		"distro_arch_series_link": testServer.URL + "/das_link",
+		"build_link":              testServer.URL + "/build_link",
+	}
+	p := &lpad.BinaryPublication{lpad.NewValue(nil, "", "", m)}
+
+	testServer.PrepareResponse(200, jsonType, `{"architecture_tag": "amd64"}`)
+	das, err := p.DistroArchSeries()
+	c.Assert(err, IsNil)
+	c.Assert(das.Tag(), Equals, "amd64")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/das_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "buildtitle"}`)
+	build, err := p.Build()
+	c.Assert(err, IsNil)
+	c.Assert(build.Title(), Equals, "buildtitle")
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/build_link")
+}
+
+func (s *ModelS) TestBuildDistroArchSeries(c *C) {
+	m := M{"distro_arch_series_link": testServer.URL + "/das_link"}
+	build := &lpad.Build{lpad.NewValue(nil, "", "", m)}
+
+	testServer.PrepareResponse(200, jsonType, `{"architecture_tag": "amd64"}`)
+	das, err := build.DistroArchSeries()
+	c.Assert(err, IsNil)
+	c.Assert(das.Tag(), Equals, "amd64")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/das_link")
+}
@@ -61,6 +61,33 @@ func (a *Archive) Publication(sourceName string, status PublishStatus) (*Publica
 	return &PublicationList{v}, nil
 }
 
+// Builds returns the list of builds in the archive in the given state
+// for the sourceName source package. If state or sourceName are empty,
+// builds are returned regardless of their state or source package.
+func (a *Archive) Builds(state BuildState, sourceName string) (*BuildList, error) {
+	params := Params{"ws.op": "getBuildRecords"}
+	if state != "" {
+		params["build_state"] = string(state)
+	}
+	if sourceName != "" {
+		params["source_name"] = sourceName
+	}
+	v, err := a.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &BuildList{v}, nil
+}
+
+// PPAs returns the list of personal package archives owned by the person.
+func (person *Person) PPAs() (*ArchiveList, error) {
+	v, err := person.Link("ppas_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveList{v}, nil
+}
+
 // ArchiveList represents a list of Archive objects.
 type ArchiveList struct {
 	*Value
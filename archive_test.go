@@ -54,3 +54,45 @@ func (s *ModelS) TestArchivePublication(c *C) {
 	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getPublishedSources"})
 	c.Assert(req.Form["source_name"], DeepEquals, []string{"whatever"})
 }
+
+func (s *ModelS) TestArchiveBuilds(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"title": "i386 build"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	archive := &lpad.Archive{lpad.NewValue(nil, testServer.URL, testServer.URL+"/archive", nil)}
+	list, err := archive.Builds(lpad.BSFailedToBuild, "whatever")
+	c.Assert(err, IsNil)
+
+	titles := []string{}
+	list.For(func(b *lpad.Build) error {
+		titles = append(titles, b.Title())
+		return nil
+	})
+	c.Assert(titles, DeepEquals, []string{"i386 build"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/archive")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getBuildRecords"})
+	c.Assert(req.Form["build_state"], DeepEquals, []string{"Failed to build"})
+	c.Assert(req.Form["source_name"], DeepEquals, []string{"whatever"})
+}
+
+func (s *ModelS) TestPersonPPAs(c *C) {
+	data := `{"total_size": 2, "start": 0, "entries": [{"name": "ppa"}, {"name": "stable"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"ppas_collection_link": testServer.URL + "/ppas_link"}
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := person.PPAs()
+	c.Assert(err, IsNil)
+
+	names := []string{}
+	list.For(func(a *lpad.Archive) error {
+		names = append(names, a.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"ppa", "stable"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/ppas_link")
+}
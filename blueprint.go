@@ -2,6 +2,7 @@ package lpad
 
 import (
 	"fmt"
+	"strings"
 )
 
 
@@ -21,6 +22,59 @@ func (root *Root) Blueprint(target BlueprintTarget, name string) (*Blueprint, er
 	return &Blueprint{v}, nil
 }
 
+// The BlueprintStub type must be used for creating new blueprints
+// via Root.CreateBlueprint.
+type BlueprintStub struct {
+	Target  BlueprintTarget // Required
+	Name    string          // Required
+	Title   string          // Required
+	Summary string          // Required
+	SpecURL string
+}
+
+// CreateBlueprint registers a new blueprint with the details in stub.
+func (root *Root) CreateBlueprint(stub *BlueprintStub) (*Blueprint, error) {
+	target, ok := stub.Target.(interface{ AbsLoc() string })
+	if !ok {
+		return nil, fmt.Errorf("blueprint target %s has no API location", stub.Target.Name())
+	}
+	params := Params{
+		"ws.op":   "createSpecification",
+		"target":  target.AbsLoc(),
+		"name":    stub.Name,
+		"title":   stub.Title,
+		"summary": stub.Summary,
+	}
+	if stub.SpecURL != "" {
+		params["specurl"] = stub.SpecURL
+	}
+	v, err := root.Location("/specs").Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Blueprint{v}, nil
+}
+
+// AllSpecifications returns the list of all blueprints targeted
+// at the project.
+func (p *Project) AllSpecifications() (*BlueprintList, error) {
+	v, err := p.Link("all_specifications_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BlueprintList{v}, nil
+}
+
+// ValidSpecifications returns the list of blueprints targeted at
+// the project that weren't made obsolete or superseded.
+func (p *Project) ValidSpecifications() (*BlueprintList, error) {
+	v, err := p.Link("valid_specifications_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &BlueprintList{v}, nil
+}
+
 // The Blueprint type represents a blueprint in Launchpad.
 type Blueprint struct {
 	*Value
@@ -103,3 +157,303 @@ func (bp *Blueprint) LinkBug(bug *Bug) error {
 	_, err := bp.Post(params)
 	return err
 }
+
+// UnlinkBranch removes the association between a branch and this blueprint.
+func (bp *Blueprint) UnlinkBranch(branch *Branch) error {
+	params := Params{
+		"ws.op":  "unlinkBranch",
+		"branch": branch.AbsLoc(),
+	}
+	_, err := bp.Post(params)
+	return err
+}
+
+// UnlinkBug removes the association between a bug and this blueprint.
+func (bp *Blueprint) UnlinkBug(bug *Bug) error {
+	params := Params{
+		"ws.op": "unlinkBug",
+		"bug":   bug.AbsLoc(),
+	}
+	_, err := bp.Post(params)
+	return err
+}
+
+// SpecURL returns the URL of the external specification for the
+// blueprint, if any.
+func (bp *Blueprint) SpecURL() string {
+	return bp.StringField("specification_url")
+}
+
+// SetSpecURL changes the URL of the external specification for the blueprint.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetSpecURL(url string) {
+	bp.SetField("specification_url", url)
+}
+
+// A DefinitionStatus holds the state of the specification of a blueprint.
+type DefinitionStatus string
+
+const (
+	DefNew             DefinitionStatus = "New"
+	DefDiscussion      DefinitionStatus = "Discussion"
+	DefDrafting        DefinitionStatus = "Drafting"
+	DefPendingApproval DefinitionStatus = "Pending Approval"
+	DefReview          DefinitionStatus = "Review"
+	DefApproved        DefinitionStatus = "Approved"
+	DefSuperseded      DefinitionStatus = "Superseded"
+	DefObsolete        DefinitionStatus = "Obsolete"
+)
+
+// DefinitionStatus returns the status of the blueprint specification.
+func (bp *Blueprint) DefinitionStatus() DefinitionStatus {
+	return DefinitionStatus(bp.StringField("definition_status"))
+}
+
+// SetDefinitionStatus changes the status of the blueprint specification.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetDefinitionStatus(status DefinitionStatus) {
+	bp.SetField("definition_status", string(status))
+}
+
+// An ImplementationStatus holds the state of progress of the
+// implementation of a blueprint.
+type ImplementationStatus string
+
+const (
+	ImplUnknown       ImplementationStatus = "Unknown"
+	ImplNotStarted    ImplementationStatus = "Not started"
+	ImplDeferred      ImplementationStatus = "Deferred"
+	ImplNeedsInfra    ImplementationStatus = "Needs Infrastructure"
+	ImplBlocked       ImplementationStatus = "Blocked"
+	ImplStarted       ImplementationStatus = "Started"
+	ImplSlowProgress  ImplementationStatus = "Slow progress"
+	ImplGoodProgress  ImplementationStatus = "Good progress"
+	ImplBeta          ImplementationStatus = "Beta Available"
+	ImplNeedsReview   ImplementationStatus = "Needs Code Review"
+	ImplDeployment    ImplementationStatus = "Deployment"
+	ImplImplemented   ImplementationStatus = "Implemented"
+	ImplInformational ImplementationStatus = "Informational"
+)
+
+// ImplementationStatus returns the state of progress of the
+// blueprint implementation.
+func (bp *Blueprint) ImplementationStatus() ImplementationStatus {
+	return ImplementationStatus(bp.StringField("implementation_status"))
+}
+
+// SetImplementationStatus changes the state of progress of the
+// blueprint implementation.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetImplementationStatus(status ImplementationStatus) {
+	bp.SetField("implementation_status", string(status))
+}
+
+// A LifecycleStatus holds the overall state of a blueprint.
+type LifecycleStatus string
+
+const (
+	LifeNotStarted LifecycleStatus = "Not started"
+	LifeStarted    LifecycleStatus = "Started"
+	LifeComplete   LifecycleStatus = "Complete"
+)
+
+// LifecycleStatus returns the overall state of the blueprint, as
+// computed by Launchpad from its definition and implementation status.
+func (bp *Blueprint) LifecycleStatus() LifecycleStatus {
+	return LifecycleStatus(bp.StringField("lifecycle_status"))
+}
+
+// A BlueprintPriority holds the importance of a blueprint.
+type BlueprintPriority string
+
+const (
+	PriorityNotForUs  BlueprintPriority = "Not"
+	PriorityUndefined BlueprintPriority = "Undefined"
+	PriorityLow       BlueprintPriority = "Low"
+	PriorityMedium    BlueprintPriority = "Medium"
+	PriorityHigh      BlueprintPriority = "High"
+	PriorityEssential BlueprintPriority = "Essential"
+)
+
+// Priority returns the priority of the blueprint.
+func (bp *Blueprint) Priority() BlueprintPriority {
+	return BlueprintPriority(bp.StringField("priority"))
+}
+
+// SetPriority changes the priority of the blueprint.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetPriority(priority BlueprintPriority) {
+	bp.SetField("priority", string(priority))
+}
+
+// Assignee returns the person responsible for implementing the blueprint.
+func (bp *Blueprint) Assignee() (*Person, error) {
+	v, err := bp.Link("assignee_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// SetAssignee changes the person responsible for implementing the blueprint.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetAssignee(person *Person) {
+	bp.SetField("assignee_link", person.AbsLoc())
+}
+
+// Drafter returns the person responsible for drafting the blueprint
+// specification.
+func (bp *Blueprint) Drafter() (*Person, error) {
+	v, err := bp.Link("drafter_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// SetDrafter changes the person responsible for drafting the blueprint
+// specification.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetDrafter(person *Person) {
+	bp.SetField("drafter_link", person.AbsLoc())
+}
+
+// Approver returns the person responsible for approving the blueprint
+// specification.
+func (bp *Blueprint) Approver() (*Person, error) {
+	v, err := bp.Link("approver_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// SetApprover changes the person responsible for approving the blueprint
+// specification.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetApprover(person *Person) {
+	bp.SetField("approver_link", person.AbsLoc())
+}
+
+// A WorkItemStatus holds the state of a work item of a blueprint.
+type WorkItemStatus string
+
+const (
+	WorkTodo       WorkItemStatus = "TODO"
+	WorkInProgress WorkItemStatus = "INPROGRESS"
+	WorkBlocked    WorkItemStatus = "BLOCKED"
+	WorkDone       WorkItemStatus = "DONE"
+	WorkPostponed  WorkItemStatus = "POSTPONED"
+)
+
+// The WorkItem type holds the details of a single work item
+// of a blueprint.
+type WorkItem struct {
+	Title     string
+	Status    WorkItemStatus
+	Assignee  string // Launchpad name of the assignee, if any.
+	Milestone string // Name of the milestone targeted, if any.
+}
+
+// WorkItems returns the work items of the blueprint. They are parsed
+// from the work items text when it's set, or otherwise from the
+// "Work items" section of the whiteboard.
+func (bp *Blueprint) WorkItems() []*WorkItem {
+	if text := bp.StringField("workitems_text"); text != "" {
+		return parseWorkItems(text, false)
+	}
+	return parseWorkItems(bp.Whiteboard(), true)
+}
+
+// SetWorkItems replaces the work items of the blueprint.
+// Patch must be called to commit all changes.
+func (bp *Blueprint) SetWorkItems(items []*WorkItem) {
+	var buf []string
+	milestone := ""
+	for i, item := range items {
+		if i == 0 || item.Milestone != milestone {
+			if i > 0 {
+				buf = append(buf, "")
+			}
+			if item.Milestone == "" {
+				buf = append(buf, "Work items:")
+			} else {
+				buf = append(buf, "Work items for "+item.Milestone+":")
+			}
+			milestone = item.Milestone
+		}
+		line := item.Title + ": " + string(item.Status)
+		if item.Assignee != "" {
+			line = "[" + item.Assignee + "] " + line
+		}
+		buf = append(buf, line)
+	}
+	bp.SetField("workitems_text", strings.Join(buf, "\n"))
+}
+
+// parseWorkItems parses work items in the format used by Launchpad:
+//
+//	Work items for milestone:
+//	[assignee] Do something: TODO
+//	Do something else: DONE
+//
+// When whiteboard is true, only lines within sections starting with a
+// "Work items" header and ending with a blank line are considered.
+func parseWorkItems(text string, whiteboard bool) []*WorkItem {
+	var items []*WorkItem
+	inSection := !whiteboard
+	milestone := ""
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		lower := strings.ToLower(line)
+		if strings.HasPrefix(lower, "work items") && strings.HasSuffix(line, ":") {
+			inSection = true
+			milestone = ""
+			if strings.HasPrefix(lower, "work items for ") {
+				milestone = strings.TrimSpace(line[len("work items for ") : len(line)-1])
+			}
+			continue
+		}
+		if line == "" {
+			if whiteboard {
+				inSection = false
+			}
+			continue
+		}
+		if !inSection {
+			continue
+		}
+		item := &WorkItem{Milestone: milestone, Status: WorkTodo}
+		if strings.HasPrefix(line, "[") {
+			if i := strings.Index(line, "]"); i > 0 {
+				item.Assignee = strings.TrimSpace(line[1:i])
+				line = strings.TrimSpace(line[i+1:])
+			}
+		}
+		if i := strings.LastIndex(line, ":"); i >= 0 {
+			status := WorkItemStatus(strings.ToUpper(strings.TrimSpace(line[i+1:])))
+			switch status {
+			case WorkTodo, WorkInProgress, WorkBlocked, WorkDone, WorkPostponed:
+				item.Status = status
+				line = strings.TrimSpace(line[:i])
+			}
+		}
+		item.Title = line
+		items = append(items, item)
+	}
+	return items
+}
+
+// The BlueprintList type represents a list of Blueprint objects.
+type BlueprintList struct {
+	*Value
+}
+
+// For iterates over the list of blueprints and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *BlueprintList) For(f func(bp *Blueprint) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&Blueprint{v})
+	})
+}
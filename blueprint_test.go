@@ -91,3 +91,225 @@ func (s *ModelS) TestBlueprintLinkBug(c *C) {
 	c.Assert(req.Form["ws.op"], DeepEquals, []string{"linkBug"})
 	c.Assert(req.Form["bug"], DeepEquals, []string{bug.AbsLoc()})
 }
+
+func (s *ModelS) TestRootCreateBlueprint(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "bp-name"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	project := &lpad.Project{lpad.NewValue(nil, "", "http://myproject", M{"name": "myproject"})}
+	stub := &lpad.BlueprintStub{
+		Target:  project,
+		Name:    "bp-name",
+		Title:   "Title",
+		Summary: "Summary",
+		SpecURL: "http://spec",
+	}
+	bp, err := root.CreateBlueprint(stub)
+	c.Assert(err, IsNil)
+	c.Assert(bp.Name(), Equals, "bp-name")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/specs")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"createSpecification"})
+	c.Assert(req.Form["target"], DeepEquals, []string{"http://myproject"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"bp-name"})
+	c.Assert(req.Form["title"], DeepEquals, []string{"Title"})
+	c.Assert(req.Form["summary"], DeepEquals, []string{"Summary"})
+	c.Assert(req.Form["specurl"], DeepEquals, []string{"http://spec"})
+}
+
+type fakeTarget struct{}
+
+func (fakeTarget) Name() string     { return "fake" }
+func (fakeTarget) BlueprintTarget() {}
+
+func (s *ModelS) TestRootCreateBlueprintWithoutLocation(c *C) {
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	_, err := root.CreateBlueprint(&lpad.BlueprintStub{Target: fakeTarget{}, Name: "bp-name"})
+	c.Assert(err, ErrorMatches, "blueprint target fake has no API location")
+}
+
+func (s *ModelS) TestBlueprintStatus(c *C) {
+	m := M{
+		"specification_url":     "http://spec",
+		"definition_status":     "Drafting",
+		"implementation_status": "Started",
+		"lifecycle_status":      "Started",
+		"priority":              "High",
+	}
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", "", m)}
+	c.Assert(bp.SpecURL(), Equals, "http://spec")
+	c.Assert(bp.DefinitionStatus(), Equals, lpad.DefDrafting)
+	c.Assert(bp.ImplementationStatus(), Equals, lpad.ImplStarted)
+	c.Assert(bp.LifecycleStatus(), Equals, lpad.LifeStarted)
+	c.Assert(bp.Priority(), Equals, lpad.PriorityHigh)
+	bp.SetSpecURL("http://newspec")
+	bp.SetDefinitionStatus(lpad.DefApproved)
+	bp.SetImplementationStatus(lpad.ImplImplemented)
+	bp.SetPriority(lpad.PriorityLow)
+	c.Assert(bp.SpecURL(), Equals, "http://newspec")
+	c.Assert(bp.DefinitionStatus(), Equals, lpad.DefApproved)
+	c.Assert(bp.ImplementationStatus(), Equals, lpad.ImplImplemented)
+	c.Assert(bp.Priority(), Equals, lpad.PriorityLow)
+}
+
+func (s *ModelS) TestBlueprintDefinitionStatusReview(c *C) {
+	m := M{"definition_status": "Pending Approval"}
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", "", m)}
+	c.Assert(bp.DefinitionStatus(), Equals, lpad.DefPendingApproval)
+	bp.SetDefinitionStatus(lpad.DefReview)
+	c.Assert(m["definition_status"], Equals, "Review")
+	c.Assert(bp.DefinitionStatus(), Equals, lpad.DefReview)
+	bp.SetDefinitionStatus(lpad.DefPendingApproval)
+	c.Assert(m["definition_status"], Equals, "Pending Approval")
+}
+
+func (s *ModelS) TestBlueprintPeople(c *C) {
+	m := M{
+		"assignee_link": testServer.URL + "/assignee_link",
+		"drafter_link":  testServer.URL + "/drafter_link",
+		"approver_link": testServer.URL + "/approver_link",
+	}
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", "", m)}
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "bob"}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "ann"}`)
+
+	assignee, err := bp.Assignee()
+	c.Assert(err, IsNil)
+	c.Assert(assignee.Name(), Equals, "joe")
+	drafter, err := bp.Drafter()
+	c.Assert(err, IsNil)
+	c.Assert(drafter.Name(), Equals, "bob")
+	approver, err := bp.Approver()
+	c.Assert(err, IsNil)
+	c.Assert(approver.Name(), Equals, "ann")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/assignee_link")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/drafter_link")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/approver_link")
+
+	person := &lpad.Person{lpad.NewValue(nil, "", "http://person", nil)}
+	bp.SetAssignee(person)
+	bp.SetDrafter(person)
+	bp.SetApprover(person)
+	c.Assert(bp.StringField("assignee_link"), Equals, "http://person")
+	c.Assert(bp.StringField("drafter_link"), Equals, "http://person")
+	c.Assert(bp.StringField("approver_link"), Equals, "http://person")
+}
+
+func (s *ModelS) TestBlueprintWorkItems(c *C) {
+	m := M{
+		"workitems_text": "Work items:\n" +
+			"[joe] Write the code: INPROGRESS\n" +
+			"Write the docs: TODO\n\n" +
+			"Work items for ubuntu-11.10-beta-1:\n" +
+			"Release it: done\n" +
+			"Plan: the next steps",
+	}
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", "", m)}
+	c.Assert(bp.WorkItems(), DeepEquals, []*lpad.WorkItem{
+		{Title: "Write the code", Status: lpad.WorkInProgress, Assignee: "joe"},
+		{Title: "Write the docs", Status: lpad.WorkTodo},
+		{Title: "Release it", Status: lpad.WorkDone, Milestone: "ubuntu-11.10-beta-1"},
+		{Title: "Plan: the next steps", Status: lpad.WorkTodo, Milestone: "ubuntu-11.10-beta-1"},
+	})
+
+	bp.SetWorkItems([]*lpad.WorkItem{
+		{Title: "One", Status: lpad.WorkTodo, Assignee: "joe"},
+		{Title: "Two", Status: lpad.WorkBlocked, Milestone: "beta"},
+	})
+	c.Assert(bp.StringField("workitems_text"), Equals,
+		"Work items:\n[joe] One: TODO\n\nWork items for beta:\nTwo: BLOCKED")
+}
+
+func (s *ModelS) TestBlueprintWorkItemsFromWhiteboard(c *C) {
+	m := M{
+		"whiteboard": "Some notes: DONE\n\n" +
+			"Work items:\n" +
+			"Write the code: DONE\n" +
+			"\n" +
+			"More notes: TODO",
+	}
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", "", m)}
+	c.Assert(bp.WorkItems(), DeepEquals, []*lpad.WorkItem{
+		{Title: "Write the code", Status: lpad.WorkDone},
+	})
+}
+
+func (s *ModelS) TestBlueprintUnlinkBranch(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{}`)
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", testServer.URL+"/project/+spec/the-bp", nil)}
+	branch := &lpad.Branch{lpad.NewValue(nil, testServer.URL, testServer.URL+"~joe/ensemble/some-branch", nil)}
+
+	err := bp.UnlinkBranch(branch)
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/project/+spec/the-bp")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"unlinkBranch"})
+	c.Assert(req.Form["branch"], DeepEquals, []string{branch.AbsLoc()})
+}
+
+func (s *ModelS) TestBlueprintUnlinkBug(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{}`)
+	bp := &lpad.Blueprint{lpad.NewValue(nil, "", testServer.URL+"/project/+spec/the-bp", nil)}
+	bug := &lpad.Bug{lpad.NewValue(nil, testServer.URL, testServer.URL+"~joe/ensemble/some-bug", nil)}
+
+	err := bp.UnlinkBug(bug)
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/project/+spec/the-bp")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"unlinkBug"})
+	c.Assert(req.Form["bug"], DeepEquals, []string{bug.AbsLoc()})
+}
+
+func (s *ModelS) TestProjectSpecifications(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"name": "Name0"
+		}, {
+			"self_link": "http://self1",
+			"name": "Name1"
+		}]
+	}`
+	m := M{
+		"all_specifications_collection_link":   testServer.URL + "/all_link",
+		"valid_specifications_collection_link": testServer.URL + "/valid_link",
+	}
+	project := &lpad.Project{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := project.AllSpecifications()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	names := []string{}
+	list.For(func(bp *lpad.Blueprint) error {
+		names = append(names, bp.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"Name0", "Name1"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/all_link")
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err = project.ValidSpecifications()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/valid_link")
+}
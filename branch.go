@@ -104,6 +104,29 @@ func (b *Branch) WebPage() string {
 	return b.StringField("web_link")
 }
 
+// The BranchList type represents a list of Branch objects.
+type BranchList struct {
+	*Value
+}
+
+// For iterates over the list of branches and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *BranchList) For(f func(b *Branch) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&Branch{v})
+	})
+}
+
+// Branches returns the list of Bazaar branches owned by the person.
+func (person *Person) Branches() (*BranchList, error) {
+	v, err := person.Location("").Get(Params{"ws.op": "getBranches"})
+	if err != nil {
+		return nil, err
+	}
+	return &BranchList{v}, nil
+}
+
 // LandingCandidates returns a list of all the merge proposals that
 // have this branch as the target of the proposed change.
 func (b *Branch) LandingCandidates() (*MergeProposalList, error) {
@@ -300,3 +323,33 @@ func (list *MergeProposalList) For(f func(t *MergeProposal) error) error {
 		return f(&MergeProposal{v})
 	})
 }
+
+// MergeProposals returns the list of merge proposals registered by the
+// person with the given status.  If status is the empty string, proposals
+// are returned regardless of their status.
+func (person *Person) MergeProposals(status MergeProposalStatus) (*MergeProposalList, error) {
+	params := Params{"ws.op": "getMergeProposals"}
+	if status != "" {
+		params["status"] = string(status)
+	}
+	v, err := person.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeProposalList{v}, nil
+}
+
+// RequestedReviews returns the list of merge proposals the person was
+// asked to review with the given status.  If status is the empty string,
+// proposals are returned regardless of their status.
+func (person *Person) RequestedReviews(status MergeProposalStatus) (*MergeProposalList, error) {
+	params := Params{"ws.op": "getRequestedReviews"}
+	if status != "" {
+		params["status"] = string(status)
+	}
+	v, err := person.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &MergeProposalList{v}, nil
+}
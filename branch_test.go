@@ -263,3 +263,54 @@ func (s *ModelS) TestBranchOwner(c *C) {
 	req := testServer.WaitRequest()
 	c.Assert(req.URL.Path, Equals, "/link")
 }
+
+func (s *ModelS) TestPersonBranches(c *C) {
+	data := `{"total_size": 2, "start": 0, "entries": [
+		{"unique_name": "~joe/proj/b1"},
+		{"unique_name": "~joe/proj/b2"}
+	]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	list, err := person.Branches()
+	c.Assert(err, IsNil)
+
+	names := []string{}
+	list.For(func(b *lpad.Branch) error {
+		names = append(names, b.UniqueName())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"~joe/proj/b1", "~joe/proj/b2"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getBranches"})
+}
+
+func (s *ModelS) TestPersonMergeProposals(c *C) {
+	testServer.PrepareResponse(200, jsonType, mpList)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	list, err := person.MergeProposals(lpad.StNeedsReview)
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getMergeProposals"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"Needs review"})
+}
+
+func (s *ModelS) TestPersonRequestedReviews(c *C) {
+	testServer.PrepareResponse(200, jsonType, mpList)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	list, err := person.RequestedReviews("")
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getRequestedReviews"})
+	c.Assert(req.Form["status"], IsNil)
+}
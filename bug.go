@@ -130,6 +130,19 @@ func (bug *Bug) LinkBranch(branch *Branch) error {
 	return err
 }
 
+// AddComment adds a new comment to the bug.
+func (bug *Bug) AddComment(subject, content string) error {
+	params := Params{
+		"ws.op":   "newMessage",
+		"content": content,
+	}
+	if subject != "" {
+		params["subject"] = subject
+	}
+	_, err := bug.Post(params)
+	return err
+}
+
 // A BugTask represents the association of a bug with a project
 // or source package, and the related information.
 type BugTask struct {
@@ -165,6 +178,12 @@ const (
 	StFixReleased  BugStatus = "Fix Released"
 )
 
+// TargetName returns the name of the project or source package
+// the task is associated with.
+func (task *BugTask) TargetName() string {
+	return task.StringField("bug_target_name")
+}
+
 // Status returns the current status for the bug task. See
 // the Status type for supported values.
 func (task *BugTask) Status() BugStatus {
@@ -240,3 +259,33 @@ func (bug *Bug) Tasks() (*BugTaskList, error) {
 	}
 	return &BugTaskList{v}, nil
 }
+
+// A BugTaskRole defines how a person relates to the bug tasks
+// being searched for.
+type BugTaskRole string
+
+const (
+	TaskAssignee   BugTaskRole = "assignee"
+	TaskReporter   BugTaskRole = "bug_reporter"
+	TaskSubscriber BugTaskRole = "bug_subscriber"
+	TaskCommenter  BugTaskRole = "bug_commenter"
+)
+
+// SearchTasks returns the list of bug tasks the person relates to in
+// the given role, such as the tasks assigned to the person or the tasks
+// on bugs the person reported.  If status is the empty string, only
+// tasks which are still open are returned.
+func (person *Person) SearchTasks(role BugTaskRole, status BugStatus) (*BugTaskList, error) {
+	params := Params{
+		"ws.op":      "searchTasks",
+		string(role): person.AbsLoc(),
+	}
+	if status != "" {
+		params["status"] = string(status)
+	}
+	v, err := person.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &BugTaskList{v}, nil
+}
@@ -36,6 +36,11 @@ func (s *ModelS) TestBug(c *C) {
 	c.Assert(bug.SecurityRelated(), Equals, false)
 }
 
+func (s *ModelS) TestBugTaskTargetName(c *C) {
+	task := &lpad.BugTask{lpad.NewValue(nil, "", "", M{"bug_target_name": "ubuntu"})}
+	c.Assert(task.TargetName(), Equals, "ubuntu")
+}
+
 func (s *ModelS) TestBugTask(c *C) {
 	m := M{
 		"assignee_link":  testServer.URL + "/assignee_link",
@@ -177,6 +182,21 @@ func (s *ModelS) TestBugLinkBranch(c *C) {
 	c.Assert(req.Form["branch"], DeepEquals, []string{branch.AbsLoc()})
 }
 
+func (s *ModelS) TestBugAddComment(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{}`)
+	bug := &lpad.Bug{lpad.NewValue(nil, "", testServer.URL+"/bugs/123456", nil)}
+
+	err := bug.AddComment("Subject", "Content")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/bugs/123456")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"newMessage"})
+	c.Assert(req.Form["subject"], DeepEquals, []string{"Subject"})
+	c.Assert(req.Form["content"], DeepEquals, []string{"Content"})
+}
+
 func (s *ModelS) TestBugTasks(c *C) {
 	data := `{
 		"total_size": 2,
@@ -207,3 +227,33 @@ func (s *ModelS) TestBugTasks(c *C) {
 	c.Assert(req.Method, Equals, "GET")
 	c.Assert(req.URL.Path, Equals, "/col_link")
 }
+
+func (s *ModelS) TestPersonSearchTasks(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"status": "In Progress"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	list, err := person.SearchTasks(lpad.TaskAssignee, lpad.StInProgress)
+	c.Assert(err, IsNil)
+
+	statuses := []lpad.BugStatus{}
+	list.For(func(task *lpad.BugTask) error {
+		statuses = append(statuses, task.Status())
+		return nil
+	})
+	c.Assert(statuses, DeepEquals, []lpad.BugStatus{lpad.StInProgress})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"searchTasks"})
+	c.Assert(req.Form["assignee"], DeepEquals, []string{testServer.URL + "/~joe"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"In Progress"})
+
+	testServer.PrepareResponse(200, jsonType, `{"total_size": 0, "start": 0, "entries": []}`)
+	_, err = person.SearchTasks(lpad.TaskReporter, "")
+	c.Assert(err, IsNil)
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["bug_reporter"], DeepEquals, []string{testServer.URL + "/~joe"})
+	c.Assert(req.Form["status"], IsNil)
+}
@@ -3,6 +3,7 @@ package lpad
 import (
 	"fmt"
 	"net/url"
+	"time"
 )
 
 // A BuildState holds the state a package build can be found in.
@@ -74,6 +75,16 @@ func (build *Build) Arch() string {
 	return build.StringField("arch_tag")
 }
 
+// DistroArchSeries returns the distribution series architecture
+// the build is targeted at.
+func (build *Build) DistroArchSeries() (*DistroArchSeries, error) {
+	v, err := build.Link("distro_arch_series_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroArchSeries{v}, nil
+}
+
 // Retry sends a failed build back to the builder farm.
 func (build *Build) Retry() error {
 	_, err := build.Post(Params{"ws.op": "retry"})
@@ -170,3 +181,100 @@ func (list *PublicationList) For(f func(s *Publication) error) error {
 		return f(&Publication{v})
 	})
 }
+
+// A BuildRequestStatus holds the state of a request for builds.
+type BuildRequestStatus string
+
+const (
+	BuildRequestPending   BuildRequestStatus = "Pending"
+	BuildRequestFailed    BuildRequestStatus = "Failed"
+	BuildRequestCompleted BuildRequestStatus = "Completed"
+)
+
+// The BuildRequest type holds the details shared by requests for
+// building a snap, a charm or an OCI image for all its architectures.
+// The builds are only created once the request completes, so the
+// request must be polled until it's done.
+type BuildRequest struct {
+	*Value
+}
+
+// Status returns the current status of the request.
+func (req *BuildRequest) Status() BuildRequestStatus {
+	return BuildRequestStatus(req.StringField("status"))
+}
+
+// Done returns true if the request has either completed or failed.
+func (req *BuildRequest) Done() bool {
+	status := req.Status()
+	return status == BuildRequestCompleted || status == BuildRequestFailed
+}
+
+// ErrorMessage returns the reason why the request failed.
+func (req *BuildRequest) ErrorMessage() string {
+	return req.StringField("error_message")
+}
+
+// WebPage returns the URL for accessing this request in a browser.
+func (req *BuildRequest) WebPage() string {
+	return req.StringField("web_link")
+}
+
+// Wait polls Launchpad every interval for the status of the request
+// until it's done, or until a value is received from stop. The request
+// status must be verified after Wait returns.
+func (req *BuildRequest) Wait(interval time.Duration, stop <-chan bool) error {
+	return req.poll(interval, stop, req.Done)
+}
+
+// The BuildFarmJob type holds the details shared by builds of a snap,
+// a charm or an OCI image for a single architecture.
+type BuildFarmJob struct {
+	*Value
+}
+
+// Title returns the build title.
+func (build *BuildFarmJob) Title() string {
+	return build.StringField("title")
+}
+
+// Arch returns the architecture of build.
+func (build *BuildFarmJob) Arch() string {
+	return build.StringField("arch_tag")
+}
+
+// WebPage returns the URL for accessing this build in a browser.
+func (build *BuildFarmJob) WebPage() string {
+	return build.StringField("web_link")
+}
+
+// State returns the state of build.
+func (build *BuildFarmJob) State() BuildState {
+	return BuildState(build.StringField("buildstate"))
+}
+
+// BuildLogURL returns the URL for the build log file.
+func (build *BuildFarmJob) BuildLogURL() string {
+	return build.StringField("build_log_url")
+}
+
+// UploadLogURL returns the URL for the upload log if there was an upload failure.
+func (build *BuildFarmJob) UploadLogURL() string {
+	return build.StringField("upload_log_url")
+}
+
+// Created returns the timestamp when the build farm job was created.
+func (build *BuildFarmJob) Created() string {
+	return build.StringField("datecreated")
+}
+
+// Finished returns the timestamp when the build farm job was finished.
+func (build *BuildFarmJob) Finished() string {
+	return build.StringField("datebuilt")
+}
+
+// Retry sends a failed build back to the builder farm.
+func (build *BuildFarmJob) Retry() error {
+	_, err := build.Post(Params{"ws.op": "retry"})
+	return err
+}
@@ -1,5 +1,13 @@
 package lpad
 
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
 // API for: https://launchpad.net/builders
 //
 // Not all info presented on that page is available via the LP API though.
@@ -62,6 +70,57 @@ func (b *Builder) WebPage() string {
 	return b.StringField("web_link")
 }
 
+// Manual returns whether the builder is in manual mode, in which case
+// it won't be dispatched builds automatically.
+func (b *Builder) Manual() bool {
+	return b.BoolField("manual")
+}
+
+// A BuilderCleanStatus holds the cleanliness state of a builder.
+type BuilderCleanStatus string
+
+const (
+	CleanStatusClean    BuilderCleanStatus = "Clean"
+	CleanStatusDirty    BuilderCleanStatus = "Dirty"
+	CleanStatusCleaning BuilderCleanStatus = "Cleaning"
+)
+
+// CleanStatus returns whether the builder is ready to take a new build.
+func (b *Builder) CleanStatus() BuilderCleanStatus {
+	return BuilderCleanStatus(b.StringField("clean_status"))
+}
+
+// FailureCount returns the number of consecutive failures of the builder.
+func (b *Builder) FailureCount() int {
+	return b.IntField("failure_count")
+}
+
+// FailNotes returns the reason why the builder is not working fine,
+// if it was provided.
+func (b *Builder) FailNotes() string {
+	return b.StringField("failnotes")
+}
+
+// Processors returns the names of the processors the builder
+// may build packages for (e.g. "amd64").
+func (b *Builder) Processors() []string {
+	var names []string
+	for _, link := range b.StringListField("processors") {
+		names = append(names, path.Base(link))
+	}
+	return names
+}
+
+// CurrentBuild returns the build the builder is currently working on.
+// ErrNotFound is returned if the builder is idle.
+func (b *Builder) CurrentBuild() (*Build, error) {
+	v, err := b.Link("current_build_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Build{v}, nil
+}
+
 // A BuilderList represents a list of Builder objects.
 type BuilderList struct {
 	*Value
@@ -75,3 +134,163 @@ func (list *BuilderList) For(f func(b *Builder) error) error {
 		return f(&Builder{v})
 	})
 }
+
+// BuildQueueStatus returns the number of jobs waiting in the build queue
+// for the given processor (e.g. "amd64") and the estimated time it will
+// take to dispatch them all.  The virtualized flag selects between the
+// queue for virtualized builders, used by PPAs, and the queue for the
+// non-virtualized ones.
+func (root *Root) BuildQueueStatus(processor string, virtualized bool) (size int, wait time.Duration, err error) {
+	v, err := root.Location("/builders").Get(Params{"ws.op": "getBuildQueueSizes"})
+	if err != nil {
+		return 0, 0, err
+	}
+	key := "nonvirt"
+	if virtualized {
+		key = "virt"
+	}
+	queues, ok := v.Map()[key].(map[string]interface{})
+	if !ok {
+		return 0, 0, fmt.Errorf("map is missing %q field", key)
+	}
+	queue, ok := queues[processor]
+	if !ok {
+		return 0, 0, nil
+	}
+	l, ok := queue.([]interface{})
+	if !ok || len(l) != 2 {
+		return 0, 0, fmt.Errorf("unsupported build queue item: %#v", queue)
+	}
+	fsize, ok1 := l[0].(float64)
+	swait, ok2 := l[1].(string)
+	if !(ok1 && ok2) {
+		return 0, 0, fmt.Errorf("unsupported build queue item: %#v", queue)
+	}
+	wait, err = parseDelta(swait)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(fsize), wait, nil
+}
+
+// parseDelta parses a time delta in the format Launchpad uses
+// (e.g. "2 days, 1:02:03.5").
+func parseDelta(s string) (time.Duration, error) {
+	var d time.Duration
+	rest := s
+	if i := strings.Index(rest, ","); i >= 0 {
+		days := strings.Fields(rest[:i])
+		if len(days) != 2 || !strings.HasPrefix(days[1], "day") {
+			return 0, fmt.Errorf("invalid time delta: %q", s)
+		}
+		n, err := strconv.Atoi(days[0])
+		if err != nil {
+			return 0, fmt.Errorf("invalid time delta: %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		rest = strings.TrimSpace(rest[i+1:])
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid time delta: %q", s)
+	}
+	hours, err1 := strconv.Atoi(parts[0])
+	minutes, err2 := strconv.Atoi(parts[1])
+	seconds, err3 := strconv.ParseFloat(parts[2], 64)
+	if err1 != nil || err2 != nil || err3 != nil {
+		return 0, fmt.Errorf("invalid time delta: %q", s)
+	}
+	d += time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+	d += time.Duration(seconds * float64(time.Second))
+	return d, nil
+}
+
+// The BuilderChange type describes a change in the state of a builder
+// noticed by a BuilderPoller.
+type BuilderChange struct {
+	Old *Builder // The builder as seen in the previous poll
+	New *Builder // The builder as seen in the latest poll
+}
+
+// Down returns true if the builder was working fine in the previous
+// poll and is not anymore, either because it failed or because it
+// was disabled.
+func (ch *BuilderChange) Down() bool {
+	return builderUp(ch.Old) && !builderUp(ch.New)
+}
+
+// Up returns true if the builder is back to working fine.
+func (ch *BuilderChange) Up() bool {
+	return !builderUp(ch.Old) && builderUp(ch.New)
+}
+
+func builderUp(b *Builder) bool {
+	return b.Active() && b.BuilderOK()
+}
+
+// The BuilderPoller type monitors the builder fleet for changes.
+// See the Poll and Watch methods.
+type BuilderPoller struct {
+	root *Root
+	last map[string]*Builder
+}
+
+// BuilderPoller returns a new poller for monitoring the builders.
+func (root *Root) BuilderPoller() *BuilderPoller {
+	return &BuilderPoller{root: root}
+}
+
+// Poll retrieves all builders and returns the changes noticed in their
+// availability, manual mode or clean status since the previous call.
+// The first call establishes the initial state and returns no changes.
+func (p *BuilderPoller) Poll() ([]*BuilderChange, error) {
+	list, err := p.root.Builders()
+	if err != nil {
+		return nil, err
+	}
+	var changes []*BuilderChange
+	current := make(map[string]*Builder)
+	err = list.For(func(b *Builder) error {
+		current[b.Name()] = b
+		if p.last == nil {
+			return nil
+		}
+		old, ok := p.last[b.Name()]
+		if !ok {
+			return nil
+		}
+		if old.Active() != b.Active() || old.BuilderOK() != b.BuilderOK() ||
+			old.Manual() != b.Manual() || old.CleanStatus() != b.CleanStatus() {
+			changes = append(changes, &BuilderChange{Old: old, New: b})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	p.last = current
+	return changes, nil
+}
+
+// Watch calls Poll every interval and calls f for every change noticed,
+// until stop is closed, polling fails, or f returns a non-nil error.
+// The error that interrupted the loop is returned, or nil if the
+// stop channel was closed.
+func (p *BuilderPoller) Watch(interval time.Duration, stop <-chan bool, f func(ch *BuilderChange) error) error {
+	for {
+		changes, err := p.Poll()
+		if err != nil {
+			return err
+		}
+		for _, ch := range changes {
+			if err := f(ch); err != nil {
+				return err
+			}
+		}
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
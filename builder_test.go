@@ -1,6 +1,8 @@
 package lpad_test
 
 import (
+	"time"
+
 	. "gopkg.in/check.v1"
 
 	"github.com/canonical/lpad"
@@ -8,13 +10,18 @@ import (
 
 func (s *ModelS) TestBuilder(c *C) {
 	m := M{
-		"name":        "thename",
-		"title":       "Title",
-		"active":      true,
-		"builderok":   true,
-		"virtualized": "false",
-		"vm_host":     "foobar",
-		"web_link":    "http://page",
+		"name":          "thename",
+		"title":         "Title",
+		"active":        true,
+		"builderok":     true,
+		"virtualized":   "false",
+		"vm_host":       "foobar",
+		"web_link":      "http://page",
+		"manual":        true,
+		"clean_status":  "Dirty",
+		"failure_count": 3.0,
+		"failnotes":     "Broken disk",
+		"processors":    []interface{}{"http://api/+processors/amd64", "http://api/+processors/i386"},
 	}
 	builder := &lpad.Builder{lpad.NewValue(nil, "", "", m)}
 	c.Assert(builder.Name(), Equals, "thename")
@@ -24,6 +31,28 @@ func (s *ModelS) TestBuilder(c *C) {
 	c.Assert(builder.Virtualized(), Equals, false)
 	c.Assert(builder.VMHost(), Equals, "foobar")
 	c.Assert(builder.WebPage(), Equals, "http://page")
+	c.Assert(builder.Manual(), Equals, true)
+	c.Assert(builder.CleanStatus(), Equals, lpad.CleanStatusDirty)
+	c.Assert(builder.FailureCount(), Equals, 3)
+	c.Assert(builder.FailNotes(), Equals, "Broken disk")
+	c.Assert(builder.Processors(), DeepEquals, []string{"amd64", "i386"})
+}
+
+func (s *ModelS) TestBuilderCurrentBuild(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"title": "buildtitle"}`)
+	m := M{"current_build_link": testServer.URL + "/build_link"}
+	builder := &lpad.Builder{lpad.NewValue(nil, "", "", m)}
+	build, err := builder.CurrentBuild()
+	c.Assert(err, IsNil)
+	c.Assert(build.Title(), Equals, "buildtitle")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/build_link")
+
+	builder = &lpad.Builder{lpad.NewValue(nil, "", "", nil)}
+	_, err = builder.CurrentBuild()
+	c.Assert(err, Equals, lpad.ErrNotFound)
 }
 
 func (s *ModelS) TestRootBuildersAndBuilderList(c *C) {
@@ -70,3 +99,105 @@ func (s *ModelS) TestRootBuilder(c *C) {
 	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getByName"})
 	c.Assert(req.Form["name"], DeepEquals, []string{"builder1"})
 }
+
+func (s *ModelS) TestRootBuildQueueStatus(c *C) {
+	data := `{
+		"virt": {"amd64": [12, "1 day, 2:03:04.500000"], "i386": [1, "0:00:30"]},
+		"nonvirt": {"amd64": [3, "0:10:00"]}
+	}`
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	size, wait, err := root.BuildQueueStatus("amd64", true)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, 12)
+	c.Assert(wait, Equals, 26*time.Hour+3*time.Minute+4500*time.Millisecond)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/builders")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getBuildQueueSizes"})
+
+	testServer.PrepareResponse(200, jsonType, data)
+	size, wait, err = root.BuildQueueStatus("amd64", false)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, 3)
+	c.Assert(wait, Equals, 10*time.Minute)
+	testServer.WaitRequest()
+
+	testServer.PrepareResponse(200, jsonType, data)
+	size, wait, err = root.BuildQueueStatus("armhf", false)
+	c.Assert(err, IsNil)
+	c.Assert(size, Equals, 0)
+	c.Assert(wait, Equals, time.Duration(0))
+	testServer.WaitRequest()
+
+	testServer.PrepareResponse(200, jsonType, `{"virt": {"amd64": [1, "soon"]}}`)
+	_, _, err = root.BuildQueueStatus("amd64", true)
+	c.Assert(err, ErrorMatches, `invalid time delta: "soon"`)
+	testServer.WaitRequest()
+}
+
+func (s *ModelS) TestBuilderPoller(c *C) {
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	poller := root.BuilderPoller()
+
+	testServer.PrepareResponse(200, jsonType, `{"entries": [
+		{"name": "builder1", "active": true, "builderok": true},
+		{"name": "builder2", "active": true, "builderok": true}
+	]}`)
+	changes, err := poller.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(changes, HasLen, 0)
+	testServer.WaitRequest()
+
+	testServer.PrepareResponse(200, jsonType, `{"entries": [
+		{"name": "builder1", "active": true, "builderok": false, "failnotes": "Boom"},
+		{"name": "builder2", "active": true, "builderok": true},
+		{"name": "builder3", "active": true, "builderok": true}
+	]}`)
+	changes, err = poller.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(changes, HasLen, 1)
+	c.Assert(changes[0].New.Name(), Equals, "builder1")
+	c.Assert(changes[0].New.FailNotes(), Equals, "Boom")
+	c.Assert(changes[0].Down(), Equals, true)
+	c.Assert(changes[0].Up(), Equals, false)
+	testServer.WaitRequest()
+
+	testServer.PrepareResponse(200, jsonType, `{"entries": [
+		{"name": "builder1", "active": true, "builderok": true},
+		{"name": "builder2", "active": true, "builderok": true, "manual": true}
+	]}`)
+	changes, err = poller.Poll()
+	c.Assert(err, IsNil)
+	c.Assert(changes, HasLen, 2)
+	c.Assert(changes[0].New.Name(), Equals, "builder1")
+	c.Assert(changes[0].Up(), Equals, true)
+	c.Assert(changes[1].New.Name(), Equals, "builder2")
+	c.Assert(changes[1].Up(), Equals, false)
+	c.Assert(changes[1].Down(), Equals, false)
+	testServer.WaitRequest()
+}
+
+func (s *ModelS) TestBuilderPollerWatch(c *C) {
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	poller := root.BuilderPoller()
+
+	testServer.PrepareResponse(200, jsonType, `{"entries": [{"name": "builder1", "active": true, "builderok": true}]}`)
+	testServer.PrepareResponse(200, jsonType, `{"entries": [{"name": "builder1", "active": false, "builderok": true}]}`)
+
+	stop := make(chan bool)
+	var down []string
+	err := poller.Watch(time.Millisecond, stop, func(ch *lpad.BuilderChange) error {
+		if ch.Down() {
+			down = append(down, ch.New.Name())
+		}
+		close(stop)
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(down, DeepEquals, []string{"builder1"})
+	testServer.WaitRequest()
+	testServer.WaitRequest()
+}
@@ -0,0 +1,218 @@
+package lpad
+
+import "encoding/json"
+
+// CharmRecipe returns the charm recipe with the given name owned by
+// owner for project.
+func (root *Root) CharmRecipe(owner Member, project *Project, name string) (*CharmRecipe, error) {
+	params := Params{
+		"ws.op":   "getByName",
+		"owner":   owner.AbsLoc(),
+		"project": project.AbsLoc(),
+		"name":    name,
+	}
+	v, err := root.Location("/+charm-recipes").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &CharmRecipe{v}, nil
+}
+
+// The CharmRecipeStub type must be used for creating new charm
+// recipes via Root.CreateCharmRecipe.
+type CharmRecipeStub struct {
+	Owner       Member   // Required
+	Project     *Project // Required
+	Name        string   // Required
+	GitRef      *GitRef  // Required
+	Description string
+
+	// AutoBuild enables building the charm automatically when the
+	// Git reference changes.
+	AutoBuild bool
+
+	// StoreUpload enables uploading successful builds to the charm
+	// store, under StoreName and releasing them to StoreChannels.
+	StoreUpload   bool
+	StoreName     string
+	StoreChannels []string
+}
+
+// CreateCharmRecipe registers a new charm recipe built from a Git reference.
+func (root *Root) CreateCharmRecipe(stub *CharmRecipeStub) (*CharmRecipe, error) {
+	params := Params{
+		"ws.op":        "new",
+		"owner":        stub.Owner.AbsLoc(),
+		"project":      stub.Project.AbsLoc(),
+		"name":         stub.Name,
+		"git_ref":      stub.GitRef.AbsLoc(),
+		"auto_build":   "false",
+		"store_upload": "false",
+	}
+	if stub.Description != "" {
+		params["description"] = stub.Description
+	}
+	if stub.AutoBuild {
+		params["auto_build"] = "true"
+	}
+	if stub.StoreUpload {
+		params["store_upload"] = "true"
+		params["store_name"] = stub.StoreName
+		params["store_channels"] = jsonList(stub.StoreChannels)
+	}
+	v, err := root.Location("/+charm-recipes").Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &CharmRecipe{v}, nil
+}
+
+// The CharmRecipe type represents a recipe for building a charm
+// out of a Git repository.
+type CharmRecipe struct {
+	*Value
+}
+
+// Name returns the recipe name.
+func (r *CharmRecipe) Name() string {
+	return r.StringField("name")
+}
+
+// Description returns the recipe description.
+func (r *CharmRecipe) Description() string {
+	return r.StringField("description")
+}
+
+// WebPage returns the URL for accessing this recipe in a browser.
+func (r *CharmRecipe) WebPage() string {
+	return r.StringField("web_link")
+}
+
+// Owner returns the Person or Team that owns the recipe.
+func (r *CharmRecipe) Owner() (Member, error) {
+	v, err := r.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// Project returns the project the charm is built for.
+func (r *CharmRecipe) Project() (*Project, error) {
+	v, err := r.Link("project_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{v}, nil
+}
+
+// GitRef returns the Git reference the charm is built from.
+func (r *CharmRecipe) GitRef() (*GitRef, error) {
+	v, err := r.Link("git_ref_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRef{v}, nil
+}
+
+// AutoBuild returns true if the charm is built automatically when
+// its source changes.
+func (r *CharmRecipe) AutoBuild() bool {
+	return r.BoolField("auto_build")
+}
+
+// StoreUpload returns true if successful builds of the charm are
+// uploaded to the store.
+func (r *CharmRecipe) StoreUpload() bool {
+	return r.BoolField("store_upload")
+}
+
+// StoreName returns the name the charm is registered under in the store.
+func (r *CharmRecipe) StoreName() string {
+	return r.StringField("store_name")
+}
+
+// StoreChannels returns the store channels uploaded builds are
+// released to.
+func (r *CharmRecipe) StoreChannels() []string {
+	return r.StringListField("store_channels")
+}
+
+// RequestBuilds requests builds of the charm for all its architectures.
+// The channels map holds the store channel to use for each snap needed
+// by the build, such as {"charmcraft": "stable"}, and may be nil.
+// Builds are created asynchronously. See the CharmRecipeBuildRequest type.
+func (r *CharmRecipe) RequestBuilds(channels map[string]string) (*CharmRecipeBuildRequest, error) {
+	params := Params{"ws.op": "requestBuilds"}
+	if len(channels) > 0 {
+		data, err := json.Marshal(channels)
+		if err != nil {
+			return nil, err
+		}
+		params["channels"] = string(data)
+	}
+	v, err := r.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &CharmRecipeBuildRequest{BuildRequest{v}}, nil
+}
+
+// Builds returns the list of all builds of the recipe.
+func (r *CharmRecipe) Builds() (*CharmRecipeBuildList, error) {
+	v, err := r.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CharmRecipeBuildList{v}, nil
+}
+
+// PendingBuilds returns the list of builds of the recipe that
+// haven't finished yet.
+func (r *CharmRecipe) PendingBuilds() (*CharmRecipeBuildList, error) {
+	v, err := r.Link("pending_builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CharmRecipeBuildList{v}, nil
+}
+
+// The CharmRecipeBuildRequest type represents a request for building
+// a charm for all its architectures. See the BuildRequest type for details.
+type CharmRecipeBuildRequest struct {
+	BuildRequest
+}
+
+// Builds returns the list of builds created by the request.
+func (req *CharmRecipeBuildRequest) Builds() (*CharmRecipeBuildList, error) {
+	v, err := req.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CharmRecipeBuildList{v}, nil
+}
+
+// The CharmRecipeBuild type describes a build of a charm for a
+// single architecture.
+type CharmRecipeBuild struct {
+	BuildFarmJob
+}
+
+// StoreUploadStatus returns the status of the upload of the build to the store.
+func (build *CharmRecipeBuild) StoreUploadStatus() string {
+	return build.StringField("store_upload_status")
+}
+
+// The CharmRecipeBuildList type represents a list of CharmRecipeBuild objects.
+type CharmRecipeBuildList struct {
+	*Value
+}
+
+// For iterates over the list of charm builds and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *CharmRecipeBuildList) For(f func(b *CharmRecipeBuild) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&CharmRecipeBuild{BuildFarmJob{v}})
+	})
+}
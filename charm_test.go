@@ -0,0 +1,190 @@
+package lpad_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestRootCharmRecipe(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "mycharm"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	owner := &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)}
+	project := &lpad.Project{lpad.NewValue(nil, "", "http://proj", nil)}
+	recipe, err := root.CharmRecipe(owner, project, "mycharm")
+	c.Assert(err, IsNil)
+	c.Assert(recipe.Name(), Equals, "mycharm")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/+charm-recipes")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getByName"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["project"], DeepEquals, []string{"http://proj"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"mycharm"})
+}
+
+func (s *ModelS) TestRootCreateCharmRecipe(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "mycharm"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	stub := &lpad.CharmRecipeStub{
+		Owner:         &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)},
+		Project:       &lpad.Project{lpad.NewValue(nil, "", "http://proj", nil)},
+		Name:          "mycharm",
+		GitRef:        &lpad.GitRef{lpad.NewValue(nil, "", "http://ref", nil)},
+		AutoBuild:     true,
+		StoreUpload:   true,
+		StoreName:     "my-charm",
+		StoreChannels: []string{"edge"},
+	}
+	recipe, err := root.CreateCharmRecipe(stub)
+	c.Assert(err, IsNil)
+	c.Assert(recipe.Name(), Equals, "mycharm")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/+charm-recipes")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"new"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["project"], DeepEquals, []string{"http://proj"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"mycharm"})
+	c.Assert(req.Form["git_ref"], DeepEquals, []string{"http://ref"})
+	c.Assert(req.Form["auto_build"], DeepEquals, []string{"true"})
+	c.Assert(req.Form["store_upload"], DeepEquals, []string{"true"})
+	c.Assert(req.Form["store_name"], DeepEquals, []string{"my-charm"})
+	c.Assert(req.Form["store_channels"], DeepEquals, []string{`["edge"]`})
+}
+
+func (s *ModelS) TestCharmRecipe(c *C) {
+	m := M{
+		"name":           "mycharm",
+		"description":    "Description",
+		"web_link":       "http://page",
+		"auto_build":     true,
+		"store_upload":   true,
+		"store_name":     "my-charm",
+		"store_channels": []interface{}{"edge"},
+		"owner_link":     testServer.URL + "/owner_link",
+		"project_link":   testServer.URL + "/project_link",
+		"git_ref_link":   testServer.URL + "/ref_link",
+	}
+	recipe := &lpad.CharmRecipe{lpad.NewValue(nil, "", "", m)}
+	c.Assert(recipe.Name(), Equals, "mycharm")
+	c.Assert(recipe.Description(), Equals, "Description")
+	c.Assert(recipe.WebPage(), Equals, "http://page")
+	c.Assert(recipe.AutoBuild(), Equals, true)
+	c.Assert(recipe.StoreUpload(), Equals, true)
+	c.Assert(recipe.StoreName(), Equals, "my-charm")
+	c.Assert(recipe.StoreChannels(), DeepEquals, []string{"edge"})
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "team", "is_team": true}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "proj"}`)
+	testServer.PrepareResponse(200, jsonType, `{"path": "refs/heads/main"}`)
+
+	owner, err := recipe.Owner()
+	c.Assert(err, IsNil)
+	_, ok := owner.(*lpad.Team)
+	c.Assert(ok, Equals, true)
+	project, err := recipe.Project()
+	c.Assert(err, IsNil)
+	c.Assert(project.Name(), Equals, "proj")
+	ref, err := recipe.GitRef()
+	c.Assert(err, IsNil)
+	c.Assert(ref.Path(), Equals, "refs/heads/main")
+
+	for _, path := range []string{"/owner_link", "/project_link", "/ref_link"} {
+		req := testServer.WaitRequest()
+		c.Assert(req.URL.Path, Equals, path)
+	}
+}
+
+func (s *ModelS) TestCharmRecipeRequestBuilds(c *C) {
+	recipe := &lpad.CharmRecipe{lpad.NewValue(nil, testServer.URL, testServer.URL+"/recipe", nil)}
+
+	testServer.PrepareResponse(200, jsonType, `{"status": "Pending"}`)
+	breq, err := recipe.RequestBuilds(map[string]string{"charmcraft": "stable"})
+	c.Assert(err, IsNil)
+	c.Assert(breq.Done(), Equals, false)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/recipe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"requestBuilds"})
+	c.Assert(req.Form["channels"], DeepEquals, []string{`{"charmcraft":"stable"}`})
+
+	breq = &lpad.CharmRecipeBuildRequest{lpad.BuildRequest{lpad.NewValue(nil, testServer.URL, testServer.URL+"/breq", M{"status": "Pending"})}}
+	testServer.PrepareResponse(200, jsonType, `{"status": "Failed", "error_message": "Oops"}`)
+	err = breq.Wait(time.Millisecond, nil)
+	c.Assert(err, IsNil)
+	c.Assert(breq.Status(), Equals, lpad.BuildRequestFailed)
+	c.Assert(breq.ErrorMessage(), Equals, "Oops")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/breq")
+}
+
+func (s *ModelS) TestCharmRecipeBuilds(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"title": "Build0",
+			"arch_tag": "amd64",
+			"buildstate": "Successfully built",
+			"build_log_url": "http://log0",
+			"store_upload_status": "Uploaded",
+			"datecreated": "2021-10-10T00:00:00",
+			"datebuilt": "2021-10-10T00:00:10",
+			"web_link": "http://page0"
+		}, {
+			"self_link": "http://self1",
+			"title": "Build1",
+			"arch_tag": "arm64",
+			"buildstate": "Failed to upload",
+			"upload_log_url": "http://upload_log1"
+		}]
+	}`
+	m := M{
+		"builds_collection_link":         testServer.URL + "/builds",
+		"pending_builds_collection_link": testServer.URL + "/pending",
+	}
+	recipe := &lpad.CharmRecipe{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := recipe.Builds()
+	c.Assert(err, IsNil)
+	var builds []*lpad.CharmRecipeBuild
+	list.For(func(b *lpad.CharmRecipeBuild) error {
+		builds = append(builds, b)
+		return nil
+	})
+	c.Assert(builds, HasLen, 2)
+	c.Assert(builds[0].Title(), Equals, "Build0")
+	c.Assert(builds[0].Arch(), Equals, "amd64")
+	c.Assert(builds[0].State(), Equals, lpad.BSSuccessfullyBuilt)
+	c.Assert(builds[0].BuildLogURL(), Equals, "http://log0")
+	c.Assert(builds[0].StoreUploadStatus(), Equals, "Uploaded")
+	c.Assert(builds[0].Created(), Equals, "2021-10-10T00:00:00")
+	c.Assert(builds[0].Finished(), Equals, "2021-10-10T00:00:10")
+	c.Assert(builds[0].WebPage(), Equals, "http://page0")
+	c.Assert(builds[1].State(), Equals, lpad.BSFailedToUpload)
+	c.Assert(builds[1].UploadLogURL(), Equals, "http://upload_log1")
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/builds")
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = recipe.PendingBuilds()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/pending")
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	build := &lpad.CharmRecipeBuild{lpad.BuildFarmJob{lpad.NewValue(nil, testServer.URL, testServer.URL+"/build", nil)}}
+	err = build.Retry()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"retry"})
+}
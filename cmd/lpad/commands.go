@@ -0,0 +1,678 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/canonical/lpad"
+)
+
+// choose returns the option matching value, ignoring case, spaces and
+// punctuation, so that "fix-released" selects "Fix Released".
+func choose(what, value string, options ...string) (string, error) {
+	for _, option := range options {
+		if normalize(option) == normalize(value) {
+			return option, nil
+		}
+	}
+	return "", fmt.Errorf("unknown %s %q; expected one of: %s", what, value, strings.Join(options, ", "))
+}
+
+func normalize(s string) string {
+	var r []rune
+	for _, c := range s {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			r = append(r, unicode.ToLower(c))
+		}
+	}
+	return string(r)
+}
+
+// apiLoc converts a Launchpad URL into a location in the API. URLs
+// in the web interface, such as https://code.launchpad.net/~joe/+merge/1,
+// are mapped to the respective path in the API.
+func apiLoc(s string) (string, error) {
+	if !strings.HasPrefix(s, "http://") && !strings.HasPrefix(s, "https://") {
+		return s, nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(u.Host, "api.") {
+		return s, nil
+	}
+	return u.Path, nil
+}
+
+func bugId(arg string) (int, error) {
+	id, err := strconv.Atoi(strings.TrimPrefix(arg, "#"))
+	if err != nil {
+		return 0, fmt.Errorf("invalid bug number: %q", arg)
+	}
+	return id, nil
+}
+
+func runLogin(ctx *context) error {
+	fs := ctx.flags("login", "")
+	if err := ctx.parse(fs, 0, 0); err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	me, err := root.Me()
+	if err != nil {
+		return err
+	}
+	return ctx.out.record([]string{"name", "display name"}, me.Name(), me.DisplayName())
+}
+
+var bugStatuses = []string{
+	string(lpad.StNew), string(lpad.StIncomplete), string(lpad.StOpinion),
+	string(lpad.StInvalid), string(lpad.StWontFix), string(lpad.StExpired),
+	string(lpad.StConfirmed), string(lpad.StTriaged), string(lpad.StInProgress),
+	string(lpad.StFixCommitted), string(lpad.StFixReleased),
+}
+
+func runBugShow(ctx *context) error {
+	fs := ctx.flags("bug show", "<bug number>")
+	if err := ctx.parse(fs, 1, 1); err != nil {
+		return err
+	}
+	id, err := bugId(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	bug, err := root.Bug(id)
+	if err != nil {
+		return err
+	}
+	tasks, err := bug.Tasks()
+	if err != nil {
+		return err
+	}
+	columns := []string{"target", "status", "importance"}
+	var rows [][]interface{}
+	err = tasks.For(func(task *lpad.BugTask) error {
+		rows = append(rows, []interface{}{task.TargetName(), string(task.Status()), string(task.Importance())})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	keys := []string{"id", "title", "tags", "private", "url", "description"}
+	values := []interface{}{bug.Id(), bug.Title(), bug.Tags(), bug.Private(), bug.WebPage(), bug.Description()}
+	if ctx.out.json {
+		m := object(keys, values)
+		var list []interface{}
+		for _, row := range rows {
+			list = append(list, object(columns, row))
+		}
+		m["tasks"] = list
+		return ctx.out.printJSON(m)
+	}
+	if err := ctx.out.record(keys, values...); err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.stdout)
+	return ctx.out.table(columns, rows)
+}
+
+func runBugCreate(ctx *context) error {
+	fs := ctx.flags("bug create", "-project <name> -title <title> -description <text>")
+	project := fs.String("project", "", "the project the bug affects")
+	title := fs.String("title", "", "the bug summary")
+	description := fs.String("description", "", "the bug description")
+	tags := fs.String("tags", "", "comma-separated list of tags")
+	private := fs.Bool("private", false, "report the bug as private")
+	security := fs.Bool("security", false, "report the bug as security related")
+	if err := ctx.parse(fs, 0, 0); err != nil {
+		return err
+	}
+	if *project == "" || *title == "" || *description == "" {
+		fs.Usage()
+		return errUsage
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	target, err := root.Project(*project)
+	if err != nil {
+		return err
+	}
+	stub := &lpad.BugStub{
+		Title:           *title,
+		Description:     *description,
+		Target:          target,
+		Private:         *private,
+		SecurityRelated: *security,
+	}
+	if *tags != "" {
+		stub.Tags = strings.Split(*tags, ",")
+	}
+	bug, err := root.CreateBug(stub)
+	if err != nil {
+		return err
+	}
+	return ctx.out.record([]string{"id", "url"}, bug.Id(), bug.WebPage())
+}
+
+func runBugSetStatus(ctx *context) error {
+	fs := ctx.flags("bug set-status", "[-target <name>] <bug number> <status>")
+	target := fs.String("target", "", "the project or package of the task to change")
+	if err := ctx.parse(fs, 2, 2); err != nil {
+		return err
+	}
+	id, err := bugId(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	status, err := choose("bug status", fs.Arg(1), bugStatuses...)
+	if err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	bug, err := root.Bug(id)
+	if err != nil {
+		return err
+	}
+	tasks, err := bug.Tasks()
+	if err != nil {
+		return err
+	}
+	var matches []*lpad.BugTask
+	var targets []string
+	err = tasks.For(func(task *lpad.BugTask) error {
+		targets = append(targets, task.TargetName())
+		if *target == "" || task.TargetName() == *target {
+			matches = append(matches, task)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(matches) != 1 {
+		return fmt.Errorf("bug %d has tasks on %s; select one with -target", id, strings.Join(targets, ", "))
+	}
+	task := matches[0]
+	task.SetStatus(lpad.BugStatus(status))
+	if err := task.Patch(); err != nil {
+		return err
+	}
+	return ctx.out.record([]string{"target", "status"}, task.TargetName(), status)
+}
+
+func runBugComment(ctx *context) error {
+	fs := ctx.flags("bug comment", "[-subject <subject>] <bug number> <comment>")
+	subject := fs.String("subject", "", "the comment subject")
+	if err := ctx.parse(fs, 2, 2); err != nil {
+		return err
+	}
+	id, err := bugId(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	bug, err := root.Bug(id)
+	if err != nil {
+		return err
+	}
+	return bug.AddComment(*subject, fs.Arg(1))
+}
+
+var proposalStatuses = []string{
+	string(lpad.StWorkInProgress), string(lpad.StNeedsReview), string(lpad.StApproved),
+	string(lpad.StRejected), string(lpad.StMerged), string(lpad.StFailedToMerge),
+	string(lpad.StQueued), string(lpad.StSuperseded),
+}
+
+var proposalVotes = []string{
+	string(lpad.VoteApprove), string(lpad.VoteNeedsFixing), string(lpad.VoteNeedsInfo),
+	string(lpad.VoteAbstain), string(lpad.VoteDisapprove), string(lpad.VoteResubmit),
+}
+
+func runMPList(ctx *context) error {
+	fs := ctx.flags("mp list", "[-status <status>] [-reviews] [person]")
+	status := fs.String("status", "", "only list proposals with this status")
+	reviews := fs.Bool("reviews", false, "list proposals the person was asked to review")
+	if err := ctx.parse(fs, 0, 1); err != nil {
+		return err
+	}
+	if *status != "" {
+		s, err := choose("proposal status", *status, proposalStatuses...)
+		if err != nil {
+			return err
+		}
+		*status = s
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	var person *lpad.Person
+	if fs.NArg() == 1 {
+		person = &lpad.Person{Value: root.Location("/~" + fs.Arg(0))}
+	} else if person, err = root.Me(); err != nil {
+		return err
+	}
+	var list *lpad.MergeProposalList
+	if *reviews {
+		list, err = person.RequestedReviews(lpad.MergeProposalStatus(*status))
+	} else {
+		list, err = person.MergeProposals(lpad.MergeProposalStatus(*status))
+	}
+	if err != nil {
+		return err
+	}
+	var rows [][]interface{}
+	err = list.For(func(mp *lpad.MergeProposal) error {
+		rows = append(rows, []interface{}{string(mp.Status()), mp.WebPage()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.out.table([]string{"status", "url"}, rows)
+}
+
+func runMPPropose(ctx *context) error {
+	fs := ctx.flags("mp propose", "-target <branch> [options] <source branch>")
+	target := fs.String("target", "", "the branch to merge into, such as lp:project")
+	description := fs.String("description", "", "the description of the changes")
+	message := fs.String("commit-message", "", "the commit message to use when merging")
+	wip := fs.Bool("wip", false, "mark the proposal as work in progress")
+	if err := ctx.parse(fs, 1, 1); err != nil {
+		return err
+	}
+	if *target == "" {
+		fs.Usage()
+		return errUsage
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	source, err := root.Branch(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	targetBranch, err := root.Branch(*target)
+	if err != nil {
+		return err
+	}
+	mp, err := source.ProposeMerge(&lpad.MergeStub{
+		Description:   *description,
+		CommitMessage: *message,
+		NeedsReview:   !*wip,
+		Target:        targetBranch,
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.out.record([]string{"status", "url"}, string(mp.Status()), mp.WebPage())
+}
+
+func runMPVote(ctx *context) error {
+	fs := ctx.flags("mp vote", "[-comment <text>] <proposal url> <vote>")
+	comment := fs.String("comment", "", "the review comment")
+	subject := fs.String("subject", "", "the review subject")
+	if err := ctx.parse(fs, 2, 2); err != nil {
+		return err
+	}
+	loc, err := apiLoc(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	vote, err := choose("vote", fs.Arg(1), proposalVotes...)
+	if err != nil {
+		return err
+	}
+	if *subject == "" {
+		*subject = "Review: " + vote
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	v, err := root.Location(loc).Get(nil)
+	if err != nil {
+		return err
+	}
+	mp := &lpad.MergeProposal{Value: v}
+	return mp.AddComment(*subject, *comment, lpad.ProposalVote(vote), "")
+}
+
+// ppa returns the named PPA owned by the given person or team.
+func ppa(root *lpad.Root, owner, name string) (*lpad.Archive, error) {
+	// Teams own PPAs too, so load the owner as a plain person.
+	v, err := root.Location("/~" + owner).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	person := &lpad.Person{Value: v}
+	list, err := person.PPAs()
+	if err != nil {
+		return nil, err
+	}
+	var found *lpad.Archive
+	err = list.For(func(a *lpad.Archive) error {
+		if a.Name() == name {
+			found = a
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, fmt.Errorf("~%s has no PPA named %q", owner, name)
+	}
+	return found, nil
+}
+
+var buildStates = []string{
+	string(lpad.BSNeedsBuilding), string(lpad.BSSuccessfullyBuilt), string(lpad.BSFailedToBuild),
+	string(lpad.BSDependencyWait), string(lpad.BSChrootProblem), string(lpad.BSBuildForSupersededSource),
+	string(lpad.BSCurrentlyBuilding), string(lpad.BSFailedToUpload), string(lpad.BSCurrentlyUploading),
+}
+
+func runBuildList(ctx *context) error {
+	fs := ctx.flags("build list", "[-state <state>] [-source <package>] <owner> <ppa>")
+	state := fs.String("state", "", "only list builds in this state")
+	source := fs.String("source", "", "only list builds of this source package")
+	if err := ctx.parse(fs, 2, 2); err != nil {
+		return err
+	}
+	if *state != "" {
+		s, err := choose("build state", *state, buildStates...)
+		if err != nil {
+			return err
+		}
+		*state = s
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	archive, err := ppa(root, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	list, err := archive.Builds(lpad.BuildState(*state), *source)
+	if err != nil {
+		return err
+	}
+	var rows [][]interface{}
+	err = list.For(func(b *lpad.Build) error {
+		rows = append(rows, []interface{}{b.Title(), string(b.State()), b.WebPage()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.out.table([]string{"title", "state", "url"}, rows)
+}
+
+func runBuildRetry(ctx *context) error {
+	fs := ctx.flags("build retry", "<build url>")
+	if err := ctx.parse(fs, 1, 1); err != nil {
+		return err
+	}
+	loc, err := apiLoc(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	v, err := root.Location(loc).Get(nil)
+	if err != nil {
+		return err
+	}
+	build := &lpad.Build{Value: v}
+	return build.Retry()
+}
+
+var publishStatuses = []string{
+	string(lpad.PubPending), string(lpad.PubPublished), string(lpad.PubSuperseded),
+	string(lpad.PubDeleted), string(lpad.PubObsolete),
+}
+
+func runPPAPublications(ctx *context) error {
+	fs := ctx.flags("ppa publications", "[-status <status>] <owner> <ppa> <source package>")
+	status := fs.String("status", string(lpad.PubPublished), "only list publications with this status")
+	if err := ctx.parse(fs, 3, 3); err != nil {
+		return err
+	}
+	s, err := choose("publication status", *status, publishStatuses...)
+	if err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	archive, err := ppa(root, fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	list, err := archive.Publication(fs.Arg(2), lpad.PublishStatus(s))
+	if err != nil {
+		return err
+	}
+	var rows [][]interface{}
+	err = list.For(func(p *lpad.Publication) error {
+		rows = append(rows, []interface{}{p.PackageName(), p.PackageVersion(), p.Component()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return ctx.out.table([]string{"package", "version", "component"}, rows)
+}
+
+func runPerson(ctx *context) error {
+	fs := ctx.flags("person", "<name>")
+	if err := ctx.parse(fs, 1, 1); err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	member, err := root.Member(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	keys := []string{"name", "display name", "url"}
+	values := []interface{}{member.Name(), member.DisplayName(), member.WebPage()}
+	if person, ok := member.(*lpad.Person); ok {
+		keys = append(keys, "karma", "created")
+		values = append(values, person.Karma(), person.DateCreated())
+	} else {
+		keys = append(keys, "team")
+		values = append(values, true)
+	}
+	return ctx.out.record(keys, values...)
+}
+
+func runTeam(ctx *context) error {
+	fs := ctx.flags("team", "<name>")
+	if err := ctx.parse(fs, 1, 1); err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	member, err := root.Member(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	team, ok := member.(*lpad.Team)
+	if !ok {
+		return fmt.Errorf("%s is not a team", fs.Arg(0))
+	}
+	members, err := team.Members(lpad.MembershipApproved)
+	if err != nil {
+		return err
+	}
+	columns := []string{"name", "display name"}
+	var rows [][]interface{}
+	err = members.For(func(m lpad.Member) error {
+		rows = append(rows, []interface{}{m.Name(), m.DisplayName()})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	keys := []string{"name", "display name", "url"}
+	values := []interface{}{team.Name(), team.DisplayName(), team.WebPage()}
+	if ctx.out.json {
+		m := object(keys, values)
+		var list []interface{}
+		for _, row := range rows {
+			list = append(list, object(columns, row))
+		}
+		m["members"] = list
+		return ctx.out.printJSON(m)
+	}
+	if err := ctx.out.record(keys, values...); err != nil {
+		return err
+	}
+	fmt.Fprintln(ctx.stdout)
+	return ctx.out.table(columns, rows)
+}
+
+// params parses arguments in the key=value form.
+func params(args []string) (lpad.Params, error) {
+	params := lpad.Params{}
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid parameter %q; expected key=value", arg)
+		}
+		params[arg[:i]] = arg[i+1:]
+	}
+	return params, nil
+}
+
+// fields parses arguments in the key=value form, holding a string, or
+// in the key:=json form, holding a JSON number, boolean, string or list
+// of strings, into values accepted by lpad.Value.SetField.
+func fields(args []string) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	for _, arg := range args {
+		i := strings.Index(arg, "=")
+		if i <= 0 || i == 1 && arg[0] == ':' {
+			return nil, fmt.Errorf("invalid field %q; expected key=string or key:=json", arg)
+		}
+		if arg[i-1] != ':' {
+			fields[arg[:i]] = arg[i+1:]
+			continue
+		}
+		key := arg[:i-1]
+		var value interface{}
+		if err := json.Unmarshal([]byte(arg[i+1:]), &value); err != nil {
+			return nil, fmt.Errorf("invalid JSON value for field %q: %v", key, err)
+		}
+		switch v := value.(type) {
+		case string, bool:
+			fields[key] = v
+		case float64:
+			if v != float64(int(v)) {
+				return nil, fmt.Errorf("unsupported value for field %q: only integer numbers are supported", key)
+			}
+			fields[key] = int(v)
+		case []interface{}:
+			list := []string{}
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("unsupported value for field %q: only lists of strings are supported", key)
+				}
+				list = append(list, s)
+			}
+			fields[key] = list
+		default:
+			return nil, fmt.Errorf("unsupported value for field %q: %s", key, arg[i+1:])
+		}
+	}
+	return fields, nil
+}
+
+func runRaw(ctx *context, method string) error {
+	usage := "<path or url> [key=value ...]"
+	if method == "patch" {
+		usage = "<path or url> [key=string | key:=json ...]"
+	}
+	fs := ctx.flags(method, usage)
+	min := 1
+	if method != "get" {
+		min = 2
+	}
+	if err := ctx.parse(fs, min, -1); err != nil {
+		return err
+	}
+	loc, err := apiLoc(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var p lpad.Params
+	var f map[string]interface{}
+	if method == "patch" {
+		f, err = fields(fs.Args()[1:])
+	} else {
+		p, err = params(fs.Args()[1:])
+	}
+	if err != nil {
+		return err
+	}
+	root, err := ctx.login()
+	if err != nil {
+		return err
+	}
+	var v *lpad.Value
+	switch method {
+	case "get":
+		v, err = root.Location(loc).Get(p)
+	case "post":
+		v, err = root.Location(loc).Post(p)
+	case "patch":
+		v, err = root.Location(loc).Get(nil)
+		if err != nil {
+			return err
+		}
+		for key, value := range f {
+			v.SetField(key, value)
+		}
+		err = v.Patch()
+	}
+	if err != nil {
+		return err
+	}
+	return ctx.out.raw(v.Map())
+}
+
+func runGet(ctx *context) error   { return runRaw(ctx, "get") }
+func runPost(ctx *context) error  { return runRaw(ctx, "post") }
+func runPatch(ctx *context) error { return runRaw(ctx, "patch") }
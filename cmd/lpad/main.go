@@ -0,0 +1,197 @@
+// The lpad command offers access to common Launchpad tasks from the
+// command line, built on top of the lpad package.
+//
+// Run "lpad help" for the list of commands. The first command run opens
+// a browser for the user to authorize access to Launchpad, and the
+// credentials obtained are reused afterwards. For example:
+//
+//	lpad login
+//	lpad bug show 123456
+//	lpad -json build list -state "Failed to build" joe ppa
+//
+// The patch command takes fields as key=value for strings, and as
+// key:=value for JSON numbers, booleans and lists of strings:
+//
+//	lpad patch /~joe display_name=Joe hide_email_addresses:=true
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/canonical/lpad"
+)
+
+// The context type holds the state shared by all commands.
+type context struct {
+	args   []string
+	stdout io.Writer
+	out    *output
+
+	api  lpad.APIBase
+	auth lpad.Auth
+	root *lpad.Root
+}
+
+// login returns the root of the Launchpad API, logging in on first use.
+func (ctx *context) login() (*lpad.Root, error) {
+	if ctx.root == nil {
+		root, err := lpad.Login(ctx.api, ctx.auth)
+		if err != nil {
+			return nil, err
+		}
+		ctx.root = root
+	}
+	return ctx.root, nil
+}
+
+// flags returns a flag set for the named command, which prints usage
+// as the command help.
+func (ctx *context) flags(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(ctx.stdout)
+	fs.Usage = func() {
+		fmt.Fprintf(ctx.stdout, "Usage: lpad %s %s\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses the command arguments with fs, and verifies that the
+// number of positional arguments left is within min and max. A max
+// of -1 means there's no maximum.
+func (ctx *context) parse(fs *flag.FlagSet, min, max int) error {
+	if err := fs.Parse(ctx.args); err != nil {
+		return err
+	}
+	if fs.NArg() < min || max >= 0 && fs.NArg() > max {
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+var errUsage = errors.New("invalid arguments")
+
+type command struct {
+	summary string
+	run     func(ctx *context) error
+	subs    map[string]*command
+}
+
+var commands = map[string]*command{
+	"login": {summary: "authorize access to Launchpad", run: runLogin},
+	"bug": {summary: "show and change bugs", subs: map[string]*command{
+		"show":       {summary: "show a bug and its tasks", run: runBugShow},
+		"create":     {summary: "report a new bug", run: runBugCreate},
+		"set-status": {summary: "change the status of a bug task", run: runBugSetStatus},
+		"comment":    {summary: "add a comment to a bug", run: runBugComment},
+	}},
+	"mp": {summary: "list and change merge proposals", subs: map[string]*command{
+		"list":    {summary: "list the merge proposals of a person", run: runMPList},
+		"propose": {summary: "propose a branch for merging", run: runMPPropose},
+		"vote":    {summary: "review a merge proposal", run: runMPVote},
+	}},
+	"build": {summary: "list and retry package builds", subs: map[string]*command{
+		"list":  {summary: "list the builds in a PPA", run: runBuildList},
+		"retry": {summary: "retry a failed build", run: runBuildRetry},
+	}},
+	"ppa": {summary: "inspect personal package archives", subs: map[string]*command{
+		"publications": {summary: "list the publications of a source package", run: runPPAPublications},
+	}},
+	"person": {summary: "show a person or team", run: runPerson},
+	"team":   {summary: "show a team and its members", run: runTeam},
+	"get":    {summary: "GET a resource and print it", run: runGet},
+	"post":   {summary: "POST parameters to a resource", run: runPost},
+	"patch":  {summary: "PATCH fields of a resource", run: runPatch},
+}
+
+func usage(w io.Writer, prefix string, cmds map[string]*command) {
+	var names []string
+	for name := range cmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "Commands:\n")
+	for _, name := range names {
+		fmt.Fprintf(w, "    %-24s %s\n", strings.TrimSpace(prefix+" "+name), cmds[name].summary)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	ctx := &context{stdout: stdout}
+	fs := flag.NewFlagSet("lpad", flag.ContinueOnError)
+	fs.SetOutput(stdout)
+	staging := fs.Bool("staging", false, "use the Launchpad staging server")
+	api := fs.String("api", "", "use the Launchpad API at `url`")
+	anon := fs.Bool("anon", false, "access Launchpad anonymously")
+	jsonOut := fs.Bool("json", false, "print results as JSON rather than tables")
+	fs.Usage = func() {
+		fmt.Fprintf(stdout, "Usage: lpad [options] <command> [arguments]\n\nOptions:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(stdout, "\n")
+		usage(stdout, "", commands)
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	ctx.api = lpad.Production
+	if *staging {
+		ctx.api = lpad.Staging
+	}
+	if *api != "" {
+		ctx.api = lpad.APIBase(*api)
+	}
+	auth := &lpad.ConsoleOAuth{Consumer: "lpad", Anonymous: *anon}
+	if os.Getenv("LPAD_OAUTH_TOKEN") != "" {
+		// Credentials provided by the environment, as in CI systems.
+		auth.Store = lpad.EnvStore{}
+	}
+	ctx.auth = auth
+	ctx.out = &output{w: stdout, json: *jsonOut}
+
+	args = fs.Args()
+	cmds := commands
+	prefix := ""
+	for {
+		if len(args) == 0 || args[0] == "help" {
+			if prefix == "" {
+				fs.Usage()
+			} else {
+				usage(stdout, prefix, cmds)
+			}
+			return nil
+		}
+		cmd, ok := cmds[args[0]]
+		if !ok {
+			return fmt.Errorf("unknown command: %s", strings.TrimSpace(prefix+" "+args[0]))
+		}
+		prefix = strings.TrimSpace(prefix + " " + args[0])
+		args = args[1:]
+		if cmd.subs == nil {
+			ctx.args = args
+			return cmd.run(ctx)
+		}
+		cmds = cmd.subs
+	}
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdout)
+	if err == flag.ErrHelp {
+		return
+	}
+	if err == errUsage {
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
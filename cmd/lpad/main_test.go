@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&CommandS{})
+
+type request struct {
+	method, path, body string
+}
+
+type CommandS struct {
+	server    *httptest.Server
+	responses map[string]string
+	requests  []request
+	home      string
+}
+
+func (s *CommandS) SetUpTest(c *C) {
+	s.responses = make(map[string]string)
+	s.requests = nil
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		s.requests = append(s.requests, request{req.Method, req.URL.RequestURI(), string(body)})
+		data, ok := s.responses[req.Method+" "+req.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, req)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(strings.Replace(data, "$URL", s.server.URL, -1)))
+	}))
+	// Credentials are stored in the home directory.
+	s.home = os.Getenv("HOME")
+	os.Setenv("HOME", c.MkDir())
+}
+
+func (s *CommandS) TearDownTest(c *C) {
+	s.server.Close()
+	os.Setenv("HOME", s.home)
+}
+
+func (s *CommandS) run(c *C, args ...string) (string, error) {
+	var stdout bytes.Buffer
+	args = append([]string{"-anon", "-api", s.server.URL + "/"}, args...)
+	err := run(args, &stdout)
+	return stdout.String(), err
+}
+
+const bugResponse = `{
+	"id": 1,
+	"title": "Broken",
+	"description": "It's\nbroken.",
+	"tags": ["crash"],
+	"private": false,
+	"web_link": "https://bugs.launchpad.net/bugs/1",
+	"bug_tasks_collection_link": "$URL/bugs/1/bug_tasks"
+}`
+
+const bugTasksResponse = `{"total_size": 2, "start": 0, "entries": [
+	{"self_link": "$URL/foo/+bug/1", "bug_target_name": "foo", "status": "New", "importance": "High"},
+	{"self_link": "$URL/bar/+bug/1", "bug_target_name": "bar", "status": "Triaged", "importance": "Low"}
+]}`
+
+func (s *CommandS) TestHelp(c *C) {
+	out, err := s.run(c, "help")
+	c.Assert(err, IsNil)
+	c.Assert(out, Matches, "(?s)Usage: lpad .*bug .*show and change bugs.*team .*")
+
+	out, err = s.run(c, "bug")
+	c.Assert(err, IsNil)
+	c.Assert(out, Matches, "(?s)Commands:\n.*bug comment .*bug create .*bug set-status .*bug show .*")
+}
+
+func (s *CommandS) TestUnknownCommand(c *C) {
+	_, err := s.run(c, "bug", "frob")
+	c.Assert(err, ErrorMatches, "unknown command: bug frob")
+}
+
+func (s *CommandS) TestUsageError(c *C) {
+	out, err := s.run(c, "bug", "show")
+	c.Assert(err, Equals, errUsage)
+	c.Assert(out, Equals, "Usage: lpad bug show <bug number>\n")
+}
+
+func (s *CommandS) TestBugShow(c *C) {
+	s.responses["GET /bugs/1"] = bugResponse
+	s.responses["GET /bugs/1/bug_tasks"] = bugTasksResponse
+	out, err := s.run(c, "bug", "show", "1")
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, ""+
+		"id:          1\n"+
+		"title:       Broken\n"+
+		"tags:        crash\n"+
+		"private:     false\n"+
+		"url:         https://bugs.launchpad.net/bugs/1\n"+
+		"description: It's broken.\n"+
+		"\n"+
+		"TARGET  STATUS   IMPORTANCE\n"+
+		"foo     New      High\n"+
+		"bar     Triaged  Low\n")
+}
+
+func (s *CommandS) TestBugShowJSON(c *C) {
+	s.responses["GET /bugs/1"] = bugResponse
+	s.responses["GET /bugs/1/bug_tasks"] = bugTasksResponse
+	out, err := s.run(c, "-json", "bug", "show", "1")
+	c.Assert(err, IsNil)
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(out), &m), IsNil)
+	c.Assert(m["id"], Equals, 1.0)
+	c.Assert(m["description"], Equals, "It's\nbroken.")
+	c.Assert(m["tasks"], DeepEquals, []interface{}{
+		map[string]interface{}{"target": "foo", "status": "New", "importance": "High"},
+		map[string]interface{}{"target": "bar", "status": "Triaged", "importance": "Low"},
+	})
+}
+
+func (s *CommandS) TestBugSetStatus(c *C) {
+	s.responses["GET /bugs/1"] = bugResponse
+	s.responses["GET /bugs/1/bug_tasks"] = bugTasksResponse
+	s.responses["PATCH /bar/+bug/1"] = `{}`
+
+	_, err := s.run(c, "bug", "set-status", "1", "fix-released")
+	c.Assert(err, ErrorMatches, "bug 1 has tasks on foo, bar; select one with -target")
+
+	_, err = s.run(c, "bug", "set-status", "1", "fixed")
+	c.Assert(err, ErrorMatches, `unknown bug status "fixed"; expected one of: New, .*`)
+
+	s.requests = nil
+	out, err := s.run(c, "bug", "set-status", "-target", "bar", "1", "fix-released")
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "target: bar\nstatus: Fix Released\n")
+	c.Assert(s.requests[2], Equals, request{"PATCH", "/bar/+bug/1", `{"status":"Fix Released"}`})
+}
+
+func (s *CommandS) TestBugComment(c *C) {
+	s.responses["GET /bugs/1"] = bugResponse
+	s.responses["POST /bugs/1"] = `{}`
+	_, err := s.run(c, "bug", "comment", "#1", "Me too.")
+	c.Assert(err, IsNil)
+	c.Assert(s.requests[1].method, Equals, "POST")
+	c.Assert(s.requests[1].body, Matches, ".*ws.op=newMessage.*")
+	c.Assert(s.requests[1].body, Matches, ".*content=Me\\+too.*")
+}
+
+func (s *CommandS) TestMPVote(c *C) {
+	s.responses["GET /~joe/foo/fix/+merge/2"] = `{"queue_status": "Needs review"}`
+	s.responses["POST /~joe/foo/fix/+merge/2"] = `{}`
+	_, err := s.run(c, "mp", "vote", "-comment", "LGTM", "https://code.launchpad.net/~joe/foo/fix/+merge/2", "approve")
+	c.Assert(err, IsNil)
+	c.Assert(s.requests[1].method, Equals, "POST")
+	c.Assert(s.requests[1].body, Matches, ".*vote=Approve.*")
+	c.Assert(s.requests[1].body, Matches, ".*subject=Review%3A\\+Approve.*")
+}
+
+func (s *CommandS) TestBuildList(c *C) {
+	s.responses["GET /~joe"] = `{"ppas_collection_link": "$URL/~joe/ppas"}`
+	s.responses["GET /~joe/ppas"] = `{"total_size": 1, "start": 0, "entries": [{"name": "ppa", "self_link": "$URL/~joe/+archive/ppa"}]}`
+	s.responses["GET /~joe/+archive/ppa?build_state=Failed+to+build&ws.op=getBuildRecords"] = `{"total_size": 1, "start": 0, "entries": [
+		{"title": "i386 build of foo", "buildstate": "Failed to build", "web_link": "https://launchpad.net/build/1"}
+	]}`
+	out, err := s.run(c, "build", "list", "-state", "failed to build", "joe", "ppa")
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, ""+
+		"TITLE              STATE            URL\n"+
+		"i386 build of foo  Failed to build  https://launchpad.net/build/1\n")
+
+	_, err = s.run(c, "build", "list", "joe", "stable")
+	c.Assert(err, ErrorMatches, `~joe has no PPA named "stable"`)
+}
+
+func (s *CommandS) TestPerson(c *C) {
+	s.responses["GET /~joe"] = `{"name": "joe", "display_name": "Joe", "web_link": "https://launchpad.net/~joe", "karma": 42}`
+	out, err := s.run(c, "-json", "person", "joe")
+	c.Assert(err, IsNil)
+	var m map[string]interface{}
+	c.Assert(json.Unmarshal([]byte(out), &m), IsNil)
+	c.Assert(m["display_name"], Equals, "Joe")
+	c.Assert(m["karma"], Equals, 42.0)
+}
+
+func (s *CommandS) TestGet(c *C) {
+	s.responses["GET /~joe?ws.op=getFoo"] = `{"name": "joe", "karma": 42}`
+	out, err := s.run(c, "get", "/~joe", "ws.op=getFoo")
+	c.Assert(err, IsNil)
+	c.Assert(out, Equals, "karma: 42\nname:  joe\n")
+
+	_, err = s.run(c, "get", "/~joe", "getFoo")
+	c.Assert(err, ErrorMatches, `invalid parameter "getFoo"; expected key=value`)
+}
+
+func (s *CommandS) TestPatch(c *C) {
+	s.responses["GET /~joe"] = `{"name": "joe"}`
+	s.responses["PATCH /~joe"] = `{}`
+	_, err := s.run(c, "patch", "/~joe", "hide_email_addresses:=true", "karma:=1", "name=1", "nick=true", "tags:=[\"a\"]")
+	c.Assert(err, IsNil)
+	c.Assert(s.requests[1], Equals, request{"PATCH", "/~joe", `{"hide_email_addresses":true,"karma":1,"name":"1","nick":"true","tags":["a"]}`})
+
+	for _, t := range []struct{ arg, err string }{
+		{"name", `invalid field "name"; expected key=string or key:=json`},
+		{":=1", `invalid field ":=1"; expected key=string or key:=json`},
+		{"karma:=x", `invalid JSON value for field "karma": .*`},
+		{"karma:=1.5", `unsupported value for field "karma": only integer numbers are supported`},
+		{"tags:=[1]", `unsupported value for field "tags": only lists of strings are supported`},
+		{"owner_link:=null", `unsupported value for field "owner_link": null`},
+	} {
+		_, err := s.run(c, "patch", "/~joe", t.arg)
+		c.Check(err, ErrorMatches, t.err)
+	}
+}
+
+func (s *CommandS) TestAPILoc(c *C) {
+	for _, t := range []struct{ in, out string }{
+		{"/~joe", "/~joe"},
+		{"https://code.launchpad.net/~joe/foo/bar/+merge/1", "/~joe/foo/bar/+merge/1"},
+		{"https://api.launchpad.net/devel/~joe", "https://api.launchpad.net/devel/~joe"},
+	} {
+		loc, err := apiLoc(t.in)
+		c.Check(err, IsNil)
+		c.Check(loc, Equals, t.out)
+	}
+}
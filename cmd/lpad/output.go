@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// The output type prints command results either as aligned text
+// or as JSON.
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// record prints a single object, with one "key: value" line per field,
+// or as a JSON object.
+func (o *output) record(keys []string, values ...interface{}) error {
+	if o.json {
+		return o.printJSON(object(keys, values))
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 4, 1, ' ', 0)
+	for i, key := range keys {
+		fmt.Fprintf(tw, "%s:\t%s\n", key, text(values[i]))
+	}
+	return tw.Flush()
+}
+
+// table prints a list of objects as rows under the given columns,
+// or as a JSON list of objects.
+func (o *output) table(columns []string, rows [][]interface{}) error {
+	if o.json {
+		list := make([]interface{}, 0, len(rows))
+		for _, row := range rows {
+			list = append(list, object(columns, row))
+		}
+		return o.printJSON(list)
+	}
+	tw := tabwriter.NewWriter(o.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(tw, "%s\n", strings.ToUpper(strings.Join(columns, "\t")))
+	for _, row := range rows {
+		var cells []string
+		for _, v := range row {
+			cells = append(cells, text(v))
+		}
+		fmt.Fprintf(tw, "%s\n", strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// raw prints a value as returned by Launchpad, with one line per
+// field sorted by key, or as JSON.
+func (o *output) raw(m map[string]interface{}) error {
+	if o.json {
+		return o.printJSON(m)
+	}
+	var keys []string
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var values []interface{}
+	for _, key := range keys {
+		values = append(values, m[key])
+	}
+	return o.record(keys, values...)
+}
+
+func (o *output) printJSON(v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(o.w, "%s\n", data)
+	return err
+}
+
+func object(keys []string, values []interface{}) map[string]interface{} {
+	m := make(map[string]interface{})
+	for i, key := range keys {
+		m[strings.Replace(key, " ", "_", -1)] = values[i]
+	}
+	return m
+}
+
+// text returns v formatted for a table cell, with strings kept in
+// a single line.
+func text(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case []string:
+		return strings.Join(v, " ")
+	case nil:
+		return ""
+	case map[string]interface{}, []interface{}:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
@@ -0,0 +1,569 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/canonical/lpad"
+)
+
+const pageSuffix = "-page-resource"
+
+// The pkgNames type holds the names declared at the top level of the
+// lpad package the generated code is added to.
+type pkgNames struct {
+	// all holds every declared name, so generated names don't clash.
+	all map[string]bool
+
+	// wrappers holds the names of types wrapping a *Value, such as
+	// Person, which generated code may link to.
+	wrappers map[string]bool
+}
+
+// lpadNames returns the names declared in the non-test Go files of the
+// lpad package source at dir.
+func lpadNames(dir string) (*pkgNames, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	names := &pkgNames{make(map[string]bool), make(map[string]bool)}
+	fset := token.NewFileSet()
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, file, nil, 0)
+		if err != nil {
+			return nil, err
+		}
+		if f.Name.Name != "lpad" {
+			continue
+		}
+		for _, decl := range f.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Recv == nil {
+					names.all[decl.Name.Name] = true
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					switch spec := spec.(type) {
+					case *ast.TypeSpec:
+						names.all[spec.Name.Name] = true
+						if isWrapper(spec) {
+							names.wrappers[spec.Name.Name] = true
+						}
+					case *ast.ValueSpec:
+						for _, name := range spec.Names {
+							names.all[name.Name] = true
+						}
+					}
+				}
+			}
+		}
+	}
+	if len(names.all) == 0 {
+		return nil, fmt.Errorf("no lpad package source found in %s", dir)
+	}
+	return names, nil
+}
+
+// isWrapper returns whether spec declares a struct embedding only *Value.
+func isWrapper(spec *ast.TypeSpec) bool {
+	st, ok := spec.Type.(*ast.StructType)
+	if !ok || len(st.Fields.List) != 1 || st.Fields.List[0].Names != nil {
+		return false
+	}
+	star, ok := st.Fields.List[0].Type.(*ast.StarExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := star.X.(*ast.Ident)
+	return ok && ident.Name == "Value"
+}
+
+// lpadDir returns the directory holding the source of the lpad package.
+func lpadDir() (string, error) {
+	out, err := exec.Command("go", "list", "-f", "{{.Dir}}", "github.com/canonical/lpad").Output()
+	if err != nil {
+		return "", fmt.Errorf("cannot find the lpad package source (use -lpad): %v", err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// The generator type emits Go wrappers for resource types in a service
+// description, in the style of the hand-written types in lpad.
+type generator struct {
+	desc   *lpad.ServiceDescription
+	source string
+	buf    bytes.Buffer
+
+	// pkg holds the names already declared in the lpad package.
+	pkg *pkgNames
+
+	// generated holds the resource types being generated.
+	generated map[string]bool
+
+	// typeNames holds the Go type names already taken.
+	typeNames map[string]bool
+
+	// warnings holds problems found while generating, such as methods
+	// that were skipped because their name clashed with another one.
+	warnings []string
+}
+
+// generate returns the Go source for the wrappers of the given resource
+// types, or for all entry types in desc if names is empty. Types that
+// already exist in the lpad package, as described by pkg, are left out
+// when generating all types, and are an error when explicitly named.
+// Links to resource types that are neither generated nor existing in
+// lpad are returned as plain *Value.
+func generate(desc *lpad.ServiceDescription, source string, names []string, pkg *pkgNames) (src []byte, warnings []string, err error) {
+	g := &generator{
+		desc:      desc,
+		source:    source,
+		pkg:       pkg,
+		generated: make(map[string]bool),
+		typeNames: make(map[string]bool),
+	}
+	if len(names) == 0 {
+		for name := range desc.ResourceTypes {
+			if isEntryType(name) && !pkg.all[goName(name)] {
+				names = append(names, name)
+			}
+		}
+	}
+	for _, name := range names {
+		if desc.ResourceTypes[name] == nil {
+			return nil, nil, fmt.Errorf("service description has no resource type %q", name)
+		}
+		if pkg.all[goName(name)] {
+			return nil, nil, fmt.Errorf("resource type %q is already defined as %s in package lpad", name, goName(name))
+		}
+		g.generated[name] = true
+	}
+	sort.Strings(names)
+
+	for name := range pkg.all {
+		g.typeNames[name] = true
+	}
+	for name := range g.generated {
+		g.typeNames[goName(name)] = true
+	}
+	for _, name := range names {
+		g.resourceType(desc.ResourceTypes[name])
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by lpadgen from %s. DO NOT EDIT.\n\n", source)
+	fmt.Fprintf(&out, "package lpad\n")
+	if bytes.Contains(g.buf.Bytes(), []byte("strconv.")) {
+		fmt.Fprintf(&out, "\nimport \"strconv\"\n")
+	}
+	out.Write(g.buf.Bytes())
+	src, err = format.Source(out.Bytes())
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot format generated code: %v", err)
+	}
+	return src, g.warnings, nil
+}
+
+func isEntryType(name string) bool {
+	return name != "service-root" && !strings.HasSuffix(name, pageSuffix) && !strings.Contains(name, "-")
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+func (g *generator) warnf(format string, args ...interface{}) {
+	g.warnings = append(g.warnings, fmt.Sprintf(format, args...))
+}
+
+// skipFields holds fields that are part of every representation, and
+// are handled by the Value type itself.
+var skipFields = map[string]bool{
+	"self_link":          true,
+	"resource_type_link": true,
+	"http_etag":          true,
+}
+
+func (g *generator) resourceType(rt *lpad.ResourceType) {
+	tname := goName(rt.Name)
+	recv := receiverName(tname)
+	methods := make(map[string]bool)
+
+	g.printf("\n")
+	g.comment("", fmt.Sprintf("The %s type represents a %q value in Launchpad.", tname, rt.Name), rt.Doc)
+	g.printf("type %s struct {\n\t*Value\n}\n", tname)
+
+	for _, f := range rt.Fields {
+		if skipFields[f.Name] {
+			continue
+		}
+		if f.Type == "link" {
+			g.linkField(rt, f, tname, recv, methods)
+		} else {
+			g.plainField(rt, f, tname, recv, methods)
+		}
+	}
+	for _, op := range rt.Operations {
+		g.operation(rt, op, tname, recv, methods)
+	}
+
+	if g.hasList(rt.Name) {
+		g.printf("\n// The %sList type represents a list of %s objects.\n", tname, tname)
+		g.printf("type %sList struct {\n\t*Value\n}\n", tname)
+		g.printf("\n// For iterates over the list and calls f for each entry.\n")
+		g.printf("// If f returns a non-nil error, iteration will stop and the error will be\n")
+		g.printf("// returned as the result of For.\n")
+		g.printf("func (list *%sList) For(f func(%s *%s) error) error {\n", tname, recv, tname)
+		g.printf("\treturn list.Value.For(func(v *Value) error {\n\t\treturn f(&%s{v})\n\t})\n}\n", tname)
+	}
+}
+
+// hasList returns whether a list type is generated for the resource type.
+func (g *generator) hasList(rtname string) bool {
+	return g.generated[rtname] && g.desc.ResourceTypes[rtname+pageSuffix] != nil && !g.pkg.all[goName(rtname)+"List"]
+}
+
+// valueMethods holds the names that generated types get from the
+// embedded *Value, which generated methods must not shadow.
+var valueMethods = func() map[string]bool {
+	names := map[string]bool{"Value": true}
+	t := reflect.TypeOf(&lpad.Value{})
+	for i := 0; i < t.NumMethod(); i++ {
+		names[t.Method(i).Name] = true
+	}
+	return names
+}()
+
+// method records the method name for the type, and returns false if it
+// was already taken.
+func (g *generator) method(rt *lpad.ResourceType, methods map[string]bool, name string) bool {
+	if valueMethods[name] {
+		g.warnf("%s: skipping method %s, which would shadow Value.%s", rt.Name, name, name)
+		return false
+	}
+	if methods[name] {
+		g.warnf("%s: skipping duplicated method %s", rt.Name, name)
+		return false
+	}
+	methods[name] = true
+	return true
+}
+
+// getters maps field types to the Value method used to read them and
+// the Go type returned.
+var getters = map[string][2]string{
+	"string":   {"StringField", "string"},
+	"date":     {"StringField", "string"},
+	"dateTime": {"StringField", "string"},
+	"binary":   {"StringField", "string"},
+	"boolean":  {"BoolField", "bool"},
+	"int":      {"IntField", "int"},
+	"float":    {"FloatField", "float64"},
+}
+
+func (g *generator) plainField(rt *lpad.ResourceType, f *lpad.Field, tname, recv string, methods map[string]bool) {
+	getter, ok := getters[f.Type]
+	if !ok {
+		getter = getters["string"]
+	}
+	name := goName(f.Name)
+	if f.Name == "web_link" {
+		name = "WebPage"
+	}
+	if !g.method(rt, methods, name) {
+		return
+	}
+	gotype := getter[1]
+	if len(f.Options) > 0 && gotype == "string" && g.typeNames[tname+name] {
+		g.warnf("%s: type %s already exists, using string for %q", rt.Name, tname+name, f.Name)
+	} else if len(f.Options) > 0 && gotype == "string" {
+		gotype = tname + name
+		g.typeNames[gotype] = true
+		g.printf("\n// A %s holds the possible values of the %q field of %s.\n", gotype, f.Name, tname)
+		g.printf("type %s string\n\nconst (\n", gotype)
+		seen := make(map[string]bool)
+		for _, o := range f.Options {
+			cname := gotype + goName(o)
+			if seen[cname] || g.pkg.all[cname] {
+				continue
+			}
+			seen[cname] = true
+			g.printf("\t%s %s = %q\n", cname, gotype, o)
+		}
+		g.printf(")\n")
+	}
+
+	g.printf("\n")
+	g.comment("", fmt.Sprintf("%s returns the %q field.", name, f.Name), f.Doc)
+	if gotype != getter[1] {
+		g.printf("func (%s *%s) %s() %s {\n\treturn %s(%s.%s(%q))\n}\n", recv, tname, name, gotype, gotype, recv, getter[0], f.Name)
+	} else {
+		g.printf("func (%s *%s) %s() %s {\n\treturn %s.%s(%q)\n}\n", recv, tname, name, gotype, recv, getter[0], f.Name)
+	}
+
+	if !f.Writable || !g.method(rt, methods, "Set"+name) {
+		return
+	}
+	arg := goArg(f.Name, recv)
+	g.printf("\n// Set%s changes the %q field.\n", name, f.Name)
+	g.printf("// Patch must be called to commit all changes.\n")
+	if gotype != getter[1] {
+		g.printf("func (%s *%s) Set%s(%s %s) {\n\t%s.SetField(%q, string(%s))\n}\n", recv, tname, name, arg, gotype, recv, f.Name, arg)
+	} else {
+		g.printf("func (%s *%s) Set%s(%s %s) {\n\t%s.SetField(%q, %s)\n}\n", recv, tname, name, arg, gotype, recv, f.Name, arg)
+	}
+}
+
+func (g *generator) linkField(rt *lpad.ResourceType, f *lpad.Field, tname, recv string, methods map[string]bool) {
+	base := strings.TrimSuffix(strings.TrimSuffix(f.Name, "_link"), "_collection")
+	name := goName(base)
+	if !g.method(rt, methods, name) {
+		return
+	}
+	gotype, wrap := g.valueType(f.LinkType)
+	g.printf("\n")
+	g.comment("", fmt.Sprintf("%s returns the value linked to by the %q field.", name, f.Name), f.Doc)
+	g.printf("func (%s *%s) %s() (%s, error) {\n", recv, tname, name, gotype)
+	g.printf("\tv, err := %s.Link(%q).Get(nil)\n", recv, f.Name)
+	g.printf("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	g.printf("\treturn %s, nil\n}\n", wrap)
+
+	if !f.Writable || !g.method(rt, methods, "Set"+name) {
+		return
+	}
+	argtype := gotype
+	if argtype == "*Value" {
+		argtype = "AnyValue"
+	}
+	arg := goArg(base, recv)
+	g.printf("\n// Set%s changes the value linked to by the %q field.\n", name, f.Name)
+	g.printf("// Patch must be called to commit all changes.\n")
+	g.printf("func (%s *%s) Set%s(%s %s) {\n\t%s.SetField(%q, %s.AbsLoc())\n}\n", recv, tname, name, arg, argtype, recv, f.Name, arg)
+}
+
+// valueType returns the Go type for values of the given resource type,
+// and the expression wrapping a *Value v into it. Only types generated
+// or wrapping a *Value in the lpad package are used.
+func (g *generator) valueType(rtname string) (gotype, wrap string) {
+	if rtname == "" || g.desc.ResourceTypes[rtname] == nil {
+		return "*Value", "v"
+	}
+	if strings.HasSuffix(rtname, pageSuffix) {
+		entry := strings.TrimSuffix(rtname, pageSuffix)
+		list := goName(entry) + "List"
+		if isEntryType(entry) && (g.hasList(entry) || g.pkg.wrappers[list]) {
+			return "*" + list, "&" + list + "{v}"
+		}
+		return "*Value", "v"
+	}
+	if !isEntryType(rtname) || !g.generated[rtname] && !g.pkg.wrappers[goName(rtname)] {
+		return "*Value", "v"
+	}
+	return "*" + goName(rtname), "&" + goName(rtname) + "{v}"
+}
+
+// paramTypes maps parameter types to their Go type.
+var paramTypes = map[string]string{
+	"boolean": "bool",
+	"int":     "int",
+	"float":   "float64",
+}
+
+func (g *generator) operation(rt *lpad.ResourceType, op *lpad.Operation, tname, recv string, methods map[string]bool) {
+	name := goName(op.Name)
+	if !g.method(rt, methods, name) {
+		return
+	}
+	var args []string
+	var sets []string
+	used := make(map[string]bool)
+	for _, p := range op.Params {
+		arg := goArg(p.Name, recv)
+		for i, base := 2, arg; used[arg]; i++ {
+			arg = base + strconv.Itoa(i)
+		}
+		used[arg] = true
+		var gotype, value, zero string
+		switch {
+		case p.Type == "link":
+			gotype, _ = g.valueType(p.LinkType)
+			if gotype == "*Value" || strings.HasSuffix(gotype, "List") {
+				gotype = "AnyValue"
+			}
+			value, zero = arg+".AbsLoc()", "nil"
+		case paramTypes[p.Type] == "bool":
+			gotype, value, zero = "bool", "strconv.FormatBool("+arg+")", "false"
+		case paramTypes[p.Type] == "int":
+			gotype, value, zero = "int", "strconv.Itoa("+arg+")", "0"
+		case paramTypes[p.Type] == "float64":
+			gotype, value, zero = "float64", "strconv.FormatFloat("+arg+", 'f', -1, 64)", "0"
+		default:
+			gotype, value, zero = "string", arg, `""`
+		}
+		args = append(args, arg+" "+gotype)
+		if p.Required {
+			sets = append(sets, fmt.Sprintf("\tparams[%q] = %s\n", p.Name, value))
+		} else if zero == "false" {
+			sets = append(sets, fmt.Sprintf("\tif %s {\n\t\tparams[%q] = %s\n\t}\n", arg, p.Name, value))
+		} else {
+			sets = append(sets, fmt.Sprintf("\tif %s != %s {\n\t\tparams[%q] = %s\n\t}\n", arg, zero, p.Name, value))
+		}
+	}
+
+	var gotype, wrap string
+	if op.Returns != "" {
+		gotype, wrap = g.valueType(op.Returns)
+	} else if op.Method == "GET" {
+		gotype, wrap = "*Value", "v"
+	}
+
+	g.printf("\n")
+	g.comment("", fmt.Sprintf("%s invokes the %q named operation.", name, op.Name), op.Doc)
+	if gotype == "" {
+		g.printf("func (%s *%s) %s(%s) error {\n", recv, tname, name, strings.Join(args, ", "))
+	} else {
+		g.printf("func (%s *%s) %s(%s) (%s, error) {\n", recv, tname, name, strings.Join(args, ", "), gotype)
+	}
+	g.printf("\tparams := Params{\"ws.op\": %q}\n", op.Name)
+	for _, set := range sets {
+		g.printf("%s", set)
+	}
+	call := "Post(params)"
+	if op.Method == "GET" {
+		call = "Location(\"\").Get(params)"
+	}
+	if gotype == "" {
+		g.printf("\t_, err := %s.%s\n\treturn err\n}\n", recv, call)
+		return
+	}
+	g.printf("\tv, err := %s.%s\n", recv, call)
+	g.printf("\tif err != nil {\n\t\treturn nil, err\n\t}\n")
+	g.printf("\treturn %s, nil\n}\n", wrap)
+}
+
+// comment prints a doc comment with the given summary followed by
+// the documentation from the service description, wrapped in lines
+// of reasonable length.
+func (g *generator) comment(indent, summary, doc string) {
+	words := strings.Fields(summary)
+	if doc != "" {
+		words = append(words, "")
+		words = append(words, strings.Fields(doc)...)
+	}
+	line := ""
+	for _, w := range words {
+		if w == "" {
+			g.printf("%s// %s\n%s//\n", indent, line, indent)
+			line = ""
+			continue
+		}
+		if line != "" && len(line)+len(w) >= 72 {
+			g.printf("%s// %s\n", indent, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	if line != "" {
+		g.printf("%s// %s\n", indent, line)
+	}
+}
+
+// commonInitialisms holds the words that are written in upper case
+// when part of a Go name.
+var commonInitialisms = map[string]bool{
+	"api": true, "http": true, "https": true, "ip": true,
+	"json": true, "oci": true, "ppa": true, "sha1": true, "ssh": true,
+	"uri": true, "url": true, "xml": true,
+}
+
+// goName converts a name such as "bug_task" or "date_created" into
+// the exported Go name "BugTask" or "DateCreated".
+func goName(name string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	for _, r := range name {
+		switch {
+		case r == '\'':
+			// Keep "Won't Fix" as WontFix rather than WonTFix.
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if unicode.IsUpper(r) && len(word) > 0 && unicode.IsLower(word[len(word)-1]) {
+				flush()
+			}
+			word = append(word, r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	var result string
+	for _, w := range words {
+		if commonInitialisms[strings.ToLower(w)] {
+			result += strings.ToUpper(w)
+		} else {
+			result += strings.ToUpper(w[:1]) + w[1:]
+		}
+	}
+	if result == "" || unicode.IsDigit(rune(result[0])) {
+		result = "X" + result
+	}
+	return result
+}
+
+// goArg converts a name into an unexported Go identifier suitable for
+// an argument of a method with the given receiver name.
+func goArg(name, recv string) string {
+	n := goName(name)
+	i := 0
+	for i < len(n) && unicode.IsUpper(rune(n[i])) {
+		i++
+	}
+	if i > 1 && i < len(n) {
+		i--
+	}
+	arg := strings.ToLower(n[:i]) + n[i:]
+	if token.Lookup(arg).IsKeyword() || arg == recv || arg == "params" || arg == "v" || arg == "err" {
+		arg += "Arg"
+	}
+	return arg
+}
+
+// receiverName returns a short receiver name for the Go type, made of
+// the initials of its words, such as "bt" for BugTask.
+func receiverName(tname string) string {
+	var initials []rune
+	for _, r := range tname {
+		if unicode.IsUpper(r) {
+			initials = append(initials, unicode.ToLower(r))
+		}
+	}
+	recv := string(initials)
+	if recv == "" || len(recv) > 3 || recv == "v" || token.Lookup(recv).IsKeyword() {
+		recv = "x"
+	}
+	return recv
+}
@@ -0,0 +1,339 @@
+package main
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func Test(t *testing.T) {
+	TestingT(t)
+}
+
+var _ = Suite(&GenerateS{})
+
+type GenerateS struct {
+	desc *lpad.ServiceDescription
+	pkg  *pkgNames
+}
+
+// lpadSource holds the directory of the lpad package source.
+const lpadSource = "../.."
+
+func (s *GenerateS) SetUpSuite(c *C) {
+	data, err := os.ReadFile("testdata/widget.wadl")
+	c.Assert(err, IsNil)
+	s.desc, err = lpad.ParseServiceDescription(data)
+	c.Assert(err, IsNil)
+	s.pkg, err = lpadNames(lpadSource)
+	c.Assert(err, IsNil)
+}
+
+func (s *GenerateS) generate(c *C, names ...string) string {
+	src, warnings, err := generate(s.desc, "widget.wadl", names, s.pkg)
+	c.Assert(err, IsNil)
+	c.Assert(warnings, HasLen, 0)
+	typeCheck(c, src)
+	return string(src)
+}
+
+// The standard library is type-checked from source once for all tests.
+var (
+	fset    = token.NewFileSet()
+	imports = importer.ForCompiler(fset, "source", nil)
+)
+
+// typeCheck verifies that src compiles as part of the lpad package.
+func typeCheck(c *C, src []byte) {
+	gen, err := parser.ParseFile(fset, "gen.go", src, 0)
+	c.Assert(err, IsNil)
+	files := []*ast.File{gen}
+	paths, err := filepath.Glob(filepath.Join(lpadSource, "*.go"))
+	c.Assert(err, IsNil)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		f, err := parser.ParseFile(fset, path, nil, 0)
+		c.Assert(err, IsNil)
+		files = append(files, f)
+	}
+	conf := types.Config{Importer: imports}
+	_, err = conf.Check("github.com/canonical/lpad", fset, files, nil)
+	c.Assert(err, IsNil, Commentf("generated code:\n%s", src))
+}
+
+func (s *GenerateS) TestHeader(c *C) {
+	src := s.generate(c, "widget")
+	c.Assert(strings.HasPrefix(src, "// Code generated by lpadgen from widget.wadl. DO NOT EDIT.\n\npackage lpad\n\nimport \"strconv\"\n"), Equals, true)
+}
+
+func (s *GenerateS) TestDefaultTypes(c *C) {
+	src := s.generate(c)
+	c.Assert(src, Matches, "(?s).*type Widget struct.*")
+	c.Assert(src, Not(Matches), "(?s).*type Person struct.*")
+	c.Assert(src, Not(Matches), "(?s).*type WidgetPageResource.*")
+}
+
+func (s *GenerateS) TestUnknownType(c *C) {
+	_, _, err := generate(s.desc, "widget.wadl", []string{"gadget"}, s.pkg)
+	c.Assert(err, ErrorMatches, `service description has no resource type "gadget"`)
+}
+
+func (s *GenerateS) TestExistingType(c *C) {
+	_, _, err := generate(s.desc, "widget.wadl", []string{"widget", "person"}, s.pkg)
+	c.Assert(err, ErrorMatches, `resource type "person" is already defined as Person in package lpad`)
+}
+
+func (s *GenerateS) TestLpadNames(c *C) {
+	c.Assert(s.pkg.wrappers["Person"], Equals, true)
+	c.Assert(s.pkg.wrappers["Member"], Equals, false)
+	c.Assert(s.pkg.all["Member"], Equals, true)
+	c.Assert(s.pkg.all["Login"], Equals, true)
+	c.Assert(s.pkg.all["Production"], Equals, true)
+}
+
+var generateTests = []string{
+	`// The Widget type represents a "widget" value in Launchpad.
+//
+// A widget attached to a project.
+type Widget struct {
+	*Value
+}
+`,
+	`// WebPage returns the "web_link" field.
+func (w *Widget) WebPage() string {
+	return w.StringField("web_link")
+}
+`,
+	`// Name returns the "name" field.
+//
+// The widget name.
+func (w *Widget) Name() string {
+	return w.StringField("name")
+}
+
+// SetName changes the "name" field.
+// Patch must be called to commit all changes.
+func (w *Widget) SetName(name string) {
+	w.SetField("name", name)
+}
+`,
+	`func (w *Widget) Id() int {
+	return w.IntField("id")
+}
+`,
+	`func (w *Widget) Ratio() float64 {
+	return w.FloatField("ratio")
+}
+`,
+	`func (w *Widget) Active() bool {
+	return w.BoolField("active")
+}
+`,
+	`func (w *Widget) DateCreated() string {
+	return w.StringField("date_created")
+}
+`,
+	`type WidgetStatus string
+
+const (
+	WidgetStatusNew         WidgetStatus = "New"
+	WidgetStatusWontFix     WidgetStatus = "Won't Fix"
+	WidgetStatusFixReleased WidgetStatus = "Fix Released"
+)
+`,
+	`func (w *Widget) Status() WidgetStatus {
+	return WidgetStatus(w.StringField("status"))
+}
+`,
+	`func (w *Widget) SetStatus(status WidgetStatus) {
+	w.SetField("status", string(status))
+}
+`,
+	`func (w *Widget) Owner() (*Person, error) {
+	v, err := w.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+`,
+	`func (w *Widget) SetOwner(owner *Person) {
+	w.SetField("owner_link", owner.AbsLoc())
+}
+`,
+	`func (w *Widget) Parts() (*WidgetList, error) {
+	v, err := w.Link("parts_collection_link").Get(nil)
+`,
+	`func (w *Widget) Other() (*Value, error) {
+`,
+	`func (w *Widget) SetOther(other AnyValue) {
+`,
+	`// FindParts invokes the "findParts" named operation.
+//
+// Find the parts of the widget.
+func (w *Widget) FindParts(text string, limit int, owner *Person) (*WidgetList, error) {
+	params := Params{"ws.op": "findParts"}
+	params["text"] = text
+	if limit != 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	if owner != nil {
+		params["owner"] = owner.AbsLoc()
+	}
+	v, err := w.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &WidgetList{v}, nil
+}
+`,
+	`func (w *Widget) NewPart(typeArg string, private bool) (*Widget, error) {
+	params := Params{"ws.op": "newPart"}
+	params["type"] = typeArg
+	if private {
+		params["private"] = strconv.FormatBool(private)
+	}
+	v, err := w.Post(params)
+`,
+	`func (w *Widget) DestroySelf() error {
+	params := Params{"ws.op": "destroySelf"}
+	_, err := w.Post(params)
+	return err
+}
+`,
+	`// The WidgetList type represents a list of Widget objects.
+type WidgetList struct {
+	*Value
+}
+`,
+	`func (list *WidgetList) For(f func(w *Widget) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&Widget{v})
+	})
+}
+`,
+}
+
+func (s *GenerateS) TestGenerate(c *C) {
+	src := s.generate(c, "widget")
+	for _, want := range generateTests {
+		if !strings.Contains(src, want) {
+			c.Errorf("generated code is missing:\n%s", want)
+		}
+	}
+	c.Assert(src, Not(Matches), `(?s).*"self_link".*`)
+}
+
+func (s *GenerateS) TestDuplicatedMethod(c *C) {
+	wadl := `<application>
+  <resource_type id="thing">
+    <method id="thing-get" name="GET"><response><representation href="#thing-full"/></response></method>
+    <method id="thing-title" name="GET">
+      <request><param name="ws.op" fixed="title"/></request>
+    </method>
+  </resource_type>
+  <representation id="thing-full"><param name="title"/></representation>
+</application>`
+	desc, err := lpad.ParseServiceDescription([]byte(wadl))
+	c.Assert(err, IsNil)
+	src, warnings, err := generate(desc, "thing.wadl", nil, s.pkg)
+	c.Assert(err, IsNil)
+	c.Assert(warnings, DeepEquals, []string{"thing: skipping duplicated method Title"})
+	c.Assert(strings.Count(string(src), ") Title("), Equals, 1)
+	typeCheck(c, src)
+}
+
+func (s *GenerateS) TestValueMethod(c *C) {
+	wadl := `<application>
+  <resource_type id="thing">
+    <method id="thing-get" name="GET"><response><representation href="#thing-full"/></response></method>
+    <method id="thing-delete" name="POST">
+      <request><param name="ws.op" fixed="delete"/></request>
+    </method>
+  </resource_type>
+  <representation id="thing-full"><param name="location"/><param name="title"/></representation>
+</application>`
+	desc, err := lpad.ParseServiceDescription([]byte(wadl))
+	c.Assert(err, IsNil)
+	src, warnings, err := generate(desc, "thing.wadl", nil, s.pkg)
+	c.Assert(err, IsNil)
+	c.Assert(warnings, DeepEquals, []string{
+		"thing: skipping method Location, which would shadow Value.Location",
+		"thing: skipping method Delete, which would shadow Value.Delete",
+	})
+	c.Assert(string(src), Not(Matches), `(?s).*\) (Delete|Location)\(.*`)
+	c.Assert(string(src), Matches, `(?s).*\) Title\(\) string.*`)
+	typeCheck(c, src)
+}
+
+func (s *GenerateS) TestLinksAndArgs(c *C) {
+	wadl := `<application>
+  <resource_type id="thing">
+    <method id="thing-get" name="GET"><response><representation href="#thing-full"/></response></method>
+    <method id="thing-find" name="GET">
+      <request>
+        <param name="ws.op" fixed="find"/>
+        <param name="type"/>
+        <param name="type_arg"/>
+        <param name="gadget"><link resource_type="https://api.launchpad.net/devel/#gadget"/></param>
+      </request>
+      <response><representation href="https://api.launchpad.net/devel/#gadget-page-resource"/></response>
+    </method>
+  </resource_type>
+  <resource_type id="gadget"/>
+  <resource_type id="gadget-page-resource"/>
+  <representation id="thing-full">
+    <param name="gadget_link"><link resource_type="https://api.launchpad.net/devel/#gadget"/></param>
+  </representation>
+</application>`
+	desc, err := lpad.ParseServiceDescription([]byte(wadl))
+	c.Assert(err, IsNil)
+	src, warnings, err := generate(desc, "thing.wadl", []string{"thing"}, s.pkg)
+	c.Assert(err, IsNil)
+	c.Assert(warnings, HasLen, 0)
+	typeCheck(c, src)
+	// Gadget isn't generated nor defined in lpad.
+	c.Assert(string(src), Matches, `(?s).*\) Gadget\(\) \(\*Value, error\).*`)
+	c.Assert(string(src), Matches, `(?s).*\) Find\(typeArg string, typeArg2 string, gadget AnyValue\) \(\*Value, error\).*`)
+}
+
+var goNameTests = []struct {
+	name, goName, goArg string
+}{
+	{"bug_task", "BugTask", "bugTask"},
+	{"date_created", "DateCreated", "dateCreated"},
+	{"web_link", "WebLink", "webLink"},
+	{"getByName", "GetByName", "getByName"},
+	{"http_etag", "HTTPEtag", "httpEtag"},
+	{"url", "URL", "url"},
+	{"Won't Fix", "WontFix", "wontFix"},
+	{"type", "Type", "typeArg"},
+	{"1.0", "X10", "x10"},
+}
+
+func (s *GenerateS) TestGoName(c *C) {
+	for _, t := range goNameTests {
+		c.Check(goName(t.name), Equals, t.goName, Commentf("name %q", t.name))
+		c.Check(goArg(t.name, "x"), Equals, t.goArg, Commentf("name %q", t.name))
+	}
+	c.Check(goArg("w", "w"), Equals, "wArg")
+}
+
+func (s *GenerateS) TestReceiverName(c *C) {
+	c.Check(receiverName("BugTask"), Equals, "bt")
+	c.Check(receiverName("Project"), Equals, "p")
+	c.Check(receiverName("Value"), Equals, "x")
+	c.Check(receiverName("DistroArchSeriesFilter"), Equals, "x")
+}
@@ -0,0 +1,79 @@
+// The lpadgen command generates Go wrapper types for Launchpad resources
+// out of the WADL document describing the Launchpad API, in the style of
+// the hand-written types in the lpad package.
+//
+// The WADL is read from a local file, which may be obtained with:
+//
+//	curl -H 'Accept: application/vnd.sun.wadl+xml' \
+//	    https://api.launchpad.net/devel/ > devel.wadl
+//
+// Types are generated for all entry resource types, or only for the ones
+// given in the command line. Since the generated code is in the lpad
+// package, types that already exist there are left out, and links to
+// types that are neither generated nor existing are left as *Value.
+// The lpad package source is found with "go list", unless given with
+// the -lpad flag. For example:
+//
+//	lpadgen -o lpad/gen_archive.go devel.wadl archive_dependency
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/canonical/lpad"
+)
+
+var output = flag.String("o", "", "write the generated code to `file` rather than stdout")
+var lpadFlag = flag.String("lpad", "", "read the lpad package source from `dir`")
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: lpadgen [-o file] [-lpad dir] <wadl file> [resource type ...]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), flag.Args()[1:], *output, *lpadFlag); err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(wadl string, names []string, output, dir string) error {
+	if dir == "" {
+		var err error
+		if dir, err = lpadDir(); err != nil {
+			return err
+		}
+	}
+	pkg, err := lpadNames(dir)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(wadl)
+	if err != nil {
+		return err
+	}
+	desc, err := lpad.ParseServiceDescription(data)
+	if err != nil {
+		return err
+	}
+	src, warnings, err := generate(desc, filepath.Base(wadl), names, pkg)
+	if err != nil {
+		return err
+	}
+	for _, w := range warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+	if output == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return os.WriteFile(output, src, 0644)
+}
@@ -0,0 +1,177 @@
+package lpad
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrNoCredentials is returned by the Load method of a CredentialStore
+// when it holds no credentials for the requested profile.
+var ErrNoCredentials = errors.New("no stored credentials")
+
+// The CredentialStore interface is implemented by types that keep the
+// OAuth credentials obtained by StoredOAuth and ConsoleOAuth, so that
+// future logins may reuse them.
+//
+// Credentials are kept under a profile name identifying the API base
+// and the consumer they were obtained for, such as
+// "lpad@https://api.launchpad.net/devel", so a single store may hold
+// identities for several Launchpad instances and applications.
+type CredentialStore interface {
+	// Load returns the credentials stored for profile, or
+	// ErrNoCredentials if there are none. Other errors prevent
+	// the login from proceeding.
+	Load(profile string) (token, secret string, err error)
+
+	// Save stores the credentials for profile, replacing any
+	// previously stored ones.
+	Save(profile, token, secret string) error
+}
+
+// credentialProfile returns the profile name under which credentials
+// obtained for consumer with the API at baseURL are stored.
+func credentialProfile(baseURL, consumer string) string {
+	return consumer + "@" + strings.TrimRight(baseURL, "/")
+}
+
+// The FileStore type is a CredentialStore that keeps credentials for
+// all profiles in a single JSON file readable only by its owner.
+//
+// If Path is empty, the file is $XDG_CONFIG_HOME/lpad/credentials, or
+// ~/.config/lpad/credentials if XDG_CONFIG_HOME isn't set. In that case
+// the single identity cached in ~/.lpad_oauth by earlier versions of
+// this package is used for the default consumer on Production, if the
+// file holds no credentials for it yet.
+type FileStore struct {
+	Path string
+}
+
+func (s *FileStore) path() string {
+	if s.Path != "" {
+		return s.Path
+	}
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		dir = filepath.Join(os.Getenv("HOME"), ".config")
+	}
+	return filepath.Join(dir, "lpad", "credentials")
+}
+
+func (s *FileStore) read() (map[string]*oauthDump, error) {
+	profiles := make(map[string]*oauthDump)
+	data, err := ioutil.ReadFile(s.path())
+	if os.IsNotExist(err) {
+		return profiles, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, errors.New("cannot parse credentials in " + s.path() + ": " + err.Error())
+	}
+	return profiles, nil
+}
+
+func (s *FileStore) Load(profile string) (token, secret string, err error) {
+	profiles, err := s.read()
+	if err != nil {
+		return "", "", err
+	}
+	dump, ok := profiles[profile]
+	if !ok && s.Path == "" && profile == legacyProfile {
+		dump, err = readLegacy()
+		if os.IsNotExist(err) {
+			return "", "", ErrNoCredentials
+		}
+		if err != nil {
+			return "", "", err
+		}
+		ok = true
+	}
+	if !ok {
+		return "", "", ErrNoCredentials
+	}
+	return dump.Token, dump.TokenSecret, nil
+}
+
+func (s *FileStore) Save(profile, token, secret string) error {
+	profiles, err := s.read()
+	if err != nil {
+		return err
+	}
+	profiles[profile] = &oauthDump{token, secret}
+	data, err := json.MarshalIndent(profiles, "", "\t")
+	if err != nil {
+		return err
+	}
+	path := s.path()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+	// Write to a private temporary file and rename it into place, so
+	// the credentials are never readable by others nor left truncated.
+	file, err := ioutil.TempFile(filepath.Dir(path), ".credentials")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0600)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+	}
+	return err
+}
+
+// legacyProfile is the only profile that may use the credentials in
+// ~/.lpad_oauth, so they're never sent to other API bases or used by
+// other consumers.
+var legacyProfile = credentialProfile(string(Production), (&OAuth{}).consumer())
+
+// readLegacy reads the credentials cached by earlier versions of
+// StoredOAuth, which held a single identity per user.
+func readLegacy() (*oauthDump, error) {
+	data, err := ioutil.ReadFile(os.ExpandEnv("$HOME/.lpad_oauth"))
+	if err != nil {
+		return nil, err
+	}
+	dump := &oauthDump{}
+	if err := json.Unmarshal(data, dump); err != nil {
+		return nil, err
+	}
+	return dump, nil
+}
+
+// The EnvStore type is a CredentialStore that takes credentials from
+// the LPAD_OAUTH_TOKEN and LPAD_OAUTH_TOKEN_SECRET environment
+// variables, which is convenient in continuous integration systems.
+// The same credentials are used for every profile. Since they can't be
+// saved, Load fails rather than letting a login ask for authorization
+// when the variables aren't set.
+type EnvStore struct{}
+
+var errEnvStore = errors.New("LPAD_OAUTH_TOKEN and LPAD_OAUTH_TOKEN_SECRET must be set")
+
+func (EnvStore) Load(profile string) (token, secret string, err error) {
+	token = os.Getenv("LPAD_OAUTH_TOKEN")
+	secret = os.Getenv("LPAD_OAUTH_TOKEN_SECRET")
+	if token == "" || secret == "" {
+		return "", "", errEnvStore
+	}
+	return token, secret, nil
+}
+
+func (EnvStore) Save(profile, token, secret string) error {
+	return errEnvStore
+}
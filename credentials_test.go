@@ -0,0 +1,124 @@
+package lpad_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+var _ = Suite(&CredentialsS{})
+
+type CredentialsS struct{}
+
+func (s *CredentialsS) TestFileStore(c *C) {
+	path := filepath.Join(c.MkDir(), "dir", "creds")
+	store := &lpad.FileStore{Path: path}
+
+	_, _, err := store.Load("a")
+	c.Assert(err, Equals, lpad.ErrNoCredentials)
+
+	c.Assert(store.Save("a", "token-a", "secret-a"), IsNil)
+	c.Assert(store.Save("b", "token-b", "secret-b"), IsNil)
+	c.Assert(store.Save("a", "token-a2", "secret-a2"), IsNil)
+
+	token, secret, err := store.Load("a")
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "token-a2")
+	c.Assert(secret, Equals, "secret-a2")
+	token, secret, err = store.Load("b")
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "token-b")
+	c.Assert(secret, Equals, "secret-b")
+
+	info, err := os.Stat(path)
+	c.Assert(err, IsNil)
+	c.Assert(info.Mode().Perm(), Equals, os.FileMode(0600))
+	info, err = os.Stat(filepath.Dir(path))
+	c.Assert(err, IsNil)
+	c.Assert(info.Mode().Perm(), Equals, os.FileMode(0700))
+
+	// No temporary files are left behind.
+	files, err := ioutil.ReadDir(filepath.Dir(path))
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+}
+
+func (s *CredentialsS) TestFileStoreBadFile(c *C) {
+	path := filepath.Join(c.MkDir(), "creds")
+	c.Assert(ioutil.WriteFile(path, []byte("{"), 0600), IsNil)
+	store := &lpad.FileStore{Path: path}
+	_, _, err := store.Load("a")
+	c.Assert(err, ErrorMatches, "cannot parse credentials in .*/creds: .*")
+	c.Assert(store.Save("a", "t", "s"), ErrorMatches, "cannot parse credentials in .*/creds: .*")
+}
+
+func (s *CredentialsS) TestFileStoreXDG(c *C) {
+	home, restore := fakeHome(c)
+	defer restore()
+	config := c.MkDir()
+	os.Setenv("XDG_CONFIG_HOME", config)
+
+	c.Assert((&lpad.FileStore{}).Save("a", "token", "secret"), IsNil)
+	_, err := os.Stat(filepath.Join(config, "lpad", "credentials"))
+	c.Assert(err, IsNil)
+	_, err = os.Stat(filepath.Join(home, ".config"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+// legacyProfile is the profile of the default consumer on Production.
+const legacyProfile = "https://launchpad.net/lpad@https://api.launchpad.net/devel"
+
+func (s *CredentialsS) TestFileStoreLegacy(c *C) {
+	home, restore := fakeHome(c)
+	defer restore()
+	err := ioutil.WriteFile(filepath.Join(home, ".lpad_oauth"), []byte(`{"Token": "old", "TokenSecret": "oldsecret"}`), 0644)
+	c.Assert(err, IsNil)
+
+	store := &lpad.FileStore{}
+	token, secret, err := store.Load(legacyProfile)
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "old")
+	c.Assert(secret, Equals, "oldsecret")
+
+	// Other API bases and consumers don't get the legacy credentials.
+	for _, profile := range []string{
+		"https://launchpad.net/lpad@https://api.staging.launchpad.net/devel",
+		"other@https://api.launchpad.net/devel",
+	} {
+		_, _, err = store.Load(profile)
+		c.Assert(err, Equals, lpad.ErrNoCredentials)
+	}
+
+	// Saved profiles take precedence.
+	c.Assert(store.Save(legacyProfile, "new", "newsecret"), IsNil)
+	token, _, err = store.Load(legacyProfile)
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "new")
+
+	// Explicit paths don't fall back to the legacy file.
+	_, _, err = (&lpad.FileStore{Path: filepath.Join(home, "creds")}).Load(legacyProfile)
+	c.Assert(err, Equals, lpad.ErrNoCredentials)
+}
+
+func (s *CredentialsS) TestEnvStore(c *C) {
+	os.Setenv("LPAD_OAUTH_TOKEN", "token")
+	os.Setenv("LPAD_OAUTH_TOKEN_SECRET", "secret")
+	defer os.Unsetenv("LPAD_OAUTH_TOKEN")
+	defer os.Unsetenv("LPAD_OAUTH_TOKEN_SECRET")
+
+	var store lpad.CredentialStore = lpad.EnvStore{}
+	token, secret, err := store.Load("any")
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "token")
+	c.Assert(secret, Equals, "secret")
+
+	c.Assert(store.Save("any", "t", "s"), ErrorMatches, "LPAD_OAUTH_TOKEN and LPAD_OAUTH_TOKEN_SECRET must be set")
+
+	os.Unsetenv("LPAD_OAUTH_TOKEN")
+	_, _, err = store.Load("any")
+	c.Assert(err, ErrorMatches, "LPAD_OAUTH_TOKEN and LPAD_OAUTH_TOKEN_SECRET must be set")
+}
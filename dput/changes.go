@@ -0,0 +1,311 @@
+// The dput package uploads signed source packages to Launchpad, as
+// the dput tool does, so that they may be built in a PPA or land in
+// a distribution's upload queue.
+//
+// This simple example demonstrates how to upload a package to a PPA:
+//
+//	changes, err := dput.ReadChanges("foo_1.0-1_source.changes")
+//	if err != nil {
+//	    panic(err)
+//	}
+//	err = dput.Upload(changes, dput.PPA("joe", "ppa", "ubuntu"))
+//	if err != nil {
+//	    panic(err)
+//	}
+package dput
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The File type holds the details of a file referenced by a .changes file.
+type File struct {
+	Name     string
+	Size     int64
+	Section  string
+	Priority string
+	MD5      string
+	SHA1     string
+	SHA256   string
+}
+
+// The Changes type holds the content of a Debian .changes file.
+type Changes struct {
+	// Path is the location of the .changes file itself, if it was
+	// read from disk. The files it references are expected to be
+	// found in the same directory.
+	Path string
+
+	// Fields holds all the fields in the control paragraph, indexed
+	// by their name as found in the file (e.g. "Distribution").
+	// Multi-line fields have their lines joined by "\n", with
+	// the leading space of continuation lines removed.
+	Fields map[string]string
+
+	// Files lists the files referenced by the .changes file,
+	// not including the .changes file itself.
+	Files []File
+
+	// Signed is true if the content was wrapped in an OpenPGP
+	// clear signature. The signature itself is not verified.
+	Signed bool
+
+	data []byte
+}
+
+// Source returns the source package name.
+func (c *Changes) Source() string {
+	return c.Fields["Source"]
+}
+
+// Version returns the version of the source package.
+func (c *Changes) Version() string {
+	return c.Fields["Version"]
+}
+
+// Distribution returns the distribution series (and possibly pocket)
+// the upload is targeted at, such as "oneiric" or "oneiric-proposed".
+func (c *Changes) Distribution() string {
+	return c.Fields["Distribution"]
+}
+
+// Architecture returns the list of architectures in the upload.
+func (c *Changes) Architecture() []string {
+	return strings.Fields(c.Fields["Architecture"])
+}
+
+// Data returns the exact content of the .changes file as parsed,
+// including the signature if there is one.
+func (c *Changes) Data() []byte {
+	return c.data
+}
+
+// Dir returns the directory where the files referenced by the .changes
+// file are expected to be found.
+func (c *Changes) Dir() string {
+	if c.Path == "" {
+		return "."
+	}
+	return filepath.Dir(c.Path)
+}
+
+// ReadChanges reads and parses the .changes file at path.
+func ReadChanges(path string) (*Changes, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseChanges(data)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse %s: %v", path, err)
+	}
+	c.Path = path
+	return c, nil
+}
+
+const (
+	pgpMessageStart   = "-----BEGIN PGP SIGNED MESSAGE-----"
+	pgpSignatureStart = "-----BEGIN PGP SIGNATURE-----"
+)
+
+// ParseChanges parses the content of a .changes file.
+func ParseChanges(data []byte) (*Changes, error) {
+	c := &Changes{data: data, Fields: make(map[string]string)}
+	lines, err := c.unwrap(data)
+	if err != nil {
+		return nil, err
+	}
+	var key string
+	for _, line := range lines {
+		if line == "" {
+			if len(c.Fields) > 0 {
+				break
+			}
+			continue
+		}
+		if line[0] == ' ' || line[0] == '\t' {
+			if key == "" {
+				return nil, fmt.Errorf("continuation line without a field: %q", line)
+			}
+			line = strings.TrimSpace(line)
+			if line == "." {
+				line = ""
+			}
+			if c.Fields[key] == "" {
+				c.Fields[key] = line
+			} else {
+				c.Fields[key] += "\n" + line
+			}
+			continue
+		}
+		i := strings.Index(line, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("invalid field line: %q", line)
+		}
+		key = line[:i]
+		c.Fields[key] = strings.TrimSpace(line[i+1:])
+	}
+	for _, key := range []string{"Source", "Version", "Distribution", "Files"} {
+		if c.Fields[key] == "" {
+			return nil, fmt.Errorf("missing %s field", key)
+		}
+	}
+	if err := c.parseFiles(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// unwrap returns the lines of the control paragraph, removing the
+// OpenPGP clear signature armor if there is one.
+func (c *Changes) unwrap(data []byte) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	start := 0
+	for start < len(lines) && strings.TrimSpace(lines[start]) == "" {
+		start++
+	}
+	if start == len(lines) || lines[start] != pgpMessageStart {
+		return lines, nil
+	}
+	c.Signed = true
+	// Skip the armor headers (e.g. "Hash: SHA256") up to the blank line.
+	i := start + 1
+	for i < len(lines) && lines[i] != "" {
+		i++
+	}
+	var body []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if line == pgpSignatureStart {
+			return body, nil
+		}
+		if strings.HasPrefix(line, "- ") {
+			line = line[2:]
+		}
+		body = append(body, line)
+	}
+	return nil, errors.New("signed content has no signature")
+}
+
+func (c *Changes) parseFiles() error {
+	byName := make(map[string]*File)
+	for _, line := range strings.Split(c.Fields["Files"], "\n") {
+		f := strings.Fields(line)
+		if len(f) != 5 {
+			return fmt.Errorf("invalid Files line: %q", line)
+		}
+		size, err := strconv.ParseInt(f[1], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Files line: %q", line)
+		}
+		// The files are looked up in the directory of the .changes
+		// file, so like dput refuse anything that may escape it.
+		if name := f[4]; name == "." || name == ".." || strings.ContainsAny(name, `/\`) {
+			return fmt.Errorf("invalid file name in Files: %q", name)
+		}
+		c.Files = append(c.Files, File{
+			Name:     f[4],
+			Size:     size,
+			Section:  f[2],
+			Priority: f[3],
+			MD5:      f[0],
+		})
+	}
+	for i := range c.Files {
+		byName[c.Files[i].Name] = &c.Files[i]
+	}
+	for _, key := range []string{"Checksums-Sha1", "Checksums-Sha256"} {
+		value, ok := c.Fields[key]
+		if !ok {
+			continue
+		}
+		for _, line := range strings.Split(value, "\n") {
+			f := strings.Fields(line)
+			if len(f) != 3 {
+				return fmt.Errorf("invalid %s line: %q", key, line)
+			}
+			file, ok := byName[f[2]]
+			if !ok {
+				return fmt.Errorf("%s lists %s which is not in Files", key, f[2])
+			}
+			if strconv.FormatInt(file.Size, 10) != f[1] {
+				return fmt.Errorf("%s and Files disagree on the size of %s", key, f[2])
+			}
+			if key == "Checksums-Sha1" {
+				file.SHA1 = f[0]
+			} else {
+				file.SHA256 = f[0]
+			}
+		}
+	}
+	return nil
+}
+
+// Verify checks that all the files referenced by the .changes file
+// exist in its directory and match the sizes and checksums listed.
+func (c *Changes) Verify() error {
+	for i := range c.Files {
+		if err := c.verifyFile(&c.Files[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Changes) verifyFile(f *File) error {
+	file, err := os.Open(filepath.Join(c.Dir(), f.Name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	hashes := []struct {
+		name string
+		want string
+		hash hash.Hash
+	}{
+		{"MD5", f.MD5, md5.New()},
+		{"SHA1", f.SHA1, sha1.New()},
+		{"SHA256", f.SHA256, sha256.New()},
+	}
+	var writers []io.Writer
+	for _, h := range hashes {
+		writers = append(writers, h.hash)
+	}
+	size, err := io.Copy(io.MultiWriter(writers...), file)
+	if err != nil {
+		return err
+	}
+	if size != f.Size {
+		return fmt.Errorf("%s has size %d, expected %d", f.Name, size, f.Size)
+	}
+	for _, h := range hashes {
+		if h.want == "" {
+			continue
+		}
+		if got := hex.EncodeToString(h.hash.Sum(nil)); got != strings.ToLower(h.want) {
+			return fmt.Errorf("%s has %s checksum %s, expected %s", f.Name, h.name, got, h.want)
+		}
+	}
+	return nil
+}
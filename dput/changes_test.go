@@ -0,0 +1,162 @@
+package dput_test
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad/dput"
+)
+
+var _ = Suite(&ChangesS{})
+
+type ChangesS struct{}
+
+// The checksums below are for the content "orig" and "debian".
+const unsignedChanges = `Format: 1.8
+Date: Mon, 10 Oct 2011 00:00:00 +0000
+Source: foo
+Binary: foo
+Architecture: source
+Version: 1.0-1
+Distribution: oneiric
+Urgency: low
+Maintainer: Joe <joe@example.com>
+Changes:
+ foo (1.0-1) oneiric; urgency=low
+ .
+   * Initial release.
+Checksums-Sha1:
+ dc894908c0eac029a31f01e32b7f0df596c7399c 4 foo_1.0.orig.tar.gz
+ a6eb4d9d7f99ca47abe56f3220597663cf37ca4a 6 foo_1.0-1.debian.tar.gz
+Checksums-Sha256:
+ 14e0ffdc8215c81da0cde40f581237ee35177ddac4f1fc7613cad3004798d25f 4 foo_1.0.orig.tar.gz
+ 81d93757457f988523814ae0009837ae893f38d3fe123f2c37896f118b4c7804 6 foo_1.0-1.debian.tar.gz
+Files:
+ 025f253325b46929cd34f2a7c3c55e7c 4 devel optional foo_1.0.orig.tar.gz
+ 6e9552c9bd8e61c8f277c21220160234 6 devel optional foo_1.0-1.debian.tar.gz
+`
+
+const signedChanges = `-----BEGIN PGP SIGNED MESSAGE-----
+Hash: SHA256
+
+` + unsignedChanges + `
+-----BEGIN PGP SIGNATURE-----
+
+iQEcBAEBCAAGBQJOkqAAAAoJEFakeSignatureOnlyForTests=
+-----END PGP SIGNATURE-----
+`
+
+func (s *ChangesS) TestParseChanges(c *C) {
+	changes, err := dput.ParseChanges([]byte(unsignedChanges))
+	c.Assert(err, IsNil)
+	c.Assert(changes.Signed, Equals, false)
+	c.Assert(changes.Source(), Equals, "foo")
+	c.Assert(changes.Version(), Equals, "1.0-1")
+	c.Assert(changes.Distribution(), Equals, "oneiric")
+	c.Assert(changes.Architecture(), DeepEquals, []string{"source"})
+	c.Assert(changes.Fields["Changes"], Equals, "foo (1.0-1) oneiric; urgency=low\n\n* Initial release.")
+	c.Assert(string(changes.Data()), Equals, unsignedChanges)
+	c.Assert(changes.Files, DeepEquals, []dput.File{{
+		Name:     "foo_1.0.orig.tar.gz",
+		Size:     4,
+		Section:  "devel",
+		Priority: "optional",
+		MD5:      "025f253325b46929cd34f2a7c3c55e7c",
+		SHA1:     "dc894908c0eac029a31f01e32b7f0df596c7399c",
+		SHA256:   "14e0ffdc8215c81da0cde40f581237ee35177ddac4f1fc7613cad3004798d25f",
+	}, {
+		Name:     "foo_1.0-1.debian.tar.gz",
+		Size:     6,
+		Section:  "devel",
+		Priority: "optional",
+		MD5:      "6e9552c9bd8e61c8f277c21220160234",
+		SHA1:     "a6eb4d9d7f99ca47abe56f3220597663cf37ca4a",
+		SHA256:   "81d93757457f988523814ae0009837ae893f38d3fe123f2c37896f118b4c7804",
+	}})
+}
+
+func (s *ChangesS) TestParseSignedChanges(c *C) {
+	changes, err := dput.ParseChanges([]byte(signedChanges))
+	c.Assert(err, IsNil)
+	c.Assert(changes.Signed, Equals, true)
+	c.Assert(changes.Source(), Equals, "foo")
+	c.Assert(changes.Files, HasLen, 2)
+	c.Assert(string(changes.Data()), Equals, signedChanges)
+}
+
+func (s *ChangesS) TestParseChangesErrors(c *C) {
+	_, err := dput.ParseChanges([]byte("Source: foo\nVersion: 1.0\nFiles:\n a 1 b c d\n"))
+	c.Assert(err, ErrorMatches, "missing Distribution field")
+
+	_, err = dput.ParseChanges([]byte("Source: foo\nVersion: 1.0\nDistribution: x\nFiles:\n a 1 b\n"))
+	c.Assert(err, ErrorMatches, `invalid Files line: "a 1 b"`)
+
+	_, err = dput.ParseChanges([]byte("-----BEGIN PGP SIGNED MESSAGE-----\n\nSource: foo\n"))
+	c.Assert(err, ErrorMatches, "signed content has no signature")
+
+	_, err = dput.ParseChanges([]byte("Source: foo\nVersion: 1.0\nDistribution: x\n" +
+		"Files:\n a 1 b c d\nChecksums-Sha1:\n a 2 d\n"))
+	c.Assert(err, ErrorMatches, "Checksums-Sha1 and Files disagree on the size of d")
+}
+
+func (s *ChangesS) TestParseChangesBadFileName(c *C) {
+	for _, name := range []string{"../etc/passwd", "/etc/passwd", `..\foo`, "sub/foo", ".", ".."} {
+		_, err := dput.ParseChanges([]byte("Source: foo\nVersion: 1.0\nDistribution: x\nFiles:\n a 1 b c " + name + "\n"))
+		c.Assert(err, ErrorMatches, `invalid file name in Files: ".*"`, Commentf("name %q", name))
+	}
+}
+
+func writeChanges(c *C, changes string, files map[string]string) string {
+	dir := c.MkDir()
+	for name, content := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644)
+		c.Assert(err, IsNil)
+	}
+	path := filepath.Join(dir, "foo_1.0-1_source.changes")
+	err := os.WriteFile(path, []byte(changes), 0644)
+	c.Assert(err, IsNil)
+	return path
+}
+
+var goodFiles = map[string]string{
+	"foo_1.0.orig.tar.gz":     "orig",
+	"foo_1.0-1.debian.tar.gz": "debian",
+}
+
+func (s *ChangesS) TestReadChangesAndVerify(c *C) {
+	path := writeChanges(c, signedChanges, goodFiles)
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+	c.Assert(changes.Path, Equals, path)
+	c.Assert(changes.Dir(), Equals, filepath.Dir(path))
+	c.Assert(changes.Verify(), IsNil)
+}
+
+func (s *ChangesS) TestVerifyMissingFile(c *C) {
+	path := writeChanges(c, signedChanges, map[string]string{"foo_1.0.orig.tar.gz": "orig"})
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+	c.Assert(changes.Verify(), ErrorMatches, "open .*/foo_1.0-1.debian.tar.gz: no such file or directory")
+}
+
+func (s *ChangesS) TestVerifyBadSize(c *C) {
+	path := writeChanges(c, signedChanges, map[string]string{
+		"foo_1.0.orig.tar.gz":     "orig",
+		"foo_1.0-1.debian.tar.gz": "debian!",
+	})
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+	c.Assert(changes.Verify(), ErrorMatches, "foo_1.0-1.debian.tar.gz has size 7, expected 6")
+}
+
+func (s *ChangesS) TestVerifyBadChecksum(c *C) {
+	path := writeChanges(c, signedChanges, map[string]string{
+		"foo_1.0.orig.tar.gz":     "ORIG",
+		"foo_1.0-1.debian.tar.gz": "debian",
+	})
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+	c.Assert(changes.Verify(), ErrorMatches, "foo_1.0.orig.tar.gz has MD5 checksum .*, expected 025f253325b46929cd34f2a7c3c55e7c")
+}
@@ -0,0 +1,342 @@
+package dput
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/textproto"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// A Method defines the protocol used to upload files to a Target.
+type Method string
+
+const (
+	FTP  Method = "ftp"
+	SFTP Method = "sftp"
+)
+
+// The Target type describes where uploads are delivered to.
+type Target struct {
+	Method   Method // Defaults to FTP
+	Host     string // The host name, with an optional ":port" suffix
+	Incoming string // The directory in the server to upload into
+
+	// Login and Password are used to authenticate against the server.
+	// For FTP they default to an anonymous login. For SFTP Login
+	// defaults to the current user name, authentication happens
+	// through the running ssh-agent and the server host key must be
+	// in ~/.ssh/known_hosts, unless SSHConfig is provided.
+	Login    string
+	Password string
+
+	// SSHConfig optionally overrides the configuration used to
+	// establish SFTP connections.
+	SSHConfig *ssh.ClientConfig
+
+	// AllowUnsigned permits uploading .changes files which are
+	// not signed. Launchpad rejects such uploads, so this is only
+	// useful for other targets.
+	AllowUnsigned bool
+
+	// Timeout limits how long establishing the connection may take.
+	// Defaults to one minute.
+	Timeout time.Duration
+}
+
+// The default hosts where Launchpad accepts uploads.
+const (
+	PPAHost    = "ppa.launchpad.net"
+	DistroHost = "upload.ubuntu.com"
+)
+
+// PPA returns a target for uploading into the named PPA owned by
+// the given person or team, for building against distro.
+func PPA(owner, name, distro string) *Target {
+	return &Target{
+		Method:   FTP,
+		Host:     PPAHost,
+		Incoming: fmt.Sprintf("~%s/%s/%s", owner, name, distro),
+	}
+}
+
+// Distro returns a target for uploading into the upload queue of the
+// primary archive of the named distribution.
+func Distro(name string) *Target {
+	return &Target{
+		Method:   FTP,
+		Host:     DistroHost,
+		Incoming: "/" + name,
+	}
+}
+
+// The Conn interface is implemented by connections able to store
+// files in an upload target.
+type Conn interface {
+	Store(name string, r io.Reader) error
+	Close() error
+}
+
+// Dial establishes a connection with the target server.
+func Dial(t *Target) (Conn, error) {
+	switch t.Method {
+	case FTP, "":
+		return dialFTP(t)
+	case SFTP:
+		return dialSFTP(t)
+	}
+	return nil, fmt.Errorf("unsupported upload method: %q", t.Method)
+}
+
+// Upload verifies that the files referenced by c match their checksums
+// and uploads them to the target, followed by the .changes file itself.
+// The .changes file is uploaded last so the server only processes the
+// upload once all of its files are in place.
+func Upload(c *Changes, t *Target) error {
+	if !c.Signed && !t.AllowUnsigned {
+		return errors.New("changes file is not signed")
+	}
+	if c.Path == "" {
+		return errors.New("changes file has no path")
+	}
+	if err := c.Verify(); err != nil {
+		return err
+	}
+	conn, err := Dial(t)
+	if err != nil {
+		return err
+	}
+	for _, f := range c.Files {
+		if err := storeFile(conn, filepath.Join(c.Dir(), f.Name)); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+	if err := storeFile(conn, c.Path); err != nil {
+		conn.Close()
+		return err
+	}
+	return conn.Close()
+}
+
+func storeFile(conn Conn, filename string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	if err := conn.Store(filepath.Base(filename), file); err != nil {
+		return fmt.Errorf("cannot upload %s: %v", filepath.Base(filename), err)
+	}
+	return nil
+}
+
+func (t *Target) timeout() time.Duration {
+	if t.Timeout == 0 {
+		return time.Minute
+	}
+	return t.Timeout
+}
+
+func (t *Target) addr(port string) string {
+	if _, _, err := net.SplitHostPort(t.Host); err == nil {
+		return t.Host
+	}
+	return net.JoinHostPort(t.Host, port)
+}
+
+type ftpConn struct {
+	conn    *textproto.Conn
+	host    string
+	timeout time.Duration
+}
+
+func dialFTP(t *Target) (Conn, error) {
+	nconn, err := net.DialTimeout("tcp", t.addr("21"), t.timeout())
+	if err != nil {
+		return nil, err
+	}
+	host, _, _ := net.SplitHostPort(nconn.RemoteAddr().String())
+	c := &ftpConn{textproto.NewConn(nconn), host, t.timeout()}
+	if _, _, err := c.conn.ReadResponse(220); err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	login, password := t.Login, t.Password
+	if login == "" {
+		login = "anonymous"
+		if password == "" {
+			password = "lpad@"
+		}
+	}
+	code, _, err := c.cmd(0, "USER %s", login)
+	if err == nil && code == 331 {
+		_, _, err = c.cmd(230, "PASS %s", password)
+	} else if err == nil && code != 230 {
+		err = fmt.Errorf("unexpected response to USER: %d", code)
+	}
+	if err == nil {
+		_, _, err = c.cmd(200, "TYPE I")
+	}
+	if err == nil && t.Incoming != "" {
+		_, _, err = c.cmd(250, "CWD %s", t.Incoming)
+	}
+	if err != nil {
+		c.conn.Close()
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *ftpConn) cmd(expect int, format string, args ...interface{}) (int, string, error) {
+	if _, err := c.conn.Cmd(format, args...); err != nil {
+		return 0, "", err
+	}
+	return c.conn.ReadResponse(expect)
+}
+
+// passive opens the data connection as advertised by the server
+// in response to PASV.
+func (c *ftpConn) passive() (net.Conn, error) {
+	_, msg, err := c.cmd(227, "PASV")
+	if err != nil {
+		return nil, err
+	}
+	start := strings.Index(msg, "(")
+	end := strings.LastIndex(msg, ")")
+	if start < 0 || end < start {
+		return nil, fmt.Errorf("invalid PASV response: %q", msg)
+	}
+	parts := strings.Split(msg[start+1:end], ",")
+	if len(parts) != 6 {
+		return nil, fmt.Errorf("invalid PASV response: %q", msg)
+	}
+	var port int
+	for _, part := range parts[4:] {
+		n, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("invalid PASV response: %q", msg)
+		}
+		port = port<<8 | n
+	}
+	// The advertised address is ignored as it's frequently wrong
+	// when the server is behind NAT.
+	return net.DialTimeout("tcp", net.JoinHostPort(c.host, strconv.Itoa(port)), c.timeout)
+}
+
+func (c *ftpConn) Store(name string, r io.Reader) error {
+	data, err := c.passive()
+	if err != nil {
+		return err
+	}
+	_, _, err = c.cmd(1, "STOR %s", name)
+	if err != nil {
+		data.Close()
+		return err
+	}
+	_, err = io.Copy(data, r)
+	if cerr := data.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	_, _, err = c.conn.ReadResponse(2)
+	return err
+}
+
+func (c *ftpConn) Close() error {
+	c.cmd(221, "QUIT")
+	return c.conn.Close()
+}
+
+type sftpConn struct {
+	ssh      *ssh.Client
+	sftp     *sftp.Client
+	agent    net.Conn
+	incoming string
+}
+
+func dialSFTP(t *Target) (Conn, error) {
+	var aconn net.Conn
+	config := t.SSHConfig
+	if config == nil {
+		login := t.Login
+		if login == "" {
+			login = os.Getenv("USER")
+		}
+		var auth []ssh.AuthMethod
+		if t.Password != "" {
+			auth = append(auth, ssh.Password(t.Password))
+		}
+		if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+			var err error
+			aconn, err = net.Dial("unix", sock)
+			if err != nil {
+				return nil, err
+			}
+			auth = append(auth, ssh.PublicKeysCallback(agent.NewClient(aconn).Signers))
+		}
+		if len(auth) == 0 {
+			return nil, errors.New("no SSH authentication available (missing ssh-agent?)")
+		}
+		hostKeys, err := knownhosts.New(os.ExpandEnv("$HOME/.ssh/known_hosts"))
+		if err != nil {
+			closeAgent(aconn)
+			return nil, err
+		}
+		config = &ssh.ClientConfig{
+			User:            login,
+			Auth:            auth,
+			HostKeyCallback: hostKeys,
+			Timeout:         t.timeout(),
+		}
+	}
+	sshc, err := ssh.Dial("tcp", t.addr("22"), config)
+	if err != nil {
+		closeAgent(aconn)
+		return nil, err
+	}
+	sftpc, err := sftp.NewClient(sshc)
+	if err != nil {
+		sshc.Close()
+		closeAgent(aconn)
+		return nil, err
+	}
+	return &sftpConn{sshc, sftpc, aconn, t.Incoming}, nil
+}
+
+func closeAgent(conn net.Conn) {
+	if conn != nil {
+		conn.Close()
+	}
+}
+
+func (c *sftpConn) Store(name string, r io.Reader) error {
+	file, err := c.sftp.Create(path.Join(c.incoming, name))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(file, r)
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+func (c *sftpConn) Close() error {
+	c.sftp.Close()
+	closeAgent(c.agent)
+	return c.ssh.Close()
+}
@@ -0,0 +1,259 @@
+package dput_test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad/dput"
+)
+
+var _ = Suite(&UploadS{})
+
+type UploadS struct {
+	server *testFTPServer
+}
+
+func (s *UploadS) SetUpTest(c *C) {
+	s.server = startTestFTPServer(c)
+}
+
+func (s *UploadS) TearDownTest(c *C) {
+	s.server.Close()
+}
+
+// testFTPServer is a minimal FTP server that accepts a single
+// passive-mode session at a time and records what it's sent.
+type testFTPServer struct {
+	listener net.Listener
+	mu       sync.Mutex
+	commands []string
+	files    map[string]string
+	done     chan bool
+}
+
+func startTestFTPServer(c *C) *testFTPServer {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	s := &testFTPServer{listener: l, files: make(map[string]string), done: make(chan bool, 1)}
+	go s.serve()
+	return s
+}
+
+func (s *testFTPServer) Addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *testFTPServer) Close() {
+	s.listener.Close()
+}
+
+func (s *testFTPServer) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.commands...)
+}
+
+func (s *testFTPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.session(conn)
+		s.done <- true
+	}
+}
+
+func (s *testFTPServer) session(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(format string, args ...interface{}) {
+		fmt.Fprintf(conn, format+"\r\n", args...)
+	}
+	reply("220 Test FTP server")
+	var data net.Listener
+	var cwd string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.mu.Lock()
+		s.commands = append(s.commands, line)
+		s.mu.Unlock()
+		cmd, arg := line, ""
+		if i := strings.Index(line, " "); i >= 0 {
+			cmd, arg = line[:i], line[i+1:]
+		}
+		switch cmd {
+		case "USER":
+			reply("331 Password required")
+		case "PASS":
+			reply("230 Logged in")
+		case "TYPE":
+			reply("200 Type set")
+		case "CWD":
+			if arg == "/forbidden" {
+				reply("550 No such directory")
+				continue
+			}
+			cwd = arg
+			reply("250 Directory changed")
+		case "PASV":
+			data, err = net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				reply("425 Can't open data connection")
+				continue
+			}
+			port := data.Addr().(*net.TCPAddr).Port
+			// The advertised address must be ignored by the client.
+			reply("227 Entering Passive Mode (10,0,0,1,%d,%d)", port>>8, port&0xff)
+		case "STOR":
+			if data == nil {
+				reply("425 Use PASV first")
+				continue
+			}
+			reply("150 Ok to send data")
+			dconn, err := data.Accept()
+			data.Close()
+			data = nil
+			if err != nil {
+				reply("425 Can't open data connection")
+				continue
+			}
+			content, err := io.ReadAll(dconn)
+			dconn.Close()
+			if err != nil {
+				reply("426 Transfer aborted")
+				continue
+			}
+			s.mu.Lock()
+			s.files[cwd+"/"+arg] = string(content)
+			s.mu.Unlock()
+			reply("226 Transfer complete")
+		case "QUIT":
+			reply("221 Goodbye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func (s *UploadS) TestUploadFTP(c *C) {
+	path := writeChanges(c, signedChanges, goodFiles)
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+
+	target := dput.PPA("joe", "ppa", "ubuntu")
+	target.Host = s.server.Addr()
+	err = dput.Upload(changes, target)
+	c.Assert(err, IsNil)
+	<-s.server.done
+
+	c.Assert(s.server.files, DeepEquals, map[string]string{
+		"~joe/ppa/ubuntu/foo_1.0.orig.tar.gz":      "orig",
+		"~joe/ppa/ubuntu/foo_1.0-1.debian.tar.gz":  "debian",
+		"~joe/ppa/ubuntu/foo_1.0-1_source.changes": signedChanges,
+	})
+	c.Assert(s.server.Commands(), DeepEquals, []string{
+		"USER anonymous",
+		"PASS lpad@",
+		"TYPE I",
+		"CWD ~joe/ppa/ubuntu",
+		"PASV",
+		"STOR foo_1.0.orig.tar.gz",
+		"PASV",
+		"STOR foo_1.0-1.debian.tar.gz",
+		"PASV",
+		"STOR foo_1.0-1_source.changes",
+		"QUIT",
+	})
+}
+
+func (s *UploadS) TestUploadFTPWithLogin(c *C) {
+	path := writeChanges(c, signedChanges, goodFiles)
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+
+	target := &dput.Target{
+		Host:     s.server.Addr(),
+		Incoming: "/ubuntu",
+		Login:    "joe",
+		Password: "secret",
+	}
+	err = dput.Upload(changes, target)
+	c.Assert(err, IsNil)
+	<-s.server.done
+
+	commands := s.server.Commands()
+	c.Assert(commands[:4], DeepEquals, []string{"USER joe", "PASS secret", "TYPE I", "CWD /ubuntu"})
+	c.Assert(s.server.files["/ubuntu/foo_1.0-1_source.changes"], Equals, signedChanges)
+}
+
+func (s *UploadS) TestUploadFTPBadIncoming(c *C) {
+	path := writeChanges(c, signedChanges, goodFiles)
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+
+	target := &dput.Target{Host: s.server.Addr(), Incoming: "/forbidden"}
+	err = dput.Upload(changes, target)
+	c.Assert(err, ErrorMatches, `550 "No such directory"`)
+	c.Assert(s.server.files, HasLen, 0)
+}
+
+func (s *UploadS) TestUploadUnsigned(c *C) {
+	path := writeChanges(c, unsignedChanges, goodFiles)
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+
+	target := &dput.Target{Host: s.server.Addr(), Incoming: "/ubuntu"}
+	err = dput.Upload(changes, target)
+	c.Assert(err, ErrorMatches, "changes file is not signed")
+	c.Assert(s.server.Commands(), HasLen, 0)
+
+	target.AllowUnsigned = true
+	err = dput.Upload(changes, target)
+	c.Assert(err, IsNil)
+	<-s.server.done
+	c.Assert(s.server.files, HasLen, 3)
+}
+
+func (s *UploadS) TestUploadVerifiesFiles(c *C) {
+	path := writeChanges(c, signedChanges, map[string]string{
+		"foo_1.0.orig.tar.gz":     "orig",
+		"foo_1.0-1.debian.tar.gz": "DEBIAN",
+	})
+	changes, err := dput.ReadChanges(path)
+	c.Assert(err, IsNil)
+
+	target := &dput.Target{Host: s.server.Addr(), Incoming: "/ubuntu"}
+	err = dput.Upload(changes, target)
+	c.Assert(err, ErrorMatches, "foo_1.0-1.debian.tar.gz has MD5 checksum .*")
+	c.Assert(s.server.Commands(), HasLen, 0)
+}
+
+func (s *UploadS) TestTargets(c *C) {
+	c.Assert(dput.PPA("joe", "ppa", "ubuntu"), DeepEquals, &dput.Target{
+		Method:   dput.FTP,
+		Host:     "ppa.launchpad.net",
+		Incoming: "~joe/ppa/ubuntu",
+	})
+	c.Assert(dput.Distro("ubuntu"), DeepEquals, &dput.Target{
+		Method:   dput.FTP,
+		Host:     "upload.ubuntu.com",
+		Incoming: "/ubuntu",
+	})
+}
+
+func (s *UploadS) TestDialUnsupportedMethod(c *C) {
+	_, err := dput.Dial(&dput.Target{Method: "scp", Host: s.server.Addr()})
+	c.Assert(err, ErrorMatches, `unsupported upload method: "scp"`)
+}
@@ -0,0 +1,162 @@
+package lpad
+
+// GitRepository returns the Git repository at the given path, which may
+// be in the short form used after lp: in repository URLs, such as
+// "project" or "~user/project/+git/name".
+func (root *Root) GitRepository(path string) (*GitRepository, error) {
+	v, err := root.Location("/+git").Get(Params{"ws.op": "getByPath", "path": path})
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepository{v}, nil
+}
+
+// GitRepositories returns the list of Git repositories owned by the person.
+func (person *Person) GitRepositories() (*GitRepositoryList, error) {
+	params := Params{
+		"ws.op":  "getRepositories",
+		"target": person.AbsLoc(),
+	}
+	v, err := person.Location("/+git").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepositoryList{v}, nil
+}
+
+// The GitRepository type represents a Git repository in Launchpad.
+type GitRepository struct {
+	*Value
+}
+
+// Name returns the repository name.
+func (r *GitRepository) Name() string {
+	return r.StringField("name")
+}
+
+// UniqueName returns the unique repository name, in the
+// form ~user/project/+git/name.
+func (r *GitRepository) UniqueName() string {
+	return r.StringField("unique_name")
+}
+
+// Id returns the shortest version for the repository name.  If the
+// repository is the default for a project, a lp:project form will be
+// returned. Otherwise, the unique name for the repository in the form
+// lp:~user/project/+git/name is returned.
+func (r *GitRepository) Id() string {
+	return r.StringField("git_identity")
+}
+
+// Description returns the repository description.
+func (r *GitRepository) Description() string {
+	return r.StringField("description")
+}
+
+// HTTPSURL returns the anonymous HTTPS URL for cloning the repository.
+func (r *GitRepository) HTTPSURL() string {
+	return r.StringField("git_https_url")
+}
+
+// SSHURL returns the SSH URL for pushing to the repository.
+func (r *GitRepository) SSHURL() string {
+	return r.StringField("git_ssh_url")
+}
+
+// DefaultBranch returns the full path of the default branch of the
+// repository (e.g. "refs/heads/master").
+func (r *GitRepository) DefaultBranch() string {
+	return r.StringField("default_branch")
+}
+
+// WebPage returns the URL for accessing this repository in a browser.
+func (r *GitRepository) WebPage() string {
+	return r.StringField("web_link")
+}
+
+// Owner returns the Person that owns this repository.
+func (r *GitRepository) Owner() (*Person, error) {
+	v, err := r.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// Branches returns the list of branches in the repository.
+func (r *GitRepository) Branches() (*GitRefList, error) {
+	v, err := r.Link("branches_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRefList{v}, nil
+}
+
+// Ref returns the reference in the repository with the given path,
+// which may be a full path such as "refs/heads/master" or a branch
+// name such as "master".
+func (r *GitRepository) Ref(path string) (*GitRef, error) {
+	v, err := r.Location("").Get(Params{"ws.op": "getRefByPath", "path": path})
+	if err != nil {
+		return nil, err
+	}
+	return &GitRef{v}, nil
+}
+
+// The GitRepositoryList type represents a list of GitRepository objects.
+type GitRepositoryList struct {
+	*Value
+}
+
+// For iterates over the list of repositories and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *GitRepositoryList) For(f func(r *GitRepository) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&GitRepository{v})
+	})
+}
+
+// The GitRef type represents a reference, such as a branch, in
+// a Git repository.
+type GitRef struct {
+	*Value
+}
+
+// Path returns the full path of the reference (e.g. "refs/heads/master").
+func (ref *GitRef) Path() string {
+	return ref.StringField("path")
+}
+
+// CommitSHA1 returns the SHA-1 of the commit the reference points to.
+func (ref *GitRef) CommitSHA1() string {
+	return ref.StringField("commit_sha1")
+}
+
+// WebPage returns the URL for accessing this reference in a browser.
+func (ref *GitRef) WebPage() string {
+	return ref.StringField("web_link")
+}
+
+// Repository returns the repository the reference is in.
+func (ref *GitRef) Repository() (*GitRepository, error) {
+	v, err := ref.Link("repository_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepository{v}, nil
+}
+
+// The GitRefList type represents a list of GitRef objects.
+type GitRefList struct {
+	*Value
+}
+
+// For iterates over the list of references and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *GitRefList) For(f func(ref *GitRef) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&GitRef{v})
+	})
+}
@@ -0,0 +1,120 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestGitRepository(c *C) {
+	m := M{
+		"name":                     "repo",
+		"unique_name":              "~joe/proj/+git/repo",
+		"git_identity":             "lp:~joe/proj/+git/repo",
+		"description":              "Description",
+		"git_https_url":            "https://git.launchpad.net/~joe/proj/+git/repo",
+		"git_ssh_url":              "git+ssh://git.launchpad.net/~joe/proj/+git/repo",
+		"default_branch":           "refs/heads/master",
+		"web_link":                 "http://page",
+		"owner_link":               testServer.URL + "/owner_link",
+		"branches_collection_link": testServer.URL + "/branches_link",
+	}
+	repo := &lpad.GitRepository{lpad.NewValue(nil, "", "", m)}
+	c.Assert(repo.Name(), Equals, "repo")
+	c.Assert(repo.UniqueName(), Equals, "~joe/proj/+git/repo")
+	c.Assert(repo.Id(), Equals, "lp:~joe/proj/+git/repo")
+	c.Assert(repo.Description(), Equals, "Description")
+	c.Assert(repo.HTTPSURL(), Equals, "https://git.launchpad.net/~joe/proj/+git/repo")
+	c.Assert(repo.SSHURL(), Equals, "git+ssh://git.launchpad.net/~joe/proj/+git/repo")
+	c.Assert(repo.DefaultBranch(), Equals, "refs/heads/master")
+	c.Assert(repo.WebPage(), Equals, "http://page")
+
+	testServer.PrepareResponse(200, jsonType, `{"display_name": "Joe"}`)
+	owner, err := repo.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.DisplayName(), Equals, "Joe")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+
+	data := `{"total_size": 2, "start": 0, "entries": [
+		{"path": "refs/heads/master", "commit_sha1": "abc"},
+		{"path": "refs/heads/devel", "commit_sha1": "def"}
+	]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	refs, err := repo.Branches()
+	c.Assert(err, IsNil)
+	paths := []string{}
+	refs.For(func(ref *lpad.GitRef) error {
+		paths = append(paths, ref.Path()+"@"+ref.CommitSHA1())
+		return nil
+	})
+	c.Assert(paths, DeepEquals, []string{"refs/heads/master@abc", "refs/heads/devel@def"})
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/branches_link")
+}
+
+func (s *ModelS) TestRootGitRepository(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"unique_name": "~joe/proj/+git/repo"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	repo, err := root.GitRepository("~joe/proj/+git/repo")
+	c.Assert(err, IsNil)
+	c.Assert(repo.UniqueName(), Equals, "~joe/proj/+git/repo")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/+git")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getByPath"})
+	c.Assert(req.Form["path"], DeepEquals, []string{"~joe/proj/+git/repo"})
+}
+
+func (s *ModelS) TestGitRepositoryRef(c *C) {
+	m := M{"repository_link": testServer.URL + "/repo_link", "web_link": "http://page"}
+	testServer.PrepareResponse(200, jsonType, `{"path": "refs/heads/master", "commit_sha1": "abc"}`)
+	repo := &lpad.GitRepository{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe/proj/+git/repo", nil)}
+	ref, err := repo.Ref("master")
+	c.Assert(err, IsNil)
+	c.Assert(ref.Path(), Equals, "refs/heads/master")
+	c.Assert(ref.CommitSHA1(), Equals, "abc")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe/proj/+git/repo")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getRefByPath"})
+	c.Assert(req.Form["path"], DeepEquals, []string{"master"})
+
+	ref = &lpad.GitRef{lpad.NewValue(nil, "", "", m)}
+	c.Assert(ref.WebPage(), Equals, "http://page")
+	testServer.PrepareResponse(200, jsonType, `{"name": "repo"}`)
+	repo, err = ref.Repository()
+	c.Assert(err, IsNil)
+	c.Assert(repo.Name(), Equals, "repo")
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/repo_link")
+}
+
+func (s *ModelS) TestPersonGitRepositories(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"name": "repo"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	list, err := person.GitRepositories()
+	c.Assert(err, IsNil)
+
+	names := []string{}
+	list.For(func(r *lpad.GitRepository) error {
+		names = append(names, r.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"repo"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/+git")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getRepositories"})
+	c.Assert(req.Form["target"], DeepEquals, []string{testServer.URL + "/~joe"})
+}
@@ -2,9 +2,15 @@ module github.com/canonical/lpad
 
 go 1.23.11
 
-require gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+require (
+	github.com/pkg/sftp v1.13.7
+	golang.org/x/crypto v0.31.0
+	gopkg.in/check.v1 v1.0.0-20201130134442-10cb98267c6c
+)
 
 require (
+	github.com/kr/fs v0.1.0 // indirect
 	github.com/kr/pretty v0.2.1 // indirect
 	github.com/kr/text v0.1.0 // indirect
+	golang.org/x/sys v0.28.0 // indirect
 )
@@ -0,0 +1,205 @@
+package lpad
+
+import (
+	"encoding/json"
+	"io"
+	"net/url"
+)
+
+// LiveFS returns the live filesystem with the given name owned by owner
+// and built for the named distribution series.
+func (root *Root) LiveFS(owner Member, distro, series, name string) (*LiveFS, error) {
+	seriesLoc := root.Location("/" + url.QueryEscape(distro) + "/" + url.QueryEscape(series)).AbsLoc()
+	params := Params{
+		"ws.op":         "getByName",
+		"owner":         owner.AbsLoc(),
+		"distro_series": seriesLoc,
+		"name":          name,
+	}
+	v, err := root.Location("/livefses").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveFS{v}, nil
+}
+
+// The LiveFS type represents a live filesystem image, such as
+// an installation medium, built by Launchpad.
+type LiveFS struct {
+	*Value
+}
+
+// Name returns the live filesystem name.
+func (fs *LiveFS) Name() string {
+	return fs.StringField("name")
+}
+
+// WebPage returns the URL for accessing this live filesystem in a browser.
+func (fs *LiveFS) WebPage() string {
+	return fs.StringField("web_link")
+}
+
+// Owner returns the Person or Team that owns the live filesystem.
+func (fs *LiveFS) Owner() (Member, error) {
+	v, err := fs.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// DistroSeries returns the distribution series the live filesystem
+// is built for.
+func (fs *LiveFS) DistroSeries() (*DistroSeries, error) {
+	v, err := fs.Link("distro_series_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroSeries{v}, nil
+}
+
+// RequestBuild requests a build of the live filesystem for arch, using
+// packages from archive in the given pocket. The metadata map overrides
+// entries in the live filesystem metadata for this build only, such
+// as {"project": "ubuntu-core"}, and may be nil.
+func (fs *LiveFS) RequestBuild(archive *Archive, arch *DistroArchSeries, pocket Pocket, metadata map[string]interface{}) (*LiveFSBuild, error) {
+	params := Params{
+		"ws.op":              "requestBuild",
+		"archive":            archive.AbsLoc(),
+		"distro_arch_series": arch.AbsLoc(),
+		"pocket":             string(pocket),
+	}
+	if len(metadata) > 0 {
+		data, err := json.Marshal(metadata)
+		if err != nil {
+			return nil, err
+		}
+		params["metadata_override"] = string(data)
+	}
+	v, err := fs.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveFSBuild{v}, nil
+}
+
+// Builds returns the list of all builds of the live filesystem.
+func (fs *LiveFS) Builds() (*LiveFSBuildList, error) {
+	v, err := fs.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveFSBuildList{v}, nil
+}
+
+// PendingBuilds returns the list of builds of the live filesystem
+// that haven't finished yet.
+func (fs *LiveFS) PendingBuilds() (*LiveFSBuildList, error) {
+	v, err := fs.Link("pending_builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveFSBuildList{v}, nil
+}
+
+// The LiveFSBuild type describes a build of a live filesystem.
+type LiveFSBuild struct {
+	*Value
+}
+
+// Title returns the build title.
+func (build *LiveFSBuild) Title() string {
+	return build.StringField("title")
+}
+
+// WebPage returns the URL for accessing this build in a browser.
+func (build *LiveFSBuild) WebPage() string {
+	return build.StringField("web_link")
+}
+
+// State returns the state of build.
+func (build *LiveFSBuild) State() BuildState {
+	return BuildState(build.StringField("buildstate"))
+}
+
+// BuildLogURL returns the URL for the build log file.
+func (build *LiveFSBuild) BuildLogURL() string {
+	return build.StringField("build_log_url")
+}
+
+// UploadLogURL returns the URL for the upload log if there was an upload failure.
+func (build *LiveFSBuild) UploadLogURL() string {
+	return build.StringField("upload_log_url")
+}
+
+// Created returns the timestamp when the build farm job was created.
+func (build *LiveFSBuild) Created() string {
+	return build.StringField("datecreated")
+}
+
+// Finished returns the timestamp when the build farm job was finished.
+func (build *LiveFSBuild) Finished() string {
+	return build.StringField("datebuilt")
+}
+
+// DistroArchSeries returns the distribution series architecture
+// the build is targeted at.
+func (build *LiveFSBuild) DistroArchSeries() (*DistroArchSeries, error) {
+	v, err := build.Link("distro_arch_series_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroArchSeries{v}, nil
+}
+
+// LiveFS returns the live filesystem being built.
+func (build *LiveFSBuild) LiveFS() (*LiveFS, error) {
+	v, err := build.Link("livefs_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LiveFS{v}, nil
+}
+
+// FileURLs returns the URLs of the files produced by the build.
+func (build *LiveFSBuild) FileURLs() ([]string, error) {
+	v, err := build.Location("").Get(Params{"ws.op": "getFileUrls"})
+	if err != nil {
+		return nil, err
+	}
+	return v.StringListField("value"), nil
+}
+
+// DownloadFile copies the content of the produced file at fileURL into w.
+// The request is only authenticated if fileURL is served by the API
+// itself, so files that Launchpad publishes elsewhere, such as in the
+// librarian, must be publicly accessible.
+func (build *LiveFSBuild) DownloadFile(fileURL string, w io.Writer) error {
+	return build.Location(fileURL).downloadTo(w)
+}
+
+// Cancel cancels the build if it's still pending or running.
+func (build *LiveFSBuild) Cancel() error {
+	_, err := build.Post(Params{"ws.op": "cancel"})
+	return err
+}
+
+// Retry sends a failed build back to the builder farm.
+func (build *LiveFSBuild) Retry() error {
+	_, err := build.Post(Params{"ws.op": "retry"})
+	return err
+}
+
+// The LiveFSBuildList type represents a list of LiveFSBuild objects.
+type LiveFSBuildList struct {
+	*Value
+}
+
+// For iterates over the list of live filesystem builds and calls f for
+// each one. If f returns a non-nil error, iteration will stop and the
+// error will be returned as the result of For.
+func (list *LiveFSBuildList) For(f func(b *LiveFSBuild) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&LiveFSBuild{v})
+	})
+}
@@ -0,0 +1,204 @@
+package lpad_test
+
+import (
+	"bytes"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestRootLiveFS(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "ubuntu-core"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	owner := &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)}
+	fs, err := root.LiveFS(owner, "ubuntu", "xenial", "ubuntu-core")
+	c.Assert(err, IsNil)
+	c.Assert(fs.Name(), Equals, "ubuntu-core")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/livefses")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getByName"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["distro_series"], DeepEquals, []string{testServer.URL + "/ubuntu/xenial"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"ubuntu-core"})
+}
+
+func (s *ModelS) TestLiveFS(c *C) {
+	m := M{
+		"name":               "ubuntu-core",
+		"web_link":           "http://page",
+		"owner_link":         testServer.URL + "/owner_link",
+		"distro_series_link": testServer.URL + "/series_link",
+	}
+	fs := &lpad.LiveFS{lpad.NewValue(nil, "", "", m)}
+	c.Assert(fs.Name(), Equals, "ubuntu-core")
+	c.Assert(fs.WebPage(), Equals, "http://page")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "image-team", "is_team": true}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "xenial"}`)
+	owner, err := fs.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "image-team")
+	series, err := fs.DistroSeries()
+	c.Assert(err, IsNil)
+	c.Assert(series.Name(), Equals, "xenial")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/series_link")
+}
+
+func (s *ModelS) TestLiveFSRequestBuild(c *C) {
+	fs := &lpad.LiveFS{lpad.NewValue(nil, testServer.URL, testServer.URL+"/livefs", nil)}
+	archive := &lpad.Archive{lpad.NewValue(nil, "", "http://archive", nil)}
+	arch := &lpad.DistroArchSeries{lpad.NewValue(nil, "", "http://arch", nil)}
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "Build"}`)
+	build, err := fs.RequestBuild(archive, arch, lpad.PocketUpdates, map[string]interface{}{"project": "ubuntu-core"})
+	c.Assert(err, IsNil)
+	c.Assert(build.Title(), Equals, "Build")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/livefs")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"requestBuild"})
+	c.Assert(req.Form["archive"], DeepEquals, []string{"http://archive"})
+	c.Assert(req.Form["distro_arch_series"], DeepEquals, []string{"http://arch"})
+	c.Assert(req.Form["pocket"], DeepEquals, []string{"Updates"})
+	c.Assert(req.Form["metadata_override"], DeepEquals, []string{`{"project":"ubuntu-core"}`})
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "Build"}`)
+	_, err = fs.RequestBuild(archive, arch, lpad.PocketRelease, nil)
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	_, ok := req.Form["metadata_override"]
+	c.Assert(ok, Equals, false)
+}
+
+func (s *ModelS) TestLiveFSBuilds(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"title": "Build"}]}`
+	m := M{
+		"builds_collection_link":         testServer.URL + "/builds",
+		"pending_builds_collection_link": testServer.URL + "/pending",
+	}
+	fs := &lpad.LiveFS{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := fs.Builds()
+	c.Assert(err, IsNil)
+	titles := []string{}
+	list.For(func(b *lpad.LiveFSBuild) error {
+		titles = append(titles, b.Title())
+		return nil
+	})
+	c.Assert(titles, DeepEquals, []string{"Build"})
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/builds")
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = fs.PendingBuilds()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/pending")
+}
+
+func (s *ModelS) TestLiveFSBuild(c *C) {
+	m := M{
+		"title":                   "Title",
+		"web_link":                "http://page",
+		"buildstate":              "Successfully built",
+		"build_log_url":           "http://log",
+		"upload_log_url":          "http://upload_log",
+		"datecreated":             "2016-10-10T00:00:00",
+		"datebuilt":               "2016-10-10T00:00:10",
+		"distro_arch_series_link": testServer.URL + "/arch_link",
+		"livefs_link":             testServer.URL + "/livefs_link",
+	}
+	build := &lpad.LiveFSBuild{lpad.NewValue(nil, testServer.URL, testServer.URL+"/build", m)}
+	c.Assert(build.Title(), Equals, "Title")
+	c.Assert(build.WebPage(), Equals, "http://page")
+	c.Assert(build.State(), Equals, lpad.BSSuccessfullyBuilt)
+	c.Assert(build.BuildLogURL(), Equals, "http://log")
+	c.Assert(build.UploadLogURL(), Equals, "http://upload_log")
+	c.Assert(build.Created(), Equals, "2016-10-10T00:00:00")
+	c.Assert(build.Finished(), Equals, "2016-10-10T00:00:10")
+
+	testServer.PrepareResponse(200, jsonType, `{"architecture_tag": "amd64"}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "ubuntu-core"}`)
+	arch, err := build.DistroArchSeries()
+	c.Assert(err, IsNil)
+	c.Assert(arch.Tag(), Equals, "amd64")
+	fs, err := build.LiveFS()
+	c.Assert(err, IsNil)
+	c.Assert(fs.Name(), Equals, "ubuntu-core")
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/arch_link")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/livefs_link")
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = build.Cancel()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"cancel"})
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = build.Retry()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"retry"})
+}
+
+func (s *ModelS) TestLiveFSBuildFiles(c *C) {
+	build := &lpad.LiveFSBuild{lpad.NewValue(nil, testServer.URL, testServer.URL+"/build", nil)}
+
+	data := `["` + testServer.URL + `/file/livecd.squashfs", "` + testServer.URL + `/file/livecd.manifest"]`
+	testServer.PrepareResponse(200, jsonType, data)
+	urls, err := build.FileURLs()
+	c.Assert(err, IsNil)
+	c.Assert(urls, DeepEquals, []string{testServer.URL + "/file/livecd.squashfs", testServer.URL + "/file/livecd.manifest"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/build")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getFileUrls"})
+
+	testServer.PrepareResponse(200, map[string]string{"Content-Type": "application/octet-stream"}, "manifest content")
+	var buf bytes.Buffer
+	err = build.DownloadFile(urls[1], &buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "manifest content")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/file/livecd.manifest")
+
+	testServer.PrepareResponse(500, nil, "boom")
+	err = build.DownloadFile(urls[0], &buf)
+	c.Assert(err, ErrorMatches, "Server returned 500 and body: boom")
+	testServer.WaitRequest()
+}
+
+func (s *ModelS) TestLiveFSBuildDownloadFileSigning(c *C) {
+	auth := &dummyAuth{}
+	session := lpad.NewSession(auth)
+	build := &lpad.LiveFSBuild{lpad.NewValue(session, testServer.URL+"/", testServer.URL+"/build", nil)}
+
+	testServer.PrepareResponse(200, nil, "content")
+	err := build.DownloadFile(testServer.URL+"/file/livecd.manifest", &bytes.Buffer{})
+	c.Assert(err, IsNil)
+	testServer.WaitRequest()
+	c.Assert(auth.signReq, NotNil)
+
+	// Credentials are not sent to other hosts.
+	auth.signReq = nil
+	testServer.PrepareResponse(200, nil, "content")
+	other := strings.Replace(testServer.URL, "localhost", "127.0.0.1", 1)
+	err = build.DownloadFile(other+"/file/livecd.manifest", &bytes.Buffer{})
+	c.Assert(err, IsNil)
+	testServer.WaitRequest()
+	c.Assert(auth.signReq, IsNil)
+}
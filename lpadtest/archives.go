@@ -0,0 +1,165 @@
+package lpadtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lpad"
+)
+
+var buildStates = strs(
+	lpad.BSNeedsBuilding, lpad.BSSuccessfullyBuilt, lpad.BSFailedToBuild,
+	lpad.BSDependencyWait, lpad.BSChrootProblem, lpad.BSBuildForSupersededSource,
+	lpad.BSCurrentlyBuilding, lpad.BSFailedToUpload, lpad.BSCurrentlyUploading,
+)
+
+// retryStates holds the states of builds which may be retried.
+var retryStates = strs(lpad.BSFailedToBuild, lpad.BSDependencyWait, lpad.BSChrootProblem, lpad.BSFailedToUpload)
+
+// doneStates holds the states of builds which are finished.
+var doneStates = append(strs(lpad.BSSuccessfullyBuilt, lpad.BSBuildForSupersededSource), retryStates...)
+
+var publishStatuses = strs(lpad.PubPending, lpad.PubPublished, lpad.PubSuperseded, lpad.PubDeleted, lpad.PubObsolete)
+
+var archive = &kind{
+	fields: map[string]*field{
+		"displayname": textField,
+		"description": textField,
+	},
+	ops: map[string]operation{
+		"GET getBuildRecords":     getBuildRecords,
+		"GET getPublishedSources": getPublishedSources,
+	},
+}
+
+var build = &kind{
+	ops: map[string]operation{
+		"POST retry": retryBuild,
+	},
+}
+
+// AddPPA adds a personal package archive for Ubuntu with the given
+// name, owned by the named person or team.
+func (s *Server) AddPPA(owner, name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.get("/~"+owner, "person", "team")
+	reference := fmt.Sprintf("~%s/ubuntu/%s", owner, name)
+	s.add("archive", fmt.Sprintf("/~%s/+archive/ubuntu/%s", owner, name), map[string]interface{}{
+		"name":              name,
+		"displayname":       fmt.Sprintf("PPA for %s", o.fields["display_name"]),
+		"description":       "",
+		"reference":         reference,
+		"private":           false,
+		"owner_link":        s.link(o),
+		"distribution_link": nil,
+		"web_link":          "https://launchpad.net/" + strings.Replace(reference, "/", "/+archive/", 1),
+	})
+}
+
+// AddBuild adds a build of version of the source package in the
+// named PPA for the given architecture, such as "amd64", and returns
+// the build number. The source package is published in the PPA if
+// it's not yet.
+func (s *Server) AddBuild(owner, ppa, source, version, arch string, state lpad.BuildState) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a := s.get(fmt.Sprintf("/~%s/+archive/ubuntu/%s", owner, ppa), "archive")
+	var pub *entry
+	for _, e := range s.where("source_package_publishing_history", "archive_link", s.link(a))() {
+		if e.fields["source_package_name"] == source && e.fields["source_package_version"] == version {
+			pub = e
+		}
+	}
+	if pub == nil {
+		pub = s.add("source_package_publishing_history", fmt.Sprintf("%s/+sourcepub/%d", a.path, s.serial("publication")), map[string]interface{}{
+			"display_name":           fmt.Sprintf("%s %s in ubuntu", source, version),
+			"source_package_name":    source,
+			"source_package_version": version,
+			"status":                 string(lpad.PubPublished),
+			"pocket":                 "Release",
+			"component_name":         "main",
+			"archive_link":           s.link(a),
+			"distro_series_link":     nil,
+			"date_published":         now(),
+		})
+	}
+	id := s.serial("build")
+	path := fmt.Sprintf("%s/+build/%d", a.path, id)
+	e := s.add("build", path, map[string]interface{}{
+		"title":                           fmt.Sprintf("%s build of %s %s in ubuntu", arch, source, version),
+		"arch_tag":                        arch,
+		"build_log_url":                   nil,
+		"upload_log_url":                  nil,
+		"archive_link":                    s.link(a),
+		"current_source_publication_link": s.link(pub),
+		"datecreated":                     now(),
+		"web_link":                        "https://launchpad.net" + path,
+	})
+	s.setBuildState(e, string(state))
+	// Builds are also reachable from the source package they build.
+	s.aliases[fmt.Sprintf("/ubuntu/+source/%s/%s/+build/%d", source, version, id)] = path
+	return id
+}
+
+func (s *Server) setBuildState(e *entry, state string) {
+	e.fields["buildstate"] = state
+	e.fields["can_be_retried"] = contains(retryStates, state)
+	e.fields["datebuilt"] = nil
+	if contains(doneStates, state) {
+		e.fields["datebuilt"] = now()
+	}
+	e.etag++
+}
+
+func getBuildRecords(s *Server, c *call) (interface{}, error) {
+	state, err := c.choice("build_state", buildStates)
+	if err != nil {
+		return nil, err
+	}
+	source := c.form.Get("source_name")
+	return s.find(func(e *entry) bool {
+		if e.kind != "build" || e.fields["archive_link"] != s.link(c.entry) {
+			return false
+		}
+		if state != "" && e.fields["buildstate"] != state {
+			return false
+		}
+		pub := s.lookup(e.fields["current_source_publication_link"].(string))
+		return source == "" || pub.fields["source_package_name"] == source
+	}), nil
+}
+
+func getPublishedSources(s *Server, c *call) (interface{}, error) {
+	status, err := c.choice("status", publishStatuses)
+	if err != nil {
+		return nil, err
+	}
+	exact, err := c.boolean("exact_match")
+	if err != nil {
+		return nil, err
+	}
+	source := c.form.Get("source_name")
+	pocket := c.form.Get("pocket")
+	return s.find(func(e *entry) bool {
+		if e.kind != "source_package_publishing_history" || e.fields["archive_link"] != s.link(c.entry) {
+			return false
+		}
+		if status != "" && e.fields["status"] != status || pocket != "" && e.fields["pocket"] != pocket {
+			return false
+		}
+		name := e.fields["source_package_name"].(string)
+		if exact {
+			return source == "" || name == source
+		}
+		return strings.Contains(name, source)
+	}), nil
+}
+
+func retryBuild(s *Server, c *call) (interface{}, error) {
+	if !c.entry.fields["can_be_retried"].(bool) {
+		return nil, badRequest("Build %s cannot be retried.", c.entry.fields["title"])
+	}
+	s.setBuildState(c.entry, string(lpad.BSNeedsBuilding))
+	return nil, nil
+}
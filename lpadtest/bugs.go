@@ -0,0 +1,255 @@
+package lpadtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canonical/lpad"
+)
+
+var bugStatuses = strs(
+	lpad.StNew, lpad.StIncomplete, lpad.StOpinion, lpad.StInvalid, lpad.StWontFix,
+	lpad.StExpired, lpad.StConfirmed, lpad.StTriaged, lpad.StInProgress,
+	lpad.StFixCommitted, lpad.StFixReleased, lpad.StUnknown,
+)
+
+// openStatuses holds the statuses of tasks searched for by default.
+var openStatuses = strs(
+	lpad.StNew, lpad.StIncomplete, lpad.StConfirmed, lpad.StTriaged,
+	lpad.StInProgress, lpad.StFixCommitted,
+)
+
+var bugImportances = strs(
+	lpad.ImUnknown, lpad.ImUndecided, lpad.ImCritical, lpad.ImHigh,
+	lpad.ImMedium, lpad.ImLow, lpad.ImWishlist,
+)
+
+var bugs = &kind{
+	ops: map[string]operation{
+		"POST createBug": createBug,
+	},
+}
+
+var bug = &kind{
+	fields: map[string]*field{
+		"title":            textField,
+		"description":      textField,
+		"tags":             listField,
+		"private":          boolField,
+		"security_related": boolField,
+	},
+	ops: map[string]operation{
+		"POST newMessage": newMessage,
+		"POST linkBranch": linkBranch,
+		"POST addTask":    addTask,
+	},
+}
+
+var bugTask = &kind{
+	fields: map[string]*field{
+		"status":         enumField(bugStatuses...),
+		"importance":     enumField(bugImportances...),
+		"assignee_link":  linkField("person", "team"),
+		"milestone_link": linkField("milestone"),
+	},
+}
+
+// AddBug adds a bug reported by the named person, with a task for each
+// of the named projects, and returns the bug number.
+func (s *Server) AddBug(reporter, title, description string, targets ...string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(targets) == 0 {
+		panic("lpadtest: bugs need at least one target")
+	}
+	owner := s.get("/~"+reporter, "person")
+	e := s.addBug(owner, title, description)
+	for _, target := range targets {
+		s.addTask(e, s.get("/"+target, "project"), owner)
+	}
+	return e.fields["id"].(int)
+}
+
+func (s *Server) addBug(owner *entry, title, description string) *entry {
+	id := s.serial("bug")
+	e := s.add("bug", "/bugs/"+strconv.Itoa(id), map[string]interface{}{
+		"id":               id,
+		"title":            title,
+		"description":      description,
+		"tags":             []string{},
+		"private":          false,
+		"security_related": false,
+		"owner_link":       s.link(owner),
+		"date_created":     now(),
+		"web_link":         fmt.Sprintf("https://bugs.launchpad.net/bugs/%d", id),
+	})
+	link := s.link(e)
+	e.lists = map[string]func() []*entry{
+		"bug_tasks":       s.where("bug_task", "bug_link", link),
+		"messages":        s.where("message", "bug_link", link),
+		"linked_branches": s.where("bug_branch", "bug_link", link),
+	}
+	s.addMessage(e, owner, title, description)
+	return e
+}
+
+func (s *Server) addTask(b, target, owner *entry) *entry {
+	id := b.fields["id"].(int)
+	name := target.fields["name"].(string)
+	return s.add("bug_task", fmt.Sprintf("/%s/+bug/%d", name, id), map[string]interface{}{
+		"title":                   fmt.Sprintf("Bug #%d in %s: %q", id, target.fields["display_name"], b.fields["title"]),
+		"bug_target_name":         name,
+		"bug_target_display_name": target.fields["display_name"],
+		"status":                  string(lpad.StNew),
+		"importance":              string(lpad.ImUndecided),
+		"assignee_link":           nil,
+		"milestone_link":          nil,
+		"bug_link":                s.link(b),
+		"target_link":             s.link(target),
+		"owner_link":              s.link(owner),
+		"date_created":            now(),
+		"web_link":                fmt.Sprintf("https://bugs.launchpad.net/%s/+bug/%d", name, id),
+	})
+}
+
+// addMessage adds a comment to bug b. The first message holds the
+// bug description, as in Launchpad.
+func (s *Server) addMessage(b, owner *entry, subject, content string) *entry {
+	n := len(b.lists["messages"]())
+	return s.add("message", fmt.Sprintf("%s/messages/%d", b.path, n), map[string]interface{}{
+		"subject":      subject,
+		"content":      content,
+		"owner_link":   s.link(owner),
+		"bug_link":     s.link(b),
+		"date_created": now(),
+		"web_link":     fmt.Sprintf("https://bugs.launchpad.net/bugs/%d/comments/%d", b.fields["id"], n),
+	})
+}
+
+func createBug(s *Server, c *call) (interface{}, error) {
+	title, err := c.required("title")
+	if err != nil {
+		return nil, err
+	}
+	description, err := c.required("description")
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.requiredObject(c, "target", "project")
+	if err != nil {
+		return nil, err
+	}
+	private, err := c.boolean("private")
+	if err != nil {
+		return nil, err
+	}
+	security, err := c.boolean("security_related")
+	if err != nil {
+		return nil, err
+	}
+	e := s.addBug(c.me, title, description)
+	e.fields["tags"] = append([]string{}, strings.Fields(c.form.Get("tags"))...)
+	e.fields["private"] = private
+	e.fields["security_related"] = security
+	s.addTask(e, target, c.me)
+	return created{e}, nil
+}
+
+func newMessage(s *Server, c *call) (interface{}, error) {
+	content, err := c.required("content")
+	if err != nil {
+		return nil, err
+	}
+	subject := c.form.Get("subject")
+	if subject == "" {
+		subject = "Re: " + c.entry.fields["title"].(string)
+	}
+	return created{s.addMessage(c.entry, c.me, subject, content)}, nil
+}
+
+func linkBranch(s *Server, c *call) (interface{}, error) {
+	b, err := s.requiredObject(c, "branch", "branch")
+	if err != nil {
+		return nil, err
+	}
+	path := fmt.Sprintf("%s/+bug/%d", b.path, c.entry.fields["id"])
+	if s.entries[path] == nil {
+		s.add("bug_branch", path, map[string]interface{}{
+			"bug_link":    s.link(c.entry),
+			"branch_link": s.link(b),
+		})
+	}
+	return nil, nil
+}
+
+func addTask(s *Server, c *call) (interface{}, error) {
+	target, err := s.requiredObject(c, "target", "project")
+	if err != nil {
+		return nil, err
+	}
+	if s.entries[fmt.Sprintf("/%s/+bug/%d", target.fields["name"], c.entry.fields["id"])] != nil {
+		return nil, badRequest("A fix for this bug has already been requested for %s.", target.fields["display_name"])
+	}
+	return created{s.addTask(c.entry, target, c.me)}, nil
+}
+
+// searchTasks finds bug tasks on a project, or related to a person
+// in the role given by the parameter that refers to them.
+func searchTasks(s *Server, c *call) (interface{}, error) {
+	statuses, err := c.choices("status", bugStatuses)
+	if err != nil {
+		return nil, err
+	}
+	if len(statuses) == 0 {
+		statuses = openStatuses
+	}
+	importances, err := c.choices("importance", bugImportances)
+	if err != nil {
+		return nil, err
+	}
+	roles := make(map[string]*entry)
+	for _, role := range strs(lpad.TaskAssignee, lpad.TaskReporter, lpad.TaskSubscriber, lpad.TaskCommenter) {
+		if roles[role], err = s.object(c, role, "person", "team"); err != nil {
+			return nil, err
+		}
+	}
+	return s.find(func(e *entry) bool {
+		if e.kind != "bug_task" || !contains(statuses, e.fields["status"].(string)) {
+			return false
+		}
+		if len(importances) > 0 && !contains(importances, e.fields["importance"].(string)) {
+			return false
+		}
+		if c.entry.kind == "project" && e.fields["target_link"] != s.link(c.entry) {
+			return false
+		}
+		b := s.lookup(e.fields["bug_link"].(string))
+		for role, p := range roles {
+			if p == nil {
+				continue
+			}
+			link := s.link(p)
+			switch lpad.BugTaskRole(role) {
+			case lpad.TaskAssignee:
+				if e.fields["assignee_link"] != link {
+					return false
+				}
+			case lpad.TaskReporter, lpad.TaskSubscriber:
+				// The reporter is subscribed to the bug.
+				if b.fields["owner_link"] != link {
+					return false
+				}
+			case lpad.TaskCommenter:
+				found := false
+				for _, m := range b.lists["messages"]() {
+					found = found || m.fields["owner_link"] == link
+				}
+				if !found {
+					return false
+				}
+			}
+		}
+		return true
+	}), nil
+}
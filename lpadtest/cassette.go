@@ -0,0 +1,288 @@
+package lpadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"sync"
+	"unicode/utf8"
+)
+
+// The Mode type defines whether a Recorder records or replays
+// interactions with Launchpad.
+type Mode int
+
+const (
+	// Replay serves the responses recorded in the cassette file,
+	// without network access.
+	Replay Mode = iota
+
+	// Record delivers requests to Launchpad, and records the
+	// interactions so that Save may write them to the cassette file.
+	Record
+)
+
+// The Recorder type is an http.RoundTripper which records the requests
+// delivered to Launchpad and their responses into a cassette file, or
+// serves responses previously recorded, so that tests which talk to
+// the real Launchpad may be run offline once recorded. Authorization
+// headers are not recorded.
+//
+// Recorded requests are matched by method, URL and form parameters,
+// and each recorded response is served once, in the recorded order.
+//
+// This example runs a test against Launchpad when -record is provided,
+// and replays the interactions otherwise:
+//
+//	mode := lpadtest.Replay
+//	if *record {
+//	    mode = lpadtest.Record
+//	}
+//	rec, err := lpadtest.NewRecorder("testdata/me.json", mode)
+//	if err != nil {
+//	    panic(err)
+//	}
+//	defer rec.Save()
+//	root, err := lpad.Login(lpad.Production, auth)
+//	if err != nil {
+//	    panic(err)
+//	}
+//	root.Session().SetTransport(rec)
+//	me, err := root.Me()
+//
+// Only requests made through the session are recorded, so OAuth
+// credentials must be obtained beforehand when recording.
+type Recorder struct {
+	// Transport delivers requests in Record mode. If nil,
+	// http.DefaultTransport is used.
+	Transport http.RoundTripper
+
+	mode         Mode
+	path         string
+	mu           sync.Mutex
+	interactions []*interaction
+	used         []bool
+}
+
+type cassette struct {
+	Interactions []*interaction `json:"interactions"`
+}
+
+type interaction struct {
+	Request  recordedRequest  `json:"request"`
+	Response recordedResponse `json:"response"`
+}
+
+type recordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+type recordedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	recordedBody
+}
+
+// recordedBody holds text bodies as strings, for easy reading of
+// cassettes, and other bodies in base64.
+type recordedBody struct {
+	Body   string `json:"body,omitempty"`
+	Binary []byte `json:"body_base64,omitempty"`
+}
+
+func (b *recordedBody) set(data []byte) {
+	if utf8.Valid(data) {
+		b.Body = string(data)
+	} else {
+		b.Binary = data
+	}
+}
+
+func (b *recordedBody) get() []byte {
+	if b.Binary != nil {
+		return b.Binary
+	}
+	return []byte(b.Body)
+}
+
+// scrubbedHeaders holds the request headers which aren't recorded,
+// as they hold credentials.
+var scrubbedHeaders = []string{"Authorization"}
+
+// NewRecorder returns a Recorder that works in the given mode with the
+// cassette file at path. In Replay mode the file is read immediately.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+	if mode == Replay {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var c cassette
+		if err := json.Unmarshal(data, &c); err != nil {
+			return nil, fmt.Errorf("cannot parse cassette %s: %v", path, err)
+		}
+		r.interactions = c.Interactions
+		r.used = make([]bool, len(c.Interactions))
+	}
+	return r, nil
+}
+
+// RoundTrip delivers req to Launchpad and records the interaction in
+// Record mode, or serves the matching recorded response in Replay mode.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.mode == Replay {
+		return r.replay(req, body)
+	}
+	return r.record(req, body)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	url := normalURL(req.URL.String())
+	form := normalForm(req.Header.Get("Content-Type"), body)
+	for i, in := range r.interactions {
+		recorded := &in.Request
+		if r.used[i] || recorded.Method != req.Method || normalURL(recorded.URL) != url {
+			continue
+		}
+		if normalForm(recorded.Header.Get("Content-Type"), recorded.get()) != form {
+			continue
+		}
+		r.used[i] = true
+		data := in.Response.get()
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        cloneHeader(in.Response.Header, nil),
+			Body:          ioutil.NopCloser(bytes.NewReader(data)),
+			ContentLength: int64(len(data)),
+			Request:       req,
+		}, nil
+	}
+	return nil, fmt.Errorf("no recorded interaction for %s %s", req.Method, req.URL)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	if body != nil {
+		out.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	data, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(data))
+
+	in := &interaction{}
+	in.Request.Method = req.Method
+	in.Request.URL = req.URL.String()
+	in.Request.Header = cloneHeader(req.Header, scrubbedHeaders)
+	in.Request.set(body)
+	in.Response.Status = resp.StatusCode
+	in.Response.Header = cloneHeader(resp.Header, nil)
+	in.Response.set(data)
+	r.interactions = append(r.interactions, in)
+	return resp, nil
+}
+
+// Save writes the interactions recorded so far to the cassette file.
+// It does nothing in Replay mode.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(&cassette{r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(r.path, append(data, '\n'), 0644)
+}
+
+func cloneHeader(header http.Header, skip []string) http.Header {
+	clone := make(http.Header)
+	for key, values := range header {
+		if !contains(skip, http.CanonicalHeaderKey(key)) {
+			clone[key] = append([]string(nil), values...)
+		}
+	}
+	return clone
+}
+
+// normalURL returns rawurl with its query parameters sorted.
+func normalURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil {
+		return rawurl
+	}
+	u.RawQuery = u.Query().Encode()
+	return u.String()
+}
+
+// normalForm returns the parameters in a form body in a canonical
+// encoding, so that requests match regardless of the order of the
+// parameters or of the multipart boundary used. Other bodies are
+// returned unchanged.
+func normalForm(ctype string, body []byte) string {
+	mtype, params, err := mime.ParseMediaType(ctype)
+	if err != nil {
+		return string(body)
+	}
+	switch mtype {
+	case "application/x-www-form-urlencoded":
+		if values, err := url.ParseQuery(string(body)); err == nil {
+			return values.Encode()
+		}
+	case "multipart/form-data":
+		values := make(url.Values)
+		mr := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+		for {
+			part, err := mr.NextPart()
+			if err == io.EOF {
+				return values.Encode()
+			}
+			if err != nil {
+				break
+			}
+			data, err := ioutil.ReadAll(part)
+			if err != nil {
+				break
+			}
+			values.Add(part.FormName(), string(data))
+		}
+	}
+	return string(body)
+}
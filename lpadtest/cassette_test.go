@@ -0,0 +1,140 @@
+package lpadtest_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+	"github.com/canonical/lpad/lpadtest"
+)
+
+var _ = Suite(&RecorderS{})
+
+type RecorderS struct{}
+
+// session runs f with a root logged in as joe, using a recorder in the
+// given mode.
+func session(c *C, base lpad.APIBase, path string, mode lpadtest.Mode, f func(root *lpad.Root)) {
+	rec, err := lpadtest.NewRecorder(path, mode)
+	c.Assert(err, IsNil)
+	root, err := lpad.Login(base, &lpadtest.Auth{Name: "joe"})
+	c.Assert(err, IsNil)
+	root.Session().SetTransport(rec)
+	f(root)
+	c.Assert(rec.Save(), IsNil)
+}
+
+func (s *RecorderS) TestRecordReplay(c *C) {
+	srv := lpadtest.NewServer()
+	srv.AddPerson("joe", "Joe")
+	srv.AddProject("foo", "Foo", "joe")
+	base := srv.APIBase()
+	path := filepath.Join(c.MkDir(), "cassette.json")
+
+	run := func(root *lpad.Root) {
+		me, err := root.Me()
+		c.Assert(err, IsNil)
+		c.Assert(me.DisplayName(), Equals, "Joe")
+		project, err := root.Project("foo")
+		c.Assert(err, IsNil)
+		bug, err := root.CreateBug(&lpad.BugStub{Title: "Broken", Description: "It's broken.", Target: project})
+		c.Assert(err, IsNil)
+		c.Assert(bug.Id(), Equals, 1)
+		bug.SetTitle("Very broken")
+		c.Assert(bug.Patch(), IsNil)
+		project, err = root.Project("foo")
+		c.Assert(err, IsNil)
+	}
+	session(c, base, path, lpadtest.Record, run)
+	srv.Close()
+
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+	c.Assert(strings.Contains(string(data), "Authorization"), Equals, false)
+	c.Assert(strings.Contains(string(data), "lpadtest joe"), Equals, false)
+	c.Assert(strings.Contains(string(data), `"status": 303`), Equals, true)
+	c.Assert(strings.Contains(string(data), `"status": 201`), Equals, true)
+
+	// The server is gone, so responses must come from the cassette.
+	session(c, base, path, lpadtest.Replay, run)
+
+	session(c, base, path, lpadtest.Replay, func(root *lpad.Root) {
+		_, err := root.Bug(2)
+		c.Assert(err, ErrorMatches, `.*: no recorded interaction for GET .*/devel/bugs/2`)
+	})
+}
+
+func (s *RecorderS) TestReplayMatching(c *C) {
+	path := filepath.Join(c.MkDir(), "cassette.json")
+	err := ioutil.WriteFile(path, []byte(`{"interactions": [
+		{"request": {"method": "POST", "url": "http://lp/devel/bugs/1",
+		             "header": {"Content-Type": ["application/x-www-form-urlencoded"]},
+		             "body": "ws.op=newMessage&content=Hi"},
+		 "response": {"status": 200, "header": {"Content-Type": ["application/json"]}, "body": "1"}},
+		{"request": {"method": "GET", "url": "http://lp/devel/bugs?ws.start=2&ws.size=1"},
+		 "response": {"status": 200, "body": "first"}},
+		{"request": {"method": "GET", "url": "http://lp/devel/bugs?ws.size=1&ws.start=2"},
+		 "response": {"status": 200, "body_base64": "/w=="}}
+	]}`), 0644)
+	c.Assert(err, IsNil)
+	rec, err := lpadtest.NewRecorder(path, lpadtest.Replay)
+	c.Assert(err, IsNil)
+
+	post := func(body string) (*http.Response, error) {
+		req, err := http.NewRequest("POST", "http://lp/devel/bugs/1", strings.NewReader(body))
+		c.Assert(err, IsNil)
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		return rec.RoundTrip(req)
+	}
+	_, err = post("ws.op=newMessage&content=Bye")
+	c.Assert(err, ErrorMatches, "no recorded interaction for POST http://lp/devel/bugs/1")
+	resp, err := post("content=Hi&ws.op=newMessage")
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, 200)
+	c.Assert(resp.Header.Get("Content-Type"), Equals, "application/json")
+
+	// Identical requests are served in the recorded order.
+	get := func() string {
+		req, err := http.NewRequest("GET", "http://lp/devel/bugs?ws.size=1&ws.start=2", nil)
+		c.Assert(err, IsNil)
+		resp, err := rec.RoundTrip(req)
+		c.Assert(err, IsNil)
+		data, err := ioutil.ReadAll(resp.Body)
+		c.Assert(err, IsNil)
+		return string(data)
+	}
+	c.Assert(get(), Equals, "first")
+	c.Assert(get(), Equals, "\xff")
+}
+
+func (s *RecorderS) TestRecordMultipart(c *C) {
+	srv := lpadtest.NewServer()
+	defer srv.Close()
+	path := filepath.Join(c.MkDir(), "cassette.json")
+
+	body := "--XXX\r\nContent-Disposition: form-data; name=\"ws.op\"\r\n\r\nupload\r\n--XXX--\r\n"
+	request := func(boundary string) *http.Request {
+		req, err := http.NewRequest("POST", string(srv.APIBase())+"bugs", bytes.NewBufferString(strings.Replace(body, "XXX", boundary, -1)))
+		c.Assert(err, IsNil)
+		req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+		req.Header.Set("Authorization", "lpadtest nobody")
+		return req
+	}
+	rec, err := lpadtest.NewRecorder(path, lpadtest.Record)
+	c.Assert(err, IsNil)
+	resp, err := rec.RoundTrip(request("abc"))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, 401)
+	c.Assert(rec.Save(), IsNil)
+
+	rec, err = lpadtest.NewRecorder(path, lpadtest.Replay)
+	c.Assert(err, IsNil)
+	resp, err = rec.RoundTrip(request("def"))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, 401)
+}
@@ -0,0 +1,241 @@
+package lpadtest
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/canonical/lpad"
+)
+
+var proposalStatuses = strs(
+	lpad.StWorkInProgress, lpad.StNeedsReview, lpad.StApproved, lpad.StRejected,
+	lpad.StMerged, lpad.StFailedToMerge, lpad.StQueued, lpad.StSuperseded,
+)
+
+var votes = strs(
+	lpad.VoteApprove, lpad.VoteNeedsFixing, lpad.VoteNeedsInfo, lpad.VoteAbstain,
+	lpad.VoteDisapprove, lpad.VoteResubmit,
+)
+
+var branches = &kind{
+	ops: map[string]operation{
+		"GET getByUrl": getBranchByURL,
+	},
+}
+
+var branch = &kind{
+	fields: map[string]*field{
+		"lifecycle_status": enumField("Experimental", "Development", "Mature", "Merged", "Abandoned"),
+		"reviewer_link":    linkField("person", "team"),
+		"whiteboard":       textField,
+	},
+	ops: map[string]operation{
+		"POST createMergeProposal": createMergeProposal,
+	},
+}
+
+var mergeProposal = &kind{
+	fields: map[string]*field{
+		"description":    textField,
+		"commit_message": textField,
+	},
+	ops: map[string]operation{
+		"POST setStatus":     setProposalStatus,
+		"POST createComment": createComment,
+	},
+}
+
+// AddBranch adds a Bazaar branch of a project owned by the named person
+// or team, and returns its unique name, as in "~joe/foo/trunk".
+func (s *Server) AddBranch(owner, project, name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.addBranch(s.get("/~"+owner, "person", "team"), s.get("/"+project, "project"), name)
+	return e.fields["unique_name"].(string)
+}
+
+// AddMergeProposal proposes merging the source branch into the target
+// branch, both given by their unique names, and returns the path of
+// the new merge proposal. The proposal is registered by the owner of
+// the source branch, and needs review.
+func (s *Server) AddMergeProposal(source, target string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	src := s.get("/"+source, "branch")
+	mp := s.addProposal(s.lookup(src.fields["owner_link"].(string)), src, s.get("/"+target, "branch"), nil)
+	return mp.path
+}
+
+func (s *Server) addBranch(owner, project *entry, name string) *entry {
+	unique := fmt.Sprintf("~%s/%s/%s", owner.fields["name"], project.fields["name"], name)
+	e := s.add("branch", "/"+unique, map[string]interface{}{
+		"name":             name,
+		"unique_name":      unique,
+		"bzr_identity":     "lp:" + unique,
+		"display_name":     "lp:" + unique,
+		"lifecycle_status": "Development",
+		"whiteboard":       "",
+		"revision_count":   0,
+		"last_scanned_id":  nil,
+		"owner_link":       s.link(owner),
+		"registrant_link":  s.link(owner),
+		"reviewer_link":    s.link(owner),
+		"project_link":     s.link(project),
+		"date_created":     now(),
+		"web_link":         "https://code.launchpad.net/" + unique,
+	})
+	link := s.link(e)
+	e.lists = map[string]func() []*entry{
+		"landing_targets":    s.where("branch_merge_proposal", "source_branch_link", link),
+		"landing_candidates": s.where("branch_merge_proposal", "target_branch_link", link),
+		"linked_bugs": func() []*entry {
+			var list []*entry
+			for _, bb := range s.where("bug_branch", "branch_link", link)() {
+				list = append(list, s.lookup(bb.fields["bug_link"].(string)))
+			}
+			return list
+		},
+	}
+	return e
+}
+
+func (s *Server) addProposal(registrant, source, target, prereq *entry) *entry {
+	id := s.serial("branch_merge_proposal")
+	path := fmt.Sprintf("%s/+merge/%d", source.path, id)
+	var prereqLink interface{}
+	if prereq != nil {
+		prereqLink = s.link(prereq)
+	}
+	e := s.add("branch_merge_proposal", path, map[string]interface{}{
+		"queue_status":             string(lpad.StNeedsReview),
+		"description":              "",
+		"commit_message":           "",
+		"address":                  fmt.Sprintf("mp+%d@code.launchpad.net", id),
+		"source_branch_link":       s.link(source),
+		"target_branch_link":       s.link(target),
+		"prerequisite_branch_link": prereqLink,
+		"registrant_link":          s.link(registrant),
+		"date_created":             now(),
+		"web_link":                 "https://code.launchpad.net" + path,
+	})
+	e.lists = map[string]func() []*entry{
+		"all_comments": s.where("code_review_comment", "branch_merge_proposal_link", s.link(e)),
+	}
+	return e
+}
+
+func getBranchByURL(s *Server, c *call) (interface{}, error) {
+	url, err := c.required("url")
+	if err != nil {
+		return nil, err
+	}
+	name := strings.TrimPrefix(url, "lp:")
+	for _, e := range s.where("branch", "", nil)() {
+		if e.fields["unique_name"] == name {
+			return e, nil
+		}
+	}
+	// As in Launchpad, unknown branches are null rather than missing.
+	return nil, nil
+}
+
+func getBranches(s *Server, c *call) (interface{}, error) {
+	return s.where("branch", "owner_link", s.link(c.entry))(), nil
+}
+
+func createMergeProposal(s *Server, c *call) (interface{}, error) {
+	target, err := s.requiredObject(c, "target_branch", "branch")
+	if err != nil {
+		return nil, err
+	}
+	prereq, err := s.object(c, "prerequisite_branch", "branch")
+	if err != nil {
+		return nil, err
+	}
+	needsReview, err := c.boolean("needs_review")
+	if err != nil {
+		return nil, err
+	}
+	source := c.entry
+	if target == source {
+		return nil, badRequest("Source and target branches must be different.")
+	}
+	if target.fields["project_link"] != source.fields["project_link"] {
+		return nil, badRequest("%s is not mergeable into %s.", source.fields["display_name"], target.fields["display_name"])
+	}
+	for _, mp := range source.lists["landing_targets"]() {
+		if mp.fields["target_branch_link"] == s.link(target) && !contains(finalProposalStatuses, mp.fields["queue_status"].(string)) {
+			return nil, badRequest("There is already a branch merge proposal registered for branch %s to land on %s that is still active.",
+				source.fields["display_name"], target.fields["display_name"])
+		}
+	}
+	mp := s.addProposal(c.me, source, target, prereq)
+	if !needsReview {
+		mp.fields["queue_status"] = string(lpad.StWorkInProgress)
+	}
+	mp.fields["description"] = c.form.Get("initial_comment")
+	mp.fields["commit_message"] = c.form.Get("commit_message")
+	return created{mp}, nil
+}
+
+// finalProposalStatuses holds the statuses of proposals which are done.
+var finalProposalStatuses = strs(lpad.StRejected, lpad.StMerged, lpad.StSuperseded)
+
+func getMergeProposals(s *Server, c *call) (interface{}, error) {
+	statuses, err := c.choices("status", proposalStatuses)
+	if err != nil {
+		return nil, err
+	}
+	return s.find(func(e *entry) bool {
+		return e.kind == "branch_merge_proposal" && e.fields["registrant_link"] == s.link(c.entry) &&
+			(len(statuses) == 0 || contains(statuses, e.fields["queue_status"].(string)))
+	}), nil
+}
+
+// getRequestedReviews finds the proposals whose target branch is
+// reviewed by the person or by one of their teams.
+func getRequestedReviews(s *Server, c *call) (interface{}, error) {
+	statuses, err := c.choices("status", proposalStatuses)
+	if err != nil {
+		return nil, err
+	}
+	return s.find(func(e *entry) bool {
+		if e.kind != "branch_merge_proposal" || len(statuses) > 0 && !contains(statuses, e.fields["queue_status"].(string)) {
+			return false
+		}
+		target := s.lookup(e.fields["target_branch_link"].(string))
+		reviewer, _ := target.fields["reviewer_link"].(string)
+		return reviewer != "" && s.isMember(c.entry, s.lookup(reviewer))
+	}), nil
+}
+
+func setProposalStatus(s *Server, c *call) (interface{}, error) {
+	status, err := c.requiredChoice("status", proposalStatuses)
+	if err != nil {
+		return nil, err
+	}
+	c.entry.fields["queue_status"] = status
+	c.entry.etag++
+	return nil, nil
+}
+
+func createComment(s *Server, c *call) (interface{}, error) {
+	vote, err := c.choice("vote", votes)
+	if err != nil {
+		return nil, err
+	}
+	var voteValue interface{}
+	if vote != "" {
+		voteValue = vote
+	}
+	n := len(c.entry.lists["all_comments"]()) + 1
+	return created{s.add("code_review_comment", fmt.Sprintf("%s/comments/%d", c.entry.path, n), map[string]interface{}{
+		"title":                      c.form.Get("subject"),
+		"message_body":               c.form.Get("content"),
+		"vote":                       voteValue,
+		"vote_tag":                   c.form.Get("review_type"),
+		"author_link":                s.link(c.me),
+		"branch_merge_proposal_link": s.link(c.entry),
+		"date_created":               now(),
+	})}, nil
+}
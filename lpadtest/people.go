@@ -0,0 +1,241 @@
+package lpadtest
+
+import (
+	"strings"
+
+	"github.com/canonical/lpad"
+)
+
+var membershipStatuses = strs(
+	lpad.MembershipProposed, lpad.MembershipApproved, lpad.MembershipAdministrator,
+	lpad.MembershipDeactivated, lpad.MembershipExpired, lpad.MembershipDeclined,
+	lpad.MembershipInvited, lpad.MembershipInvitationDeclined,
+)
+
+// activeStatuses holds the statuses of members who belong to a team.
+var activeStatuses = strs(lpad.MembershipApproved, lpad.MembershipAdministrator)
+
+var people = &kind{
+	ops: map[string]operation{
+		"GET findPerson": findPeople("person"),
+		"GET findTeam":   findPeople("team"),
+		"GET find":       findPeople("person", "team"),
+	},
+}
+
+var personFields = map[string]*field{
+	"display_name":         textField,
+	"time_zone":            textField,
+	"hide_email_addresses": boolField,
+}
+
+var personOps = map[string]operation{
+	"GET getBranches":         getBranches,
+	"GET getMergeProposals":   getMergeProposals,
+	"GET getRequestedReviews": getRequestedReviews,
+	"GET searchTasks":         searchTasks,
+}
+
+var person = &kind{fields: personFields, ops: personOps}
+
+var team = &kind{fields: personFields, ops: withOp(personOps, "POST addMember", addMember)}
+
+var teamMembership = &kind{
+	ops: map[string]operation{
+		"POST setStatus":         setMembershipStatus,
+		"POST setExpirationDate": setExpirationDate,
+	},
+}
+
+// AddPerson adds a person with the given name and display name.
+func (s *Server) AddPerson(name, displayName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addPerson("person", name, displayName)
+}
+
+// AddTeam adds a team with the given name and display name. The owner,
+// a person or team, becomes an administrator of the new team.
+func (s *Server) AddTeam(name, displayName, owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	o := s.get("/~"+owner, "person", "team")
+	t := s.addPerson("team", name, displayName)
+	t.fields["team_owner_link"] = s.link(o)
+	s.setMembership(t, o, string(lpad.MembershipAdministrator), "")
+}
+
+// AddMember adds the named person or team to a team with the given
+// status, or changes the status if it's already a member.
+func (s *Server) AddMember(team, member string, status lpad.MembershipStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := s.get("/~"+team, "team")
+	m := s.get("/~"+member, "person", "team")
+	s.setMembership(t, m, string(status), "")
+}
+
+func (s *Server) addPerson(kind, name, displayName string) *entry {
+	e := s.add(kind, "/~"+name, map[string]interface{}{
+		"name":                         name,
+		"display_name":                 displayName,
+		"is_team":                      kind == "team",
+		"is_valid":                     true,
+		"is_probationary":              false,
+		"karma":                        0,
+		"time_zone":                    "UTC",
+		"hide_email_addresses":         false,
+		"preferred_email_address_link": nil,
+		"date_created":                 now(),
+		"web_link":                     "https://launchpad.net/~" + name,
+	})
+	link := s.link(e)
+	e.lists = map[string]func() []*entry{
+		"ppas": s.where("archive", "owner_link", link),
+		"super_teams": func() []*entry {
+			return s.memberOf("team_link", s.memberships("member_link", link, activeStatuses))
+		},
+		"memberships_details": func() []*entry {
+			return s.memberships("member_link", link, activeStatuses)
+		},
+		"irc_nicknames": none,
+		"sshkeys":       none,
+		"gpg_keys":      none,
+		"jabber_ids":    none,
+	}
+	if kind != "team" {
+		return e
+	}
+	members := func(statuses ...string) func() []*entry {
+		return func() []*entry {
+			return s.memberOf("member_link", s.memberships("team_link", link, statuses))
+		}
+	}
+	e.lists["members"] = members(activeStatuses...)
+	e.lists["admins"] = members(string(lpad.MembershipAdministrator))
+	e.lists["proposed_members"] = members(string(lpad.MembershipProposed))
+	e.lists["invited_members"] = members(string(lpad.MembershipInvited))
+	e.lists["deactivated_members"] = members(string(lpad.MembershipDeactivated))
+	e.lists["expired_members"] = members(string(lpad.MembershipExpired))
+	e.lists["members_details"] = func() []*entry {
+		return s.memberships("team_link", link, activeStatuses)
+	}
+	e.lists["sub_teams"] = func() []*entry {
+		var teams []*entry
+		for _, m := range members(activeStatuses...)() {
+			if m.kind == "team" {
+				teams = append(teams, m)
+			}
+		}
+		return teams
+	}
+	return e
+}
+
+// memberships returns the team memberships with the named link field set
+// to link and one of the given statuses.
+func (s *Server) memberships(name, link string, statuses []string) []*entry {
+	return s.find(func(e *entry) bool {
+		return e.kind == "team_membership" && e.fields[name] == link && contains(statuses, e.fields["status"].(string))
+	})
+}
+
+// memberOf returns the resources linked to by the named field of each
+// of the memberships.
+func (s *Server) memberOf(name string, memberships []*entry) []*entry {
+	var list []*entry
+	for _, m := range memberships {
+		list = append(list, s.lookup(m.fields[name].(string)))
+	}
+	return list
+}
+
+func (s *Server) setMembership(t, member *entry, status, comment string) *entry {
+	path := t.path + "/+member/" + member.fields["name"].(string)
+	m := s.entries[path]
+	if m == nil {
+		m = s.add("team_membership", path, map[string]interface{}{
+			"date_joined":  now(),
+			"date_expires": nil,
+			"member_link":  s.link(member),
+			"team_link":    s.link(t),
+		})
+	}
+	m.fields["status"] = status
+	m.fields["last_change_comment"] = comment
+	m.etag++
+	return m
+}
+
+// isMember returns whether member is an active member of t, directly
+// or through other teams.
+func (s *Server) isMember(member, t *entry) bool {
+	if member == t {
+		return true
+	}
+	for _, m := range s.memberships("member_link", s.link(member), activeStatuses) {
+		if s.isMember(s.lookup(m.fields["team_link"].(string)), t) {
+			return true
+		}
+	}
+	return false
+}
+
+// withOp returns a copy of ops with the given operation added.
+func withOp(ops map[string]operation, name string, op operation) map[string]operation {
+	m := map[string]operation{name: op}
+	for name, op := range ops {
+		m[name] = op
+	}
+	return m
+}
+
+func findPeople(kinds ...string) operation {
+	return func(s *Server, c *call) (interface{}, error) {
+		text := strings.ToLower(c.form.Get("text"))
+		return s.find(func(e *entry) bool {
+			if !contains(kinds, e.kind) {
+				return false
+			}
+			return strings.Contains(e.fields["name"].(string), text) ||
+				strings.Contains(strings.ToLower(e.fields["display_name"].(string)), text)
+		}), nil
+	}
+}
+
+func addMember(s *Server, c *call) (interface{}, error) {
+	member, err := s.requiredObject(c, "person", "person", "team")
+	if err != nil {
+		return nil, err
+	}
+	status, err := c.requiredChoice("status", membershipStatuses)
+	if err != nil {
+		return nil, err
+	}
+	if member.kind == "team" && s.isMember(c.entry, member) {
+		return nil, badRequest("%s is a member of %s already.", c.entry.fields["name"], member.fields["name"])
+	}
+	s.setMembership(c.entry, member, status, c.form.Get("comment"))
+	return nil, nil
+}
+
+func setMembershipStatus(s *Server, c *call) (interface{}, error) {
+	status, err := c.requiredChoice("status", membershipStatuses)
+	if err != nil {
+		return nil, err
+	}
+	c.entry.fields["status"] = status
+	c.entry.fields["last_change_comment"] = c.form.Get("comment")
+	c.entry.etag++
+	return nil, nil
+}
+
+func setExpirationDate(s *Server, c *call) (interface{}, error) {
+	date, err := c.required("date")
+	if err != nil {
+		return nil, err
+	}
+	c.entry.fields["date_expires"] = date
+	c.entry.etag++
+	return nil, nil
+}
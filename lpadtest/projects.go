@@ -0,0 +1,122 @@
+package lpadtest
+
+import (
+	"encoding/json"
+	"regexp"
+
+	"github.com/canonical/lpad"
+)
+
+var projects = &kind{
+	ops: map[string]operation{
+		"POST new_project": newProject,
+	},
+}
+
+var project = &kind{
+	fields: map[string]*field{
+		"display_name":         textField,
+		"title":                textField,
+		"summary":              textField,
+		"description":          textField,
+		"homepage_url":         textField,
+		"programming_language": textField,
+		"licenses":             listField,
+		"official_bug_tags":    listField,
+		"official_bugs":        boolField,
+		"official_codehosting": boolField,
+		"official_answers":     boolField,
+		"translations_usage":   enumField("Unknown", "Launchpad", "External", "Not Applicable"),
+		"owner_link":           linkField("person", "team"),
+		"driver_link":          linkField("person", "team"),
+		"bug_supervisor_link":  linkField("person", "team"),
+	},
+	ops: map[string]operation{
+		"GET searchTasks": searchTasks,
+	},
+}
+
+var knownLicenses = strs(
+	lpad.LicenseAcademicFree, lpad.LicenseAFFERO, lpad.LicenseApache, lpad.LicenseArtistic,
+	lpad.LicenseArtistic2, lpad.LicenseBSD, lpad.LicenseBSDModified, lpad.LicenseCC0,
+	lpad.LicenseEclipse, lpad.LicenseGPL2, lpad.LicenseGPL3, lpad.LicenseLGPL21,
+	lpad.LicenseLGPL3, lpad.LicenseMIT, lpad.LicenseMPL, lpad.LicensePublicDomain,
+	lpad.LicensePython, lpad.LicenseZPL, lpad.LicenseOtherOpen, lpad.LicenseOtherProprietary,
+	lpad.LicenseDontKnow,
+)
+
+var validName = regexp.MustCompile(`^[a-z0-9][a-z0-9\.\-\+]*$`)
+
+// AddProject adds a project with the given name and display name,
+// owned by the named person or team.
+func (s *Server) AddProject(name, displayName, owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addProject(name, displayName, displayName, "", s.get("/~"+owner, "person", "team"))
+}
+
+func (s *Server) addProject(name, displayName, title, summary string, owner *entry) *entry {
+	e := s.add("project", "/"+name, map[string]interface{}{
+		"name":                   name,
+		"display_name":           displayName,
+		"title":                  title,
+		"summary":                summary,
+		"description":            "",
+		"homepage_url":           "",
+		"programming_language":   "",
+		"licenses":               []string{},
+		"official_bug_tags":      []string{},
+		"official_bugs":          false,
+		"official_codehosting":   false,
+		"official_answers":       false,
+		"translations_usage":     "Unknown",
+		"owner_link":             s.link(owner),
+		"driver_link":            nil,
+		"bug_supervisor_link":    nil,
+		"development_focus_link": nil,
+		"date_created":           now(),
+		"web_link":               "https://launchpad.net/" + name,
+	})
+	e.lists = map[string]func() []*entry{
+		"series":            none,
+		"active_milestones": none,
+		"all_milestones":    none,
+	}
+	return e
+}
+
+func newProject(s *Server, c *call) (interface{}, error) {
+	var values [4]string
+	for i, name := range []string{"name", "display_name", "title", "summary"} {
+		value, err := c.required(name)
+		if err != nil {
+			return nil, err
+		}
+		values[i] = value
+	}
+	name := values[0]
+	if !validName.MatchString(name) {
+		return nil, badRequest("name: Invalid name %q.", name)
+	}
+	if e, _ := s.resolve("/" + name); e != nil {
+		return nil, badRequest("name: %s is already used by another project.", name)
+	}
+	var licenses []string
+	if v := c.form.Get("licenses"); v != "" {
+		if err := json.Unmarshal([]byte(v), &licenses); err != nil {
+			return nil, badRequest("licenses: Expected a JSON list.")
+		}
+	}
+	for _, l := range licenses {
+		if !contains(knownLicenses, l) {
+			return nil, invalidValue("licenses", l, knownLicenses)
+		}
+	}
+	e := s.addProject(name, values[1], values[2], values[3], c.me)
+	if licenses != nil {
+		e.fields["licenses"] = licenses
+	}
+	e.fields["description"] = c.form.Get("description")
+	e.fields["homepage_url"] = c.form.Get("home_page_url")
+	return created{e}, nil
+}
@@ -0,0 +1,700 @@
+// The lpadtest package offers an in-memory emulation of the Launchpad
+// API, so that code built on the lpad package may be tested without
+// network access.
+//
+// The emulated Launchpad starts out empty, and is populated by the
+// test before the code under test talks to it. Changes made through
+// the API may then be verified with Entry. For example:
+//
+//	srv := lpadtest.NewServer()
+//	defer srv.Close()
+//	srv.AddPerson("joe", "Joe")
+//	srv.AddProject("foo", "Foo", "joe")
+//	id := srv.AddBug("joe", "Broken", "It's broken.", "foo")
+//
+//	root, err := lpad.Login(srv.APIBase(), &lpadtest.Auth{Name: "joe"})
+//	if err != nil {
+//	    panic(err)
+//	}
+//	codeUnderTest(root)
+//	fmt.Println(srv.Entry(fmt.Sprintf("/foo/+bug/%d", id))["status"])
+//
+// People and teams, projects, bugs and their tasks, Bazaar branches
+// and merge proposals, and PPAs and their builds are supported, along
+// with the named operations lpad uses on them.
+//
+// Tests which must talk to the real Launchpad may instead use a
+// Recorder, so that they're run against it once and replayed offline
+// afterwards.
+package lpadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/canonical/lpad"
+)
+
+// The Server type is an emulated Launchpad, serving its API over HTTP
+// on the local host.
+type Server struct {
+	// PageSize is the number of entries in each page of a collection,
+	// unless the client requests a different size with ws.size.
+	PageSize int
+
+	mu      sync.Mutex
+	server  *httptest.Server
+	base    string
+	entries map[string]*entry
+	order   []*entry
+	aliases map[string]string
+	serials map[string]int
+}
+
+// An entry is a single resource in the emulated Launchpad.
+type entry struct {
+	kind   string
+	path   string
+	fields map[string]interface{}
+	lists  map[string]func() []*entry
+	etag   int
+}
+
+// NewServer starts and returns an emulated Launchpad with no content.
+// Close must be called to shut it down once it's no longer needed.
+func NewServer() *Server {
+	s := &Server{
+		PageSize: 50,
+		entries:  make(map[string]*entry),
+		aliases:  make(map[string]string),
+		serials:  make(map[string]int),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.base = s.server.URL + "/devel"
+	root := s.add("service-root", "", nil)
+	root.lists = map[string]func() []*entry{
+		"people": func() []*entry {
+			return s.find(func(e *entry) bool { return e.kind == "person" || e.kind == "team" })
+		},
+		"projects": s.where("project", "", nil),
+		"bugs":     s.where("bug", "", nil),
+		"branches": s.where("branch", "", nil),
+	}
+	return s
+}
+
+// APIBase returns the base URL of the emulated API, to be provided
+// to lpad.Login.
+func (s *Server) APIBase() lpad.APIBase {
+	return lpad.APIBase(s.base + "/")
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Entry returns the fields of the resource at path, such as "/~joe" or
+// "/bugs/1", as they would be sent to clients, or nil if there's no
+// resource at path. The returned map is a copy, and may be changed.
+func (s *Server) Entry(path string) map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, list := s.resolve(strings.TrimSuffix(path, "/"))
+	if e == nil || list != "" {
+		return nil
+	}
+	return s.repr(e)
+}
+
+// The Auth type authenticates requests to the emulated Launchpad as
+// the person with the given name. An empty name means anonymous access,
+// which as in Launchpad may only read content.
+type Auth struct {
+	Name string
+}
+
+// Login does nothing, as the emulated Launchpad trusts all names.
+func (a *Auth) Login(baseURL string) error {
+	return nil
+}
+
+// Sign adds the person name to req.
+func (a *Auth) Sign(req *http.Request) error {
+	if a.Name != "" {
+		req.Header.Set("Authorization", "lpadtest "+a.Name)
+	}
+	return nil
+}
+
+// A kind describes how resources of a given type may be changed.
+type kind struct {
+	// fields holds the fields which may be changed with PATCH.
+	fields map[string]*field
+
+	// ops holds the named operations, keyed by the HTTP method
+	// and the operation name, as in "GET getBranches".
+	ops map[string]operation
+}
+
+type operation func(s *Server, c *call) (interface{}, error)
+
+var kinds = map[string]*kind{
+	"people":                people,
+	"person":                person,
+	"team":                  team,
+	"team_membership":       teamMembership,
+	"projects":              projects,
+	"project":               project,
+	"bugs":                  bugs,
+	"bug":                   bug,
+	"bug_task":              bugTask,
+	"branches":              branches,
+	"branch":                branch,
+	"branch_merge_proposal": mergeProposal,
+	"archive":               archive,
+	"build":                 build,
+}
+
+// A field describes the values accepted for a writable field.
+type field struct {
+	typ     string   // "string", "bool", "list" or "link"
+	options []string // Values accepted for a string, if restricted
+	links   []string // Kinds of resource a link may point to
+}
+
+var (
+	textField = &field{typ: "string"}
+	boolField = &field{typ: "bool"}
+	listField = &field{typ: "list"}
+)
+
+func enumField(options ...string) *field {
+	return &field{typ: "string", options: options}
+}
+
+func linkField(kinds ...string) *field {
+	return &field{typ: "link", links: kinds}
+}
+
+// A call holds the details of a request being handled.
+type call struct {
+	path  string
+	entry *entry
+	query url.Values
+	form  url.Values
+	me    *entry
+}
+
+// required returns the named parameter, or an error if it's missing.
+func (c *call) required(name string) (string, error) {
+	value := c.form.Get(name)
+	if value == "" {
+		return "", badRequest("%s: Required input is missing.", name)
+	}
+	return value, nil
+}
+
+// boolean returns the named boolean parameter, which is false if missing.
+func (c *call) boolean(name string) (bool, error) {
+	switch c.form.Get(name) {
+	case "", "false":
+		return false, nil
+	case "true":
+		return true, nil
+	}
+	return false, badRequest("%s: Invalid boolean value %q.", name, c.form.Get(name))
+}
+
+// choice returns the named parameter, which if present must be one
+// of the given options.
+func (c *call) choice(name string, options []string) (string, error) {
+	value := c.form.Get(name)
+	if value != "" && !contains(options, value) {
+		return "", invalidValue(name, value, options)
+	}
+	return value, nil
+}
+
+// requiredChoice works like choice, but fails if the parameter is missing.
+func (c *call) requiredChoice(name string, options []string) (string, error) {
+	if _, err := c.required(name); err != nil {
+		return "", err
+	}
+	return c.choice(name, options)
+}
+
+// choices returns all values of the named parameter, each of which
+// must be one of the given options.
+func (c *call) choices(name string, options []string) ([]string, error) {
+	values := c.form[name]
+	for _, value := range values {
+		if !contains(options, value) {
+			return nil, invalidValue(name, value, options)
+		}
+	}
+	return values, nil
+}
+
+// object returns the resource linked to by the named parameter, or
+// nil if the parameter is missing.
+func (s *Server) object(c *call, name string, kinds ...string) (*entry, error) {
+	value := c.form.Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	e := s.lookup(value, kinds...)
+	if e == nil {
+		return nil, badRequest("%s: No such object %q.", name, value)
+	}
+	return e, nil
+}
+
+// requiredObject works like object, but fails if the parameter is missing.
+func (s *Server) requiredObject(c *call, name string, kinds ...string) (*entry, error) {
+	if _, err := c.required(name); err != nil {
+		return nil, err
+	}
+	return s.object(c, name, kinds...)
+}
+
+type httpError struct {
+	status int
+	msg    string
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...interface{}) error {
+	return &httpError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func invalidValue(name, value string, options []string) error {
+	return badRequest("%s: Invalid value %q. Acceptable values are: %s", name, value, strings.Join(options, ", "))
+}
+
+var errUnauthorized = &httpError{http.StatusUnauthorized, "Unauthorized"}
+
+// A created result makes an operation reply with 201 and the location
+// of the new resource.
+type created struct {
+	*entry
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, value, err := s.handle(req)
+	if err != nil {
+		herr, ok := err.(*httpError)
+		if !ok {
+			herr = &httpError{http.StatusInternalServerError, err.Error()}
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(herr.status)
+		fmt.Fprint(w, herr.msg)
+		return
+	}
+	if status == http.StatusCreated || status == http.StatusSeeOther {
+		w.Header().Set("Location", value.(string))
+		w.WriteHeader(status)
+		return
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func (s *Server) handle(req *http.Request) (status int, value interface{}, err error) {
+	path := strings.TrimPrefix(req.URL.Path, "/devel")
+	if path == req.URL.Path {
+		return 0, nil, &httpError{http.StatusNotFound, "Not found"}
+	}
+	c := &call{path: strings.TrimSuffix(path, "/"), query: req.URL.Query()}
+	if auth := req.Header.Get("Authorization"); strings.HasPrefix(auth, "lpadtest ") {
+		name := strings.TrimPrefix(auth, "lpadtest ")
+		if c.me = s.entries["/~"+name]; c.me == nil {
+			return 0, nil, &httpError{http.StatusUnauthorized, "Unknown person: " + name}
+		}
+	}
+	if c.path == "/people/+me" {
+		if c.me == nil {
+			return 0, nil, errUnauthorized
+		}
+		return http.StatusSeeOther, s.link(c.me), nil
+	}
+
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	c.form = req.URL.Query()
+	if req.Method == "POST" {
+		form, err := url.ParseQuery(string(body))
+		if err != nil {
+			return 0, nil, badRequest("Invalid form data: %v", err)
+		}
+		for key, values := range form {
+			c.form[key] = append(c.form[key], values...)
+		}
+	}
+
+	e, list := s.resolve(c.path)
+	if e == nil {
+		return 0, nil, &httpError{http.StatusNotFound, "Object not found: " + c.path}
+	}
+	c.entry = e
+	if req.Method != "GET" && c.me == nil {
+		return 0, nil, errUnauthorized
+	}
+
+	var result interface{}
+	name := c.form.Get("ws.op")
+	switch {
+	case req.Method == "GET" && name == "" && list == "":
+		result = e
+	case req.Method == "GET" && name == "":
+		result = e.lists[list]()
+	case req.Method == "GET" || req.Method == "POST":
+		if name == "" {
+			return 0, nil, badRequest("No operation name given.")
+		}
+		target := e.kind
+		if list != "" {
+			target = list
+		}
+		var op operation
+		if k := kinds[target]; k != nil {
+			op = k.ops[req.Method+" "+name]
+		}
+		if op == nil {
+			return 0, nil, badRequest("No such operation: %s", name)
+		}
+		if result, err = op(s, c); err != nil {
+			return 0, nil, err
+		}
+	case req.Method == "PATCH" && list == "":
+		if ctype := req.Header.Get("Content-Type"); ctype != "application/json" {
+			return 0, nil, &httpError{http.StatusUnsupportedMediaType, "Unsupported media type: " + ctype}
+		}
+		if err := s.patch(e, body); err != nil {
+			return 0, nil, err
+		}
+		return 209, s.repr(e), nil
+	default:
+		return 0, nil, &httpError{http.StatusMethodNotAllowed, "Method not allowed: " + req.Method}
+	}
+
+	switch r := result.(type) {
+	case *entry:
+		if r == nil {
+			return http.StatusOK, nil, nil
+		}
+		return http.StatusOK, s.repr(r), nil
+	case []*entry:
+		page, err := s.page(c, r)
+		return http.StatusOK, page, err
+	case created:
+		return http.StatusCreated, s.link(r.entry), nil
+	}
+	return http.StatusOK, result, nil
+}
+
+// resolve returns the resource at path and, if path refers to one of
+// its collections instead, the collection name.
+func (s *Server) resolve(path string) (e *entry, list string) {
+	if alias, ok := s.aliases[path]; ok {
+		path = alias
+	}
+	if e := s.entries[path]; e != nil {
+		return e, ""
+	}
+	if i := strings.LastIndex(path, "/"); i >= 0 {
+		if e := s.entries[path[:i]]; e != nil && e.lists[path[i+1:]] != nil {
+			return e, path[i+1:]
+		}
+	}
+	return nil, ""
+}
+
+// lookup returns the resource at the API address link, provided it's
+// of one of the given kinds, or nil otherwise.
+func (s *Server) lookup(link string, kinds ...string) *entry {
+	if !strings.HasPrefix(link, s.base+"/") {
+		return nil
+	}
+	e := s.entries[strings.TrimSuffix(strings.TrimPrefix(link, s.base), "/")]
+	if e == nil || len(kinds) > 0 && !contains(kinds, e.kind) {
+		return nil
+	}
+	return e
+}
+
+// link returns the API address of e.
+func (s *Server) link(e *entry) string {
+	if e.path == "" {
+		return s.base + "/"
+	}
+	return s.base + e.path
+}
+
+// repr returns the representation of e sent to clients.
+func (s *Server) repr(e *entry) map[string]interface{} {
+	m := make(map[string]interface{}, len(e.fields)+len(e.lists)+3)
+	for key, value := range e.fields {
+		m[key] = value
+	}
+	for name := range e.lists {
+		m[name+"_collection_link"] = s.base + e.path + "/" + name
+	}
+	m["self_link"] = s.link(e)
+	m["resource_type_link"] = s.base + "/#" + e.kind
+	m["http_etag"] = fmt.Sprintf(`"%s-%d"`, e.kind, e.etag)
+	return m
+}
+
+// page returns the page of entries requested in c.
+func (s *Server) page(c *call, entries []*entry) (map[string]interface{}, error) {
+	start, size := 0, s.PageSize
+	var err error
+	if v := c.query.Get("ws.start"); v != "" {
+		if start, err = strconv.Atoi(v); err != nil || start < 0 {
+			return nil, badRequest("ws.start: Invalid value %q.", v)
+		}
+	}
+	if v := c.query.Get("ws.size"); v != "" {
+		if size, err = strconv.Atoi(v); err != nil || size < 1 {
+			return nil, badRequest("ws.size: Invalid value %q.", v)
+		}
+	}
+	end := start + size
+	if end > len(entries) {
+		end = len(entries)
+	}
+	list := []interface{}{}
+	for i := start; i < end; i++ {
+		list = append(list, s.repr(entries[i]))
+	}
+	page := map[string]interface{}{
+		"total_size": len(entries),
+		"start":      start,
+		"entries":    list,
+	}
+	if end < len(entries) {
+		page["next_collection_link"] = s.pageLink(c, end, size)
+	}
+	if start > 0 {
+		prev := start - size
+		if prev < 0 {
+			prev = 0
+		}
+		page["prev_collection_link"] = s.pageLink(c, prev, size)
+	}
+	return page, nil
+}
+
+func (s *Server) pageLink(c *call, start, size int) string {
+	query := make(url.Values)
+	for key, values := range c.query {
+		query[key] = values
+	}
+	query.Set("ws.start", strconv.Itoa(start))
+	query.Set("ws.size", strconv.Itoa(size))
+	return s.base + c.path + "?" + query.Encode()
+}
+
+// patch changes the fields of e as requested in body, a JSON object.
+func (s *Server) patch(e *entry, body []byte) error {
+	var changes map[string]interface{}
+	if err := json.Unmarshal(body, &changes); err != nil {
+		return badRequest("Expected a JSON object: %v", err)
+	}
+	var fields map[string]*field
+	if k := kinds[e.kind]; k != nil {
+		fields = k.fields
+	}
+	var names []string
+	for name := range changes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	values := make(map[string]interface{})
+	var problems []string
+	current := s.repr(e)
+	for _, name := range names {
+		f, ok := fields[name]
+		if !ok {
+			// Read-only fields may be sent back unchanged.
+			if !reflect.DeepEqual(changes[name], jsonValue(current[name])) {
+				problems = append(problems, name+": You tried to modify a read-only attribute.")
+			}
+			continue
+		}
+		value, err := s.check(name, f, changes[name])
+		if err != nil {
+			problems = append(problems, err.Error())
+			continue
+		}
+		values[name] = value
+	}
+	if len(problems) > 0 {
+		return badRequest("%s", strings.Join(problems, "\n"))
+	}
+	for name, value := range values {
+		e.fields[name] = value
+	}
+	e.etag++
+	return nil
+}
+
+// check verifies that value is acceptable for the named field f, and
+// returns it in the form it's stored.
+func (s *Server) check(name string, f *field, value interface{}) (interface{}, error) {
+	switch f.typ {
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s: Expected a string.", name)
+		}
+		if f.options != nil && !contains(f.options, str) {
+			return nil, invalidValue(name, str, f.options)
+		}
+		return str, nil
+	case "bool":
+		if _, ok := value.(bool); !ok {
+			return nil, fmt.Errorf("%s: Expected a boolean.", name)
+		}
+		return value, nil
+	case "list":
+		items, ok := value.([]interface{})
+		list := []string{}
+		for _, item := range items {
+			str, isString := item.(string)
+			if !isString {
+				ok = false
+				break
+			}
+			list = append(list, str)
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: Expected a list of strings.", name)
+		}
+		return list, nil
+	case "link":
+		if value == nil {
+			return nil, nil
+		}
+		link, ok := value.(string)
+		if !ok || s.lookup(link, f.links...) == nil {
+			return nil, fmt.Errorf("%s: No such object %v.", name, value)
+		}
+		return link, nil
+	}
+	panic("unknown field type: " + f.typ)
+}
+
+// jsonValue returns value as it would be decoded from JSON.
+func jsonValue(value interface{}) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		panic(err)
+	}
+	var result interface{}
+	json.Unmarshal(data, &result)
+	return result
+}
+
+// add registers a new resource at path, panicking if the path is taken.
+func (s *Server) add(kind, path string, fields map[string]interface{}) *entry {
+	if _, ok := s.entries[path]; ok {
+		panic("lpadtest: " + path + " already exists")
+	}
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	e := &entry{kind: kind, path: path, fields: fields}
+	s.entries[path] = e
+	s.order = append(s.order, e)
+	return e
+}
+
+// serial returns the next number in the sequence for kind, starting at 1.
+func (s *Server) serial(kind string) int {
+	s.serials[kind]++
+	return s.serials[kind]
+}
+
+// find returns the resources for which f returns true, in the order
+// they were added.
+func (s *Server) find(f func(e *entry) bool) []*entry {
+	var found []*entry
+	for _, e := range s.order {
+		if f(e) {
+			found = append(found, e)
+		}
+	}
+	return found
+}
+
+// where returns a function listing the resources of the given kind
+// with the named field set to value. An empty name lists all of them.
+func (s *Server) where(kind, name string, value interface{}) func() []*entry {
+	return func() []*entry {
+		return s.find(func(e *entry) bool {
+			return e.kind == kind && (name == "" || e.fields[name] == value)
+		})
+	}
+}
+
+// get returns the resource at path, panicking if there's no resource
+// of one of the given kinds there. It's used by the methods setting
+// up content, which are called by tests.
+func (s *Server) get(path string, kinds ...string) *entry {
+	e := s.entries[path]
+	if e == nil || !contains(kinds, e.kind) {
+		panic(fmt.Sprintf("lpadtest: no %s at %s", strings.Join(kinds, " or "), path))
+	}
+	return e
+}
+
+func none() []*entry {
+	return nil
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func strs(values ...interface{}) []string {
+	var list []string
+	for _, v := range values {
+		list = append(list, fmt.Sprint(v))
+	}
+	return list
+}
+
+func now() string {
+	return time.Now().UTC().Format("2006-01-02T15:04:05.000000+00:00")
+}
@@ -0,0 +1,333 @@
+package lpadtest_test
+
+import (
+	"fmt"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+	"github.com/canonical/lpad/lpadtest"
+)
+
+var _ = Suite(&ServerS{})
+
+type ServerS struct {
+	srv  *lpadtest.Server
+	root *lpad.Root
+}
+
+func (s *ServerS) SetUpTest(c *C) {
+	s.srv = lpadtest.NewServer()
+	s.srv.AddPerson("joe", "Joe")
+	s.srv.AddPerson("bob", "Bob")
+	s.srv.AddProject("foo", "Foo", "joe")
+	s.root = s.login(c, "joe")
+}
+
+func (s *ServerS) TearDownTest(c *C) {
+	s.srv.Close()
+}
+
+func (s *ServerS) login(c *C, name string) *lpad.Root {
+	root, err := lpad.Login(s.srv.APIBase(), &lpadtest.Auth{Name: name})
+	c.Assert(err, IsNil)
+	return root
+}
+
+func (s *ServerS) TestMe(c *C) {
+	me, err := s.root.Me()
+	c.Assert(err, IsNil)
+	c.Assert(me.Name(), Equals, "joe")
+	c.Assert(me.DisplayName(), Equals, "Joe")
+	c.Assert(me.AbsLoc(), Equals, string(s.srv.APIBase())+"~joe")
+
+	_, err = s.login(c, "").Me()
+	c.Assert(err, ErrorMatches, "Server returned 401 and body: Unauthorized")
+}
+
+func (s *ServerS) TestNotFound(c *C) {
+	_, err := s.root.Member("nobody")
+	c.Assert(err, Equals, lpad.ErrNotFound)
+	c.Assert(s.srv.Entry("/~nobody"), IsNil)
+}
+
+func (s *ServerS) TestTeams(c *C) {
+	s.srv.AddTeam("devs", "Developers", "joe")
+	s.srv.AddMember("devs", "bob", lpad.MembershipApproved)
+
+	m, err := s.root.Member("devs")
+	c.Assert(err, IsNil)
+	team, ok := m.(*lpad.Team)
+	c.Assert(ok, Equals, true)
+	owner, err := team.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "joe")
+
+	names := func(status lpad.MembershipStatus) []string {
+		list, err := team.Members(status)
+		c.Assert(err, IsNil)
+		var names []string
+		list.For(func(m lpad.Member) error {
+			names = append(names, m.Name())
+			return nil
+		})
+		return names
+	}
+	c.Assert(names(lpad.MembershipApproved), DeepEquals, []string{"joe", "bob"})
+	c.Assert(names(lpad.MembershipAdministrator), DeepEquals, []string{"joe"})
+
+	bob, err := s.root.Member("bob")
+	c.Assert(err, IsNil)
+	err = team.SetMembershipStatus(bob, lpad.MembershipDeactivated, "Bye.")
+	c.Assert(err, IsNil)
+	c.Assert(names(lpad.MembershipApproved), DeepEquals, []string{"joe"})
+	c.Assert(names(lpad.MembershipDeactivated), DeepEquals, []string{"bob"})
+	c.Assert(s.srv.Entry("/~devs/+member/bob")["last_change_comment"], Equals, "Bye.")
+
+	err = team.AddMember(bob, "Bogus", "")
+	c.Assert(err, ErrorMatches, `.*status: Invalid value "Bogus"\. Acceptable values are: Proposed, .*`)
+
+	list, err := s.root.FindTeams("dev")
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 1)
+}
+
+func (s *ServerS) TestPatch(c *C) {
+	project, err := s.root.Project("foo")
+	c.Assert(err, IsNil)
+	c.Assert(project.DisplayName(), Equals, "Foo")
+	project.SetSummary("A project.")
+	project.SetLicenses([]lpad.License{lpad.LicenseMIT})
+	c.Assert(project.Patch(), IsNil)
+	entry := s.srv.Entry("/foo")
+	c.Assert(entry["summary"], Equals, "A project.")
+	c.Assert(entry["licenses"], DeepEquals, []string{"MIT / X / Expat Licence"})
+
+	bob, err := s.root.Member("bob")
+	c.Assert(err, IsNil)
+	project.SetOwner(bob)
+	c.Assert(project.Patch(), IsNil)
+	owner, err := project.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "bob")
+
+	project.SetName("bar")
+	err = project.Patch()
+	c.Assert(err, ErrorMatches, "Server returned 400 and body: name: You tried to modify a read-only attribute.")
+}
+
+func (s *ServerS) TestPatchReturnsContent(c *C) {
+	loc := string(s.srv.APIBase()) + "foo"
+	v, err := s.root.Location(loc).Get(nil)
+	c.Assert(err, IsNil)
+	etag := v.StringField("http_etag")
+	v.SetField("title", "The Foo")
+	c.Assert(v.Patch(), IsNil)
+
+	v, err = s.root.Location(loc).Get(nil)
+	c.Assert(err, IsNil)
+	c.Assert(v.StringField("title"), Equals, "The Foo")
+	c.Assert(v.StringField("http_etag"), Not(Equals), etag)
+}
+
+func (s *ServerS) TestAnonymousWrite(c *C) {
+	project, err := s.login(c, "").Project("foo")
+	c.Assert(err, IsNil)
+	project.SetSummary("Spam.")
+	c.Assert(project.Patch(), ErrorMatches, "Server returned 401 .*")
+}
+
+func (s *ServerS) TestCreateProject(c *C) {
+	project, err := s.root.CreateProject(&lpad.ProjectStub{
+		Name:        "bar",
+		DisplayName: "Bar",
+		Title:       "The Bar",
+		Summary:     "Bar summary.",
+		Licenses:    []lpad.License{lpad.LicenseGPL3},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(project.Name(), Equals, "bar")
+	c.Assert(project.Licenses(), DeepEquals, []lpad.License{lpad.LicenseGPL3})
+	c.Assert(project.AbsLoc(), Equals, string(s.srv.APIBase())+"bar")
+	c.Assert(s.srv.Entry("/bar")["owner_link"], Equals, string(s.srv.APIBase())+"~joe")
+
+	_, err = s.root.CreateProject(&lpad.ProjectStub{Name: "foo", DisplayName: "Foo", Title: "Foo", Summary: "Foo."})
+	c.Assert(err, ErrorMatches, ".*name: foo is already used by another project.")
+}
+
+func (s *ServerS) TestBugs(c *C) {
+	project, err := s.root.Project("foo")
+	c.Assert(err, IsNil)
+	bug, err := s.root.CreateBug(&lpad.BugStub{
+		Title:       "Broken",
+		Description: "It's broken.",
+		Target:      project,
+		Tags:        []string{"crash"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(bug.Id(), Equals, 1)
+	c.Assert(bug.Tags(), DeepEquals, []string{"crash"})
+
+	c.Assert(bug.AddComment("", "Me too."), IsNil)
+	c.Assert(s.srv.Entry("/bugs/1/messages/1")["subject"], Equals, "Re: Broken")
+
+	tasks, err := bug.Tasks()
+	c.Assert(err, IsNil)
+	c.Assert(tasks.TotalSize(), Equals, 1)
+	var task *lpad.BugTask
+	tasks.For(func(t *lpad.BugTask) error {
+		task = t
+		return nil
+	})
+	c.Assert(task.TargetName(), Equals, "foo")
+	c.Assert(task.Status(), Equals, lpad.StNew)
+
+	task.SetStatus(lpad.StFixReleased)
+	task.SetImportance(lpad.ImHigh)
+	c.Assert(task.Patch(), IsNil)
+	c.Assert(s.srv.Entry("/foo/+bug/1")["status"], Equals, "Fix Released")
+
+	task.SetStatus("Fixed")
+	c.Assert(task.Patch(), ErrorMatches, `.*status: Invalid value "Fixed"\. Acceptable values are: .*`)
+}
+
+func (s *ServerS) TestSearchTasks(c *C) {
+	s.srv.AddBug("joe", "One", "One.", "foo")
+	s.srv.AddBug("bob", "Two", "Two.", "foo")
+	bob, err := s.root.Member("bob")
+	c.Assert(err, IsNil)
+
+	search := func(role lpad.BugTaskRole, status lpad.BugStatus) []string {
+		list, err := bob.(*lpad.Person).SearchTasks(role, status)
+		c.Assert(err, IsNil)
+		var titles []string
+		list.For(func(t *lpad.BugTask) error {
+			titles = append(titles, t.StringField("title"))
+			return nil
+		})
+		return titles
+	}
+	c.Assert(search(lpad.TaskReporter, ""), DeepEquals, []string{`Bug #2 in Foo: "Two"`})
+	c.Assert(search(lpad.TaskAssignee, ""), IsNil)
+	c.Assert(search(lpad.TaskReporter, lpad.StFixReleased), IsNil)
+}
+
+func (s *ServerS) TestPagination(c *C) {
+	s.srv.PageSize = 2
+	for i := 1; i <= 5; i++ {
+		s.srv.AddBug("joe", fmt.Sprintf("Bug %d", i), "Broken.", "foo")
+	}
+	list, err := s.root.Location("/bugs").Get(nil)
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 5)
+	c.Assert(list.StartIndex(), Equals, 0)
+	c.Assert(list.Map()["prev_collection_link"], IsNil)
+	var titles []string
+	err = list.For(func(v *lpad.Value) error {
+		titles = append(titles, v.StringField("title"))
+		return nil
+	})
+	c.Assert(err, IsNil)
+	c.Assert(titles, DeepEquals, []string{"Bug 1", "Bug 2", "Bug 3", "Bug 4", "Bug 5"})
+
+	page, err := list.Link("next_collection_link").Get(nil)
+	c.Assert(err, IsNil)
+	c.Assert(page.StartIndex(), Equals, 2)
+	c.Assert(page.StringField("prev_collection_link"), Matches, `.*/bugs\?ws\.size=2&ws\.start=0`)
+}
+
+func (s *ServerS) TestUnknownOperation(c *C) {
+	_, err := s.root.Location("/foo").Get(lpad.Params{"ws.op": "frobnicate"})
+	c.Assert(err, ErrorMatches, "Server returned 400 and body: No such operation: frobnicate")
+	_, err = s.root.Location("/foo").Post(nil)
+	c.Assert(err, ErrorMatches, "Server returned 400 and body: No operation name given.")
+}
+
+func (s *ServerS) TestMergeProposals(c *C) {
+	s.srv.AddBranch("joe", "foo", "trunk")
+	fix := s.srv.AddBranch("bob", "foo", "fix")
+	c.Assert(fix, Equals, "~bob/foo/fix")
+
+	bobRoot := s.login(c, "bob")
+	source, err := bobRoot.Branch("lp:~bob/foo/fix")
+	c.Assert(err, IsNil)
+	c.Assert(source.UniqueName(), Equals, "~bob/foo/fix")
+	target, err := bobRoot.Branch("lp:~joe/foo/trunk")
+	c.Assert(err, IsNil)
+
+	_, err = bobRoot.Branch("lp:~bob/foo/missing")
+	c.Assert(err, Equals, lpad.ErrNotFound)
+
+	mp, err := source.ProposeMerge(&lpad.MergeStub{
+		Description: "Fixes it.",
+		NeedsReview: true,
+		Target:      target,
+	})
+	c.Assert(err, IsNil)
+	c.Assert(mp.Status(), Equals, lpad.StNeedsReview)
+	c.Assert(mp.Description(), Equals, "Fixes it.")
+	c.Assert(mp.AbsLoc(), Equals, string(s.srv.APIBase())+"~bob/foo/fix/+merge/1")
+
+	_, err = source.ProposeMerge(&lpad.MergeStub{Target: target})
+	c.Assert(err, ErrorMatches, ".*There is already a branch merge proposal registered .*")
+
+	// The owner of the target branch is asked to review it.
+	joe, err := s.root.Me()
+	c.Assert(err, IsNil)
+	reviews, err := joe.RequestedReviews(lpad.StNeedsReview)
+	c.Assert(err, IsNil)
+	c.Assert(reviews.TotalSize(), Equals, 1)
+
+	c.Assert(mp.AddComment("Review: Approve", "LGTM", lpad.VoteApprove, ""), IsNil)
+	c.Assert(mp.SetStatus(lpad.StApproved), IsNil)
+	entry := s.srv.Entry("/~bob/foo/fix/+merge/1")
+	c.Assert(entry["queue_status"], Equals, "Approved")
+	c.Assert(s.srv.Entry("/~bob/foo/fix/+merge/1/comments/1")["vote"], Equals, "Approve")
+
+	list, err := target.LandingCandidates()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 1)
+}
+
+func (s *ServerS) TestBuilds(c *C) {
+	s.srv.AddPPA("joe", "ppa")
+	s.srv.AddBuild("joe", "ppa", "foo", "1.0-1", "amd64", lpad.BSSuccessfullyBuilt)
+	id := s.srv.AddBuild("joe", "ppa", "foo", "1.0-1", "i386", lpad.BSFailedToBuild)
+	s.srv.AddBuild("joe", "ppa", "bar", "2.0", "i386", lpad.BSFailedToBuild)
+
+	joe, err := s.root.Me()
+	c.Assert(err, IsNil)
+	ppas, err := joe.PPAs()
+	c.Assert(err, IsNil)
+	var archive *lpad.Archive
+	ppas.For(func(a *lpad.Archive) error {
+		archive = a
+		return nil
+	})
+	c.Assert(archive.Name(), Equals, "ppa")
+	c.Assert(archive.DisplayName(), Equals, "PPA for Joe")
+
+	builds, err := archive.Builds(lpad.BSFailedToBuild, "foo")
+	c.Assert(err, IsNil)
+	var titles []string
+	builds.For(func(b *lpad.Build) error {
+		titles = append(titles, b.Title())
+		return nil
+	})
+	c.Assert(titles, DeepEquals, []string{"i386 build of foo 1.0-1 in ubuntu"})
+
+	b, err := s.root.Build("ubuntu", "foo", "1.0-1", id)
+	c.Assert(err, IsNil)
+	c.Assert(b.State(), Equals, lpad.BSFailedToBuild)
+	c.Assert(b.Retry(), IsNil)
+	entry := s.srv.Entry(fmt.Sprintf("/~joe/+archive/ubuntu/ppa/+build/%d", id))
+	c.Assert(entry["buildstate"], Equals, "Needs building")
+	c.Assert(b.Retry(), ErrorMatches, ".*cannot be retried.")
+
+	pub, err := b.Publication()
+	c.Assert(err, IsNil)
+	c.Assert(pub.PackageName(), Equals, "foo")
+	pubs, err := archive.Publication("foo", lpad.PubPublished)
+	c.Assert(err, IsNil)
+	c.Assert(pubs.TotalSize(), Equals, 1)
+}
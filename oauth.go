@@ -1,7 +1,6 @@
 package lpad
 
 import (
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -31,6 +30,7 @@ type OAuth struct {
 	Token, TokenSecret string             // Credentials obtained
 	Consumer           string             // Consumer name. Defaults to "https://launchpad.net/lpad"
 	Anonymous          bool               // Don't try to login
+	Store              CredentialStore    // Only used by StoredOAuth and ConsoleOAuth. Defaults to a FileStore
 }
 
 func (oauth *OAuth) consumer() string {
@@ -147,7 +147,9 @@ func (oauth *OAuth) Sign(req *http.Request) error {
 }
 
 // The StoredOAuth type behaves like OAuth, but will cache a successful
-// authentication in ~/.lpad_oauth and reuse it in future Login requests.
+// authentication in its Store and reuse it in future Login requests.
+// Credentials are stored under a profile for the API base URL and the
+// consumer, so distinct identities may be kept for each of them.
 //
 // See the OAuth type for details on how to construct values of this type,
 // and see the Login method for a convenient way to make use of them.
@@ -162,57 +164,37 @@ type oauthDump struct {
 }
 
 func (oauth *StoredOAuth) Login(baseURL string) error {
-	if oauth.TokenSecret == "" && oauth.read() == nil {
-		return nil
+	if oauth.Anonymous {
+		return (*OAuth)(oauth).Login(baseURL)
+	}
+	profile := credentialProfile(baseURL, (*OAuth)(oauth).consumer())
+	if oauth.TokenSecret == "" {
+		token, secret, err := oauth.store().Load(profile)
+		if err == nil {
+			oauth.Token = token
+			oauth.TokenSecret = secret
+			return nil
+		}
+		if err != ErrNoCredentials {
+			return err
+		}
 	}
 	err := (*OAuth)(oauth).Login(baseURL)
 	if err != nil {
 		return err
 	}
-	return oauth.write()
+	return oauth.store().Save(profile, oauth.Token, oauth.TokenSecret)
 }
 
 func (oauth *StoredOAuth) Sign(req *http.Request) error {
 	return (*OAuth)(oauth).Sign(req)
 }
 
-func (oauth *StoredOAuth) read() error {
-	path := os.ExpandEnv("$HOME/.lpad_oauth")
-	if oauth.TokenSecret != "" {
-		return nil
-	}
-	file, err := os.Open(path)
-	if err != nil {
-		return err
-	}
-	data, err := ioutil.ReadAll(file)
-	file.Close()
-	if err != nil {
-		return err
-	}
-	dump := &oauthDump{}
-	err = json.Unmarshal(data, dump)
-	if err != nil {
-		return err
-	}
-	oauth.Token = dump.Token
-	oauth.TokenSecret = dump.TokenSecret
-	return nil
-}
-
-func (oauth *StoredOAuth) write() error {
-	path := os.ExpandEnv("$HOME/.lpad_oauth")
-	file, err := os.Create(path)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-	data, err := json.Marshal(&oauthDump{oauth.Token, oauth.TokenSecret})
-	if err != nil {
-		return err
+func (oauth *StoredOAuth) store() CredentialStore {
+	if oauth.Store == nil {
+		return &FileStore{}
 	}
-	_, err = file.Write(data)
-	return err
+	return oauth.Store
 }
 
 // The ConsoleOAuth type will cache successful authentications like
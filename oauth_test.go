@@ -241,11 +241,14 @@ func (s *OAuthS) TestDontLoginWithAnonymous(c *C) {
 
 func fakeHome(c *C) (dir string, restore func()) {
 	realHome := os.Getenv("HOME")
+	realConfig := os.Getenv("XDG_CONFIG_HOME")
 	fakeHome := c.MkDir()
 	restore = func() {
 		os.Setenv("HOME", realHome)
+		os.Setenv("XDG_CONFIG_HOME", realConfig)
 	}
 	os.Setenv("HOME", fakeHome)
+	os.Unsetenv("XDG_CONFIG_HOME")
 	return fakeHome, restore
 }
 
@@ -261,7 +264,7 @@ func (s *OAuthS) TestStoredOAuthLoginWithStored(c *C) {
 	file.Close()
 
 	oauth := lpad.StoredOAuth{}
-	oauth.Login("baseURL")
+	oauth.Login(string(lpad.Production))
 
 	c.Assert(oauth.Token, Equals, "mytoken")
 	c.Assert(oauth.TokenSecret, Equals, "mysecret")
@@ -289,14 +292,84 @@ func (s *OAuthS) TestStoredOAuthLogin(c *C) {
 	c.Assert(oauth.TokenSecret, Equals, "mysecret2")
 	c.Assert(oauth.AuthURL, Equals, "")
 
-	data, err := ioutil.ReadFile(filepath.Join(home, ".lpad_oauth"))
+	path := filepath.Join(home, ".config", "lpad", "credentials")
+	info, err := os.Stat(path)
 	c.Assert(err, IsNil)
+	c.Assert(info.Mode().Perm(), Equals, os.FileMode(0600))
 
-	result := lpad.OAuth{}
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, IsNil)
+
+	result := map[string]lpad.OAuth{}
 	err = json.Unmarshal(data, &result)
 	c.Assert(err, IsNil)
-	c.Assert(result.Token, Equals, "mytoken2")
-	c.Assert(result.TokenSecret, Equals, "mysecret2")
+	profile := "https://launchpad.net/lpad@" + testServer.URL
+	c.Assert(result[profile].Token, Equals, "mytoken2")
+	c.Assert(result[profile].TokenSecret, Equals, "mysecret2")
+
+	_, err = os.Stat(filepath.Join(home, ".lpad_oauth"))
+	c.Assert(os.IsNotExist(err), Equals, true)
+}
+
+func (s *OAuthS) TestStoredOAuthLoginProfiles(c *C) {
+	_, restore := fakeHome(c)
+	defer restore()
+
+	store := &lpad.FileStore{}
+	c.Assert(store.Save("lpad@"+testServer.URL, "mytoken", "mysecret"), IsNil)
+
+	oauth := lpad.StoredOAuth{Consumer: "lpad"}
+	err := oauth.Login(testServer.URL + "/")
+	c.Assert(err, IsNil)
+	c.Assert(oauth.Token, Equals, "mytoken")
+	c.Assert(oauth.TokenSecret, Equals, "mysecret")
+
+	// Another consumer has its own credentials.
+	testServer.PrepareResponse(200, nil, "oauth_token=mytoken1&oauth_token_secret=mysecret1")
+	testServer.PrepareResponse(200, nil, "oauth_token=mytoken2&oauth_token_secret=mysecret2")
+	other := lpad.StoredOAuth{Consumer: "other"}
+	err = other.Login(testServer.URL)
+	c.Assert(err, IsNil)
+	c.Assert(other.TokenSecret, Equals, "mysecret2")
+
+	token, secret, err := store.Load("lpad@" + testServer.URL)
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "mytoken")
+	c.Assert(secret, Equals, "mysecret")
+	token, secret, err = store.Load("other@" + testServer.URL)
+	c.Assert(err, IsNil)
+	c.Assert(token, Equals, "mytoken2")
+	c.Assert(secret, Equals, "mysecret2")
+}
+
+func (s *OAuthS) TestStoredOAuthLoginWithStore(c *C) {
+	os.Setenv("LPAD_OAUTH_TOKEN", "envtoken")
+	os.Setenv("LPAD_OAUTH_TOKEN_SECRET", "envsecret")
+	defer os.Unsetenv("LPAD_OAUTH_TOKEN")
+	defer os.Unsetenv("LPAD_OAUTH_TOKEN_SECRET")
+
+	oauth := lpad.ConsoleOAuth{Store: lpad.EnvStore{}}
+	err := oauth.Login(testServer.URL)
+	c.Assert(err, IsNil)
+	c.Assert(oauth.Token, Equals, "envtoken")
+	c.Assert(oauth.TokenSecret, Equals, "envsecret")
+
+	os.Unsetenv("LPAD_OAUTH_TOKEN_SECRET")
+	oauth = lpad.ConsoleOAuth{Store: lpad.EnvStore{}}
+	err = oauth.Login(testServer.URL)
+	c.Assert(err, ErrorMatches, "LPAD_OAUTH_TOKEN and LPAD_OAUTH_TOKEN_SECRET must be set")
+}
+
+func (s *OAuthS) TestStoredOAuthAnonymous(c *C) {
+	home, restore := fakeHome(c)
+	defer restore()
+
+	oauth := lpad.StoredOAuth{Anonymous: true}
+	err := oauth.Login(testServer.URL)
+	c.Assert(err, IsNil)
+
+	_, err = os.Stat(filepath.Join(home, ".config"))
+	c.Assert(os.IsNotExist(err), Equals, true)
 }
 
 func (s *OAuthS) TestStoredOAuthSignForwards(c *C) {
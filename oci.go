@@ -0,0 +1,186 @@
+package lpad
+
+import "net/url"
+
+func ociProjectPath(pillar, ociProject string) string {
+	return "/" + url.QueryEscape(pillar) + "/+oci/" + url.QueryEscape(ociProject)
+}
+
+// OCIRecipe returns the OCI recipe with the given name owned by owner
+// for the OCI project ociProject. The pillar is the name of the
+// project or distribution the OCI project belongs to.
+func (root *Root) OCIRecipe(owner Member, pillar, ociProject, name string) (*OCIRecipe, error) {
+	path := "/~" + url.QueryEscape(owner.Name()) + ociProjectPath(pillar, ociProject) + "/+recipe/" + url.QueryEscape(name)
+	v, err := root.Location(path).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OCIRecipe{v}, nil
+}
+
+// The OCIRecipeStub type must be used for creating new OCI recipes
+// via Root.CreateOCIRecipe.
+type OCIRecipeStub struct {
+	Owner       Member  // Required
+	Pillar      string  // Required
+	OCIProject  string  // Required
+	Name        string  // Required
+	GitRef      *GitRef // Required
+	BuildFile   string  // Required, such as "Dockerfile"
+	BuildPath   string
+	Description string
+	BuildDaily  bool
+}
+
+// CreateOCIRecipe registers a new OCI recipe built from a Git reference.
+func (root *Root) CreateOCIRecipe(stub *OCIRecipeStub) (*OCIRecipe, error) {
+	params := Params{
+		"ws.op":       "newRecipe",
+		"owner":       stub.Owner.AbsLoc(),
+		"name":        stub.Name,
+		"git_ref":     stub.GitRef.AbsLoc(),
+		"build_file":  stub.BuildFile,
+		"build_daily": "false",
+	}
+	if stub.BuildPath != "" {
+		params["build_path"] = stub.BuildPath
+	}
+	if stub.Description != "" {
+		params["description"] = stub.Description
+	}
+	if stub.BuildDaily {
+		params["build_daily"] = "true"
+	}
+	v, err := root.Location(ociProjectPath(stub.Pillar, stub.OCIProject)).Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &OCIRecipe{v}, nil
+}
+
+// The OCIRecipe type represents a recipe for building OCI images
+// out of a Git repository.
+type OCIRecipe struct {
+	*Value
+}
+
+// Name returns the recipe name.
+func (r *OCIRecipe) Name() string {
+	return r.StringField("name")
+}
+
+// Description returns the recipe description.
+func (r *OCIRecipe) Description() string {
+	return r.StringField("description")
+}
+
+// WebPage returns the URL for accessing this recipe in a browser.
+func (r *OCIRecipe) WebPage() string {
+	return r.StringField("web_link")
+}
+
+// BuildFile returns the location of the build file in the repository.
+func (r *OCIRecipe) BuildFile() string {
+	return r.StringField("build_file")
+}
+
+// BuildPath returns the directory in the repository used as the
+// context for the build.
+func (r *OCIRecipe) BuildPath() string {
+	return r.StringField("build_path")
+}
+
+// BuildDaily returns true if the recipe is built automatically every
+// day when its source changes.
+func (r *OCIRecipe) BuildDaily() bool {
+	return r.BoolField("build_daily")
+}
+
+// Owner returns the Person or Team that owns the recipe.
+func (r *OCIRecipe) Owner() (Member, error) {
+	v, err := r.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// GitRef returns the Git reference the image is built from.
+func (r *OCIRecipe) GitRef() (*GitRef, error) {
+	v, err := r.Link("git_ref_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRef{v}, nil
+}
+
+// RequestBuilds requests builds of the recipe for all its architectures.
+// Builds are created asynchronously. See the OCIRecipeBuildRequest type.
+func (r *OCIRecipe) RequestBuilds() (*OCIRecipeBuildRequest, error) {
+	v, err := r.Post(Params{"ws.op": "requestBuilds"})
+	if err != nil {
+		return nil, err
+	}
+	return &OCIRecipeBuildRequest{BuildRequest{v}}, nil
+}
+
+// Builds returns the list of all builds of the recipe.
+func (r *OCIRecipe) Builds() (*OCIRecipeBuildList, error) {
+	v, err := r.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OCIRecipeBuildList{v}, nil
+}
+
+// PendingBuilds returns the list of builds of the recipe that
+// haven't finished yet.
+func (r *OCIRecipe) PendingBuilds() (*OCIRecipeBuildList, error) {
+	v, err := r.Link("pending_builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OCIRecipeBuildList{v}, nil
+}
+
+// The OCIRecipeBuildRequest type represents a request for building
+// an OCI recipe for all its architectures. See the BuildRequest type
+// for details.
+type OCIRecipeBuildRequest struct {
+	BuildRequest
+}
+
+// Builds returns the list of builds created by the request.
+func (req *OCIRecipeBuildRequest) Builds() (*OCIRecipeBuildList, error) {
+	v, err := req.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &OCIRecipeBuildList{v}, nil
+}
+
+// The OCIRecipeBuild type describes a build of an OCI image for a
+// single architecture.
+type OCIRecipeBuild struct {
+	BuildFarmJob
+}
+
+// RegistryUploadStatus returns the status of the upload of the image
+// to the registries configured for the recipe.
+func (build *OCIRecipeBuild) RegistryUploadStatus() string {
+	return build.StringField("registry_upload_status")
+}
+
+// The OCIRecipeBuildList type represents a list of OCIRecipeBuild objects.
+type OCIRecipeBuildList struct {
+	*Value
+}
+
+// For iterates over the list of OCI builds and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *OCIRecipeBuildList) For(f func(b *OCIRecipeBuild) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&OCIRecipeBuild{BuildFarmJob{v}})
+	})
+}
@@ -0,0 +1,144 @@
+package lpad_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestRootOCIRecipe(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "myimage"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	owner := &lpad.Person{lpad.NewValue(nil, "", "", M{"name": "joe"})}
+	recipe, err := root.OCIRecipe(owner, "ubuntu", "postgres", "myimage")
+	c.Assert(err, IsNil)
+	c.Assert(recipe.Name(), Equals, "myimage")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe/ubuntu/+oci/postgres/+recipe/myimage")
+}
+
+func (s *ModelS) TestRootCreateOCIRecipe(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "myimage"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	stub := &lpad.OCIRecipeStub{
+		Owner:      &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)},
+		Pillar:     "ubuntu",
+		OCIProject: "postgres",
+		Name:       "myimage",
+		GitRef:     &lpad.GitRef{lpad.NewValue(nil, "", "http://ref", nil)},
+		BuildFile:  "Dockerfile",
+		BuildDaily: true,
+	}
+	recipe, err := root.CreateOCIRecipe(stub)
+	c.Assert(err, IsNil)
+	c.Assert(recipe.Name(), Equals, "myimage")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/ubuntu/+oci/postgres")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"newRecipe"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"myimage"})
+	c.Assert(req.Form["git_ref"], DeepEquals, []string{"http://ref"})
+	c.Assert(req.Form["build_file"], DeepEquals, []string{"Dockerfile"})
+	c.Assert(req.Form["build_daily"], DeepEquals, []string{"true"})
+
+	_, ok := req.Form["build_path"]
+	c.Assert(ok, Equals, false)
+}
+
+func (s *ModelS) TestOCIRecipe(c *C) {
+	m := M{
+		"name":         "myimage",
+		"description":  "Description",
+		"web_link":     "http://page",
+		"build_file":   "Dockerfile",
+		"build_path":   "docker",
+		"build_daily":  true,
+		"owner_link":   testServer.URL + "/owner_link",
+		"git_ref_link": testServer.URL + "/ref_link",
+	}
+	recipe := &lpad.OCIRecipe{lpad.NewValue(nil, "", "", m)}
+	c.Assert(recipe.Name(), Equals, "myimage")
+	c.Assert(recipe.Description(), Equals, "Description")
+	c.Assert(recipe.WebPage(), Equals, "http://page")
+	c.Assert(recipe.BuildFile(), Equals, "Dockerfile")
+	c.Assert(recipe.BuildPath(), Equals, "docker")
+	c.Assert(recipe.BuildDaily(), Equals, true)
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	testServer.PrepareResponse(200, jsonType, `{"path": "refs/heads/main"}`)
+	owner, err := recipe.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "joe")
+	ref, err := recipe.GitRef()
+	c.Assert(err, IsNil)
+	c.Assert(ref.Path(), Equals, "refs/heads/main")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ref_link")
+}
+
+func (s *ModelS) TestOCIRecipeRequestBuilds(c *C) {
+	recipe := &lpad.OCIRecipe{lpad.NewValue(nil, testServer.URL, testServer.URL+"/recipe", nil)}
+
+	testServer.PrepareResponse(200, jsonType, `{"status": "Pending"}`)
+	breq, err := recipe.RequestBuilds()
+	c.Assert(err, IsNil)
+	c.Assert(breq.Done(), Equals, false)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/recipe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"requestBuilds"})
+
+	m := M{"status": "Pending"}
+	breq = &lpad.OCIRecipeBuildRequest{lpad.BuildRequest{lpad.NewValue(nil, testServer.URL, testServer.URL+"/breq", m)}}
+	testServer.PrepareResponse(200, jsonType, `{"status": "Completed", "builds_collection_link": "`+testServer.URL+`/builds"}`)
+	err = breq.Wait(time.Millisecond, nil)
+	c.Assert(err, IsNil)
+	c.Assert(breq.Status(), Equals, lpad.BuildRequestCompleted)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/breq")
+
+	data := `{
+		"total_size": 1,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"title": "Build0",
+			"arch_tag": "amd64",
+			"buildstate": "Successfully built",
+			"build_log_url": "http://log0",
+			"registry_upload_status": "Uploaded",
+			"datecreated": "2021-10-10T00:00:00",
+			"datebuilt": "2021-10-10T00:00:10",
+			"web_link": "http://page0"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := breq.Builds()
+	c.Assert(err, IsNil)
+	var builds []*lpad.OCIRecipeBuild
+	list.For(func(b *lpad.OCIRecipeBuild) error {
+		builds = append(builds, b)
+		return nil
+	})
+	c.Assert(builds, HasLen, 1)
+	c.Assert(builds[0].Title(), Equals, "Build0")
+	c.Assert(builds[0].Arch(), Equals, "amd64")
+	c.Assert(builds[0].State(), Equals, lpad.BSSuccessfullyBuilt)
+	c.Assert(builds[0].BuildLogURL(), Equals, "http://log0")
+	c.Assert(builds[0].RegistryUploadStatus(), Equals, "Uploaded")
+	c.Assert(builds[0].Created(), Equals, "2021-10-10T00:00:00")
+	c.Assert(builds[0].Finished(), Equals, "2021-10-10T00:00:10")
+	c.Assert(builds[0].WebPage(), Equals, "http://page0")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/builds")
+}
@@ -1,7 +1,11 @@
 package lpad
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
 	"net/url"
+	"strings"
 )
 
 // The Root type provides the entrance for the Launchpad API.
@@ -24,10 +28,7 @@ func (root *Root) Member(name string) (Member, error) {
 	if err != nil {
 		return nil, err
 	}
-	if v.BoolField("is_team") {
-		return &Team{v}, nil
-	}
-	return &Person{v}, nil
+	return personOrTeam(v), nil
 }
 
 // FindPeople returns a PersonList containing all Person accounts whose
@@ -71,10 +72,7 @@ type MemberList struct {
 // returned as the result of For.
 func (list *MemberList) For(f func(v Member) error) error {
 	return list.Value.For(func(v *Value) error {
-		if v.BoolField("is_team") {
-			return f(&Team{v})
-		}
-		return f(&Person{v})
+		return f(personOrTeam(v))
 	})
 }
 
@@ -123,6 +121,15 @@ type Member interface {
 	Member()
 }
 
+// personOrTeam returns v as a Team or as a Person, depending on
+// whether it represents a team.
+func personOrTeam(v *Value) Member {
+	if v.BoolField("is_team") {
+		return &Team{v}
+	}
+	return &Person{v}
+}
+
 // The Person type represents a person in Launchpad.
 type Person struct {
 	*Value
@@ -190,10 +197,108 @@ func (person *Person) IRCNicks() (nicks []*IRCNick, err error) {
 	return
 }
 
+// AddIRCNick registers a new IRC nick for the person on the given network.
+func (person *Person) AddIRCNick(nick, network string) (*IRCNick, error) {
+	params := Params{
+		"ws.op":    "createIRCNick",
+		"nickname": nick,
+		"network":  network,
+	}
+	v, err := person.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &IRCNick{v}, nil
+}
+
+// TimeZone returns the name of the time zone the person is in
+// (e.g. "America/Sao_Paulo").
+func (person *Person) TimeZone() string {
+	return person.StringField("time_zone")
+}
+
+// Latitude returns the latitude of the person's location, if known.
+func (person *Person) Latitude() float64 {
+	return person.FloatField("latitude")
+}
+
+// Longitude returns the longitude of the person's location, if known.
+func (person *Person) Longitude() float64 {
+	return person.FloatField("longitude")
+}
+
+// Karma returns the karma points the person has accumulated
+// through activity in Launchpad.
+func (person *Person) Karma() int {
+	return person.IntField("karma")
+}
+
+// DateCreated returns the timestamp when the person's account was created.
+func (person *Person) DateCreated() string {
+	return person.StringField("date_created")
+}
+
+// ValidAccount returns true if the person has an active account
+// with a preferred email address.
+func (person *Person) ValidAccount() bool {
+	return person.BoolField("is_valid")
+}
+
+// Probationary returns true if the person has no karma and thus is
+// subject to restrictions as a measure against spam.
+func (person *Person) Probationary() bool {
+	return person.BoolField("is_probationary")
+}
+
+// SSHKeys returns the list of SSH public keys registered by the person.
+func (person *Person) SSHKeys() (keys []*SSHKey, err error) {
+	list, err := person.Link("sshkeys_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	err = list.For(func(v *Value) error {
+		keys = append(keys, &SSHKey{v})
+		return nil
+	})
+	return keys, err
+}
+
+// GPGKeys returns the list of OpenPGP keys registered by the person.
+func (person *Person) GPGKeys() (keys []*GPGKey, err error) {
+	list, err := person.Link("gpg_keys_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	err = list.For(func(v *Value) error {
+		keys = append(keys, &GPGKey{v})
+		return nil
+	})
+	return keys, err
+}
+
+// JabberIDs returns the list of Jabber IDs registered by the person.
+func (person *Person) JabberIDs() (ids []*JabberID, err error) {
+	list, err := person.Link("jabber_ids_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	err = list.For(func(v *Value) error {
+		ids = append(ids, &JabberID{v})
+		return nil
+	})
+	return ids, err
+}
+
 type IRCNick struct {
 	*Value
 }
 
+// Remove removes the IRC nick from the person's profile.
+func (nick *IRCNick) Remove() error {
+	_, err := nick.Post(Params{"ws.op": "destroySelf"})
+	return err
+}
+
 // Nick returns the person's nick on an IRC network.
 func (nick *IRCNick) Nick() string {
 	return nick.StringField("nickname")
@@ -216,6 +321,111 @@ func (nick *IRCNick) SetNetwork(n string) {
 	nick.SetField("network", n)
 }
 
+// The SSHKey type represents an SSH public key registered by a person.
+type SSHKey struct {
+	*Value
+}
+
+// Type returns the key type (e.g. "RSA", "DSA", "ECDSA" or "ED25519").
+func (key *SSHKey) Type() string {
+	return key.StringField("keytype")
+}
+
+// Text returns the base64 encoded public key.
+func (key *SSHKey) Text() string {
+	return key.StringField("keytext")
+}
+
+// Comment returns the comment associated with the key, which is
+// usually in the form user@host.
+func (key *SSHKey) Comment() string {
+	return key.StringField("comment")
+}
+
+// AuthorizedKey returns the key in the format used in the
+// ~/.ssh/authorized_keys file.
+func (key *SSHKey) AuthorizedKey() string {
+	var algo string
+	switch key.Type() {
+	case "RSA":
+		algo = "ssh-rsa"
+	case "DSA":
+		algo = "ssh-dss"
+	case "ED25519":
+		algo = "ssh-ed25519"
+	default:
+		// The algorithm name for ECDSA depends on the curve,
+		// and is encoded at the start of the key data.
+		algo = sshKeyAlgorithm(key.Text())
+	}
+	line := algo + " " + key.Text()
+	if comment := key.Comment(); comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// sshKeyAlgorithm returns the algorithm name found at the start of
+// the base64 encoded SSH public key, or the empty string if it can't
+// be decoded.
+func sshKeyAlgorithm(text string) string {
+	data, err := base64.StdEncoding.DecodeString(text)
+	if err != nil || len(data) < 4 {
+		return ""
+	}
+	n := int(data[0])<<24 | int(data[1])<<16 | int(data[2])<<8 | int(data[3])
+	if n > len(data)-4 {
+		return ""
+	}
+	return string(data[4 : 4+n])
+}
+
+// Fingerprint returns the MD5 fingerprint of the key in the colon
+// separated hexadecimal format displayed by Launchpad and by
+// ssh-keygen -l -E md5 (e.g. "c1:b1:30:29:...").
+func (key *SSHKey) Fingerprint() (string, error) {
+	data, err := base64.StdEncoding.DecodeString(key.Text())
+	if err != nil {
+		return "", fmt.Errorf("invalid SSH key text: %v", err)
+	}
+	sum := md5.Sum(data)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":"), nil
+}
+
+// The GPGKey type represents an OpenPGP key registered by a person.
+type GPGKey struct {
+	*Value
+}
+
+// Fingerprint returns the full fingerprint of the key.
+func (key *GPGKey) Fingerprint() string {
+	return key.StringField("fingerprint")
+}
+
+// KeyID returns the short identifier of the key.
+func (key *GPGKey) KeyID() string {
+	return key.StringField("keyid")
+}
+
+// CanEncrypt returns true if the key may be used for encryption.
+func (key *GPGKey) CanEncrypt() bool {
+	return key.BoolField("can_encrypt")
+}
+
+// The JabberID type represents a Jabber ID registered by a person.
+type JabberID struct {
+	*Value
+}
+
+// ID returns the Jabber ID (e.g. "joe@jabber.org").
+func (id *JabberID) ID() string {
+	return id.StringField("jabberid")
+}
+
 // The Team type encapsulates access to details about a team in Launchpad.
 type Team struct {
 	*Value
@@ -260,3 +260,136 @@ func (s *ModelS) TestPersonPreferredEmail(c *C) {
 	req := testServer.WaitRequest()
 	c.Assert(req.URL.Path, Equals, "/link")
 }
+
+func (s *ModelS) TestPersonProfile(c *C) {
+	m := M{
+		"time_zone":       "America/Sao_Paulo",
+		"latitude":        -23.5,
+		"longitude":       -46.6,
+		"karma":           1234.0,
+		"date_created":    "2005-06-06T08:59:51",
+		"is_valid":        true,
+		"is_probationary": false,
+	}
+	person := &lpad.Person{lpad.NewValue(nil, "", "", m)}
+	c.Assert(person.TimeZone(), Equals, "America/Sao_Paulo")
+	c.Assert(person.Latitude(), Equals, -23.5)
+	c.Assert(person.Longitude(), Equals, -46.6)
+	c.Assert(person.Karma(), Equals, 1234)
+	c.Assert(person.DateCreated(), Equals, "2005-06-06T08:59:51")
+	c.Assert(person.ValidAccount(), Equals, true)
+	c.Assert(person.Probationary(), Equals, false)
+}
+
+const ecdsaKeyText = "AAAAE2VjZHNhLXNoYTItbmlzdHAyNTYAAAAIbmlzdHAyNTYAAABBBAABAgMEBQYHCAkKCwwNDg8QERITFBUWFxgZGhscHR4fICEiIyQlJicoKSorLC0uLzAxMjM0NTY3ODk6Ozw9Pj8="
+
+func (s *ModelS) TestPersonSSHKeys(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"keytype": "ECDSA",
+			"keytext": "` + ecdsaKeyText + `",
+			"comment": "joe@laptop"
+		}, {
+			"keytype": "RSA",
+			"keytext": "AAAAB3NzaC1yc2E=",
+			"comment": ""
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"sshkeys_collection_link": testServer.URL + "/sshkeys_link"}
+	person := &lpad.Person{lpad.NewValue(nil, "", "", m)}
+	keys, err := person.SSHKeys()
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 2)
+
+	c.Assert(keys[0].Type(), Equals, "ECDSA")
+	c.Assert(keys[0].Text(), Equals, ecdsaKeyText)
+	c.Assert(keys[0].Comment(), Equals, "joe@laptop")
+	c.Assert(keys[0].AuthorizedKey(), Equals, "ecdsa-sha2-nistp256 "+ecdsaKeyText+" joe@laptop")
+	fingerprint, err := keys[0].Fingerprint()
+	c.Assert(err, IsNil)
+	c.Assert(fingerprint, Equals, "78:59:e9:5a:9c:eb:6b:1d:7c:53:90:3b:75:06:7f:01")
+
+	c.Assert(keys[1].AuthorizedKey(), Equals, "ssh-rsa AAAAB3NzaC1yc2E=")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/sshkeys_link")
+}
+
+func (s *ModelS) TestSSHKeyBadFingerprint(c *C) {
+	key := &lpad.SSHKey{lpad.NewValue(nil, "", "", M{"keytext": "!!!"})}
+	_, err := key.Fingerprint()
+	c.Assert(err, ErrorMatches, "invalid SSH key text: .*")
+}
+
+func (s *ModelS) TestPersonGPGKeys(c *C) {
+	data := `{
+		"total_size": 1,
+		"start": 0,
+		"entries": [{
+			"fingerprint": "0123456789ABCDEF0123456789ABCDEF01234567",
+			"keyid": "01234567",
+			"can_encrypt": true
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"gpg_keys_collection_link": testServer.URL + "/gpg_link"}
+	person := &lpad.Person{lpad.NewValue(nil, "", "", m)}
+	keys, err := person.GPGKeys()
+	c.Assert(err, IsNil)
+	c.Assert(keys, HasLen, 1)
+	c.Assert(keys[0].Fingerprint(), Equals, "0123456789ABCDEF0123456789ABCDEF01234567")
+	c.Assert(keys[0].KeyID(), Equals, "01234567")
+	c.Assert(keys[0].CanEncrypt(), Equals, true)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/gpg_link")
+}
+
+func (s *ModelS) TestPersonJabberIDs(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"jabberid": "joe@jabber.org"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"jabber_ids_collection_link": testServer.URL + "/jabber_link"}
+	person := &lpad.Person{lpad.NewValue(nil, "", "", m)}
+	ids, err := person.JabberIDs()
+	c.Assert(err, IsNil)
+	c.Assert(ids, HasLen, 1)
+	c.Assert(ids[0].ID(), Equals, "joe@jabber.org")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/jabber_link")
+}
+
+func (s *ModelS) TestPersonAddIRCNick(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"nickname": "joe", "network": "irc.libera.chat"}`)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	nick, err := person.AddIRCNick("joe", "irc.libera.chat")
+	c.Assert(err, IsNil)
+	c.Assert(nick.Nick(), Equals, "joe")
+	c.Assert(nick.Network(), Equals, "irc.libera.chat")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"createIRCNick"})
+	c.Assert(req.Form["nickname"], DeepEquals, []string{"joe"})
+	c.Assert(req.Form["network"], DeepEquals, []string{"irc.libera.chat"})
+}
+
+func (s *ModelS) TestIRCNickRemove(c *C) {
+	testServer.PrepareResponse(200, jsonType, "null")
+	m := M{"self_link": testServer.URL + "/~joe/+ircnick/1"}
+	nick := &lpad.IRCNick{lpad.NewValue(nil, testServer.URL, "", m)}
+	err := nick.Remove()
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~joe/+ircnick/1")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"destroySelf"})
+}
@@ -1,6 +1,80 @@
 package lpad
 
-import "net/url"
+import (
+	"net/url"
+	"strings"
+)
+
+// A License holds the name of a license as known by Launchpad.
+type License string
+
+const (
+	LicenseAcademicFree     License = "Academic Free License"
+	LicenseAFFERO           License = "GNU Affero GPL v3"
+	LicenseApache           License = "Apache Licence"
+	LicenseArtistic         License = "Artistic License 1.0"
+	LicenseArtistic2        License = "Artistic License 2.0"
+	LicenseBSD              License = "Simplified BSD Licence"
+	LicenseBSDModified      License = "Modified BSD Licence"
+	LicenseCC0              License = "Creative Commons - No Rights Reserved"
+	LicenseEclipse          License = "Eclipse Public Licence"
+	LicenseGPL2             License = "GNU GPL v2"
+	LicenseGPL3             License = "GNU GPL v3"
+	LicenseLGPL21           License = "GNU LGPL v2.1"
+	LicenseLGPL3            License = "GNU LGPL v3"
+	LicenseMIT              License = "MIT / X / Expat Licence"
+	LicenseMPL              License = "Mozilla Public Licence"
+	LicensePublicDomain     License = "Public Domain"
+	LicensePython           License = "Python Licence"
+	LicenseZPL              License = "Zope Public Licence"
+	LicenseOtherOpen        License = "Other/Open Source"
+	LicenseOtherProprietary License = "Other/Proprietary"
+	LicenseDontKnow         License = "I don't know yet"
+)
+
+// A ProjectStub holds details necessary for registering a new
+// project in Launchpad.
+type ProjectStub struct {
+	Name        string // Required
+	DisplayName string // Required
+	Title       string // Required
+	Summary     string // Required
+	Licenses    []License
+	Description string
+	HomepageURL string
+}
+
+// CreateProject registers a new project in Launchpad and returns it.
+// The authenticated user becomes the owner of the project.
+func (root *Root) CreateProject(stub *ProjectStub) (*Project, error) {
+	params := Params{
+		"ws.op":        "new_project",
+		"name":         stub.Name,
+		"display_name": stub.DisplayName,
+		"title":        stub.Title,
+		"summary":      stub.Summary,
+		"licenses":     jsonList(licenseStrings(stub.Licenses)),
+	}
+	if stub.Description != "" {
+		params["description"] = stub.Description
+	}
+	if stub.HomepageURL != "" {
+		params["home_page_url"] = stub.HomepageURL
+	}
+	v, err := root.Location("/projects").Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{v}, nil
+}
+
+func licenseStrings(licenses []License) []string {
+	var l []string
+	for _, license := range licenses {
+		l = append(l, string(license))
+	}
+	return l
+}
 
 // Project returns a project with the given name.
 func (root *Root) Project(name string) (*Project, error) {
@@ -90,6 +164,170 @@ func (p *Project) SetDescription(description string) {
 	p.SetField("description", description)
 }
 
+// Owner returns the Person or Team that owns the project.
+func (p *Project) Owner() (Member, error) {
+	v, err := p.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// SetOwner changes the Person or Team that owns the project.
+// Patch must be called to commit all changes.
+func (p *Project) SetOwner(owner Member) {
+	p.SetField("owner_link", owner.AbsLoc())
+}
+
+// Driver returns the Person or Team responsible for the project
+// series and for targeting bugs and blueprints to them.
+func (p *Project) Driver() (Member, error) {
+	v, err := p.Link("driver_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// SetDriver changes the Person or Team responsible for the project
+// series and for targeting bugs and blueprints to them.
+// Patch must be called to commit all changes.
+func (p *Project) SetDriver(driver Member) {
+	p.SetField("driver_link", driver.AbsLoc())
+}
+
+// BugSupervisor returns the Person or Team responsible for
+// handling bugs in the project.
+func (p *Project) BugSupervisor() (Member, error) {
+	v, err := p.Link("bug_supervisor_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// SetBugSupervisor changes the Person or Team responsible for
+// handling bugs in the project.
+// Patch must be called to commit all changes.
+func (p *Project) SetBugSupervisor(supervisor Member) {
+	p.SetField("bug_supervisor_link", supervisor.AbsLoc())
+}
+
+// Licenses returns the licenses the project is distributed under.
+func (p *Project) Licenses() []License {
+	var licenses []License
+	for _, s := range p.StringListField("licenses") {
+		licenses = append(licenses, License(s))
+	}
+	return licenses
+}
+
+// SetLicenses changes the licenses the project is distributed under.
+// Patch must be called to commit all changes.
+func (p *Project) SetLicenses(licenses []License) {
+	p.SetField("licenses", licenseStrings(licenses))
+}
+
+// HomepageURL returns the URL of the project's home page.
+func (p *Project) HomepageURL() string {
+	return p.StringField("homepage_url")
+}
+
+// SetHomepageURL changes the URL of the project's home page.
+// Patch must be called to commit all changes.
+func (p *Project) SetHomepageURL(url string) {
+	p.SetField("homepage_url", url)
+}
+
+// ProgrammingLanguages returns the programming languages
+// the project is written in.
+func (p *Project) ProgrammingLanguages() []string {
+	var langs []string
+	for _, lang := range strings.Split(p.StringField("programming_language"), ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			langs = append(langs, lang)
+		}
+	}
+	return langs
+}
+
+// SetProgrammingLanguages changes the programming languages
+// the project is written in.
+// Patch must be called to commit all changes.
+func (p *Project) SetProgrammingLanguages(langs []string) {
+	p.SetField("programming_language", strings.Join(langs, ", "))
+}
+
+// OfficialBugTags returns the tags suggested for use when
+// filing bugs against the project.
+func (p *Project) OfficialBugTags() []string {
+	return p.StringListField("official_bug_tags")
+}
+
+// SetOfficialBugTags changes the tags suggested for use when
+// filing bugs against the project.
+// Patch must be called to commit all changes.
+func (p *Project) SetOfficialBugTags(tags []string) {
+	p.SetField("official_bug_tags", tags)
+}
+
+// UsesLaunchpadBugs returns true if the project tracks bugs in Launchpad.
+func (p *Project) UsesLaunchpadBugs() bool {
+	return p.BoolField("official_bugs")
+}
+
+// SetUsesLaunchpadBugs changes whether the project tracks bugs in Launchpad.
+// Patch must be called to commit all changes.
+func (p *Project) SetUsesLaunchpadBugs(uses bool) {
+	p.SetField("official_bugs", uses)
+}
+
+// UsesLaunchpadCode returns true if the project hosts code in Launchpad.
+func (p *Project) UsesLaunchpadCode() bool {
+	return p.BoolField("official_codehosting")
+}
+
+// SetUsesLaunchpadCode changes whether the project hosts code in Launchpad.
+// Patch must be called to commit all changes.
+func (p *Project) SetUsesLaunchpadCode(uses bool) {
+	p.SetField("official_codehosting", uses)
+}
+
+// UsesLaunchpadAnswers returns true if the project answers
+// questions in Launchpad.
+func (p *Project) UsesLaunchpadAnswers() bool {
+	return p.BoolField("official_answers")
+}
+
+// SetUsesLaunchpadAnswers changes whether the project answers
+// questions in Launchpad.
+// Patch must be called to commit all changes.
+func (p *Project) SetUsesLaunchpadAnswers(uses bool) {
+	p.SetField("official_answers", uses)
+}
+
+// A ServiceUsage holds how a project uses one of the Launchpad
+// applications, such as translations.
+type ServiceUsage string
+
+const (
+	UsageUnknown       ServiceUsage = "Unknown"
+	UsageLaunchpad     ServiceUsage = "Launchpad"
+	UsageExternal      ServiceUsage = "External"
+	UsageNotApplicable ServiceUsage = "Not Applicable"
+)
+
+// TranslationsUsage returns whether and where the project is translated.
+func (p *Project) TranslationsUsage() ServiceUsage {
+	return ServiceUsage(p.StringField("translations_usage"))
+}
+
+// SetTranslationsUsage changes whether and where the project is translated.
+// Patch must be called to commit all changes.
+func (p *Project) SetTranslationsUsage(usage ServiceUsage) {
+	p.SetField("translations_usage", string(usage))
+}
+
 // ActiveMilestones returns the list of active milestones associated with
 // the project, ordered by the target date.
 func (p *Project) ActiveMilestones() (*MilestoneList, error) {
@@ -100,6 +338,16 @@ func (p *Project) ActiveMilestones() (*MilestoneList, error) {
 	return &MilestoneList{r}, nil
 }
 
+// AllMilestones returns the list of all milestones associated with
+// the project, including inactive ones.
+func (p *Project) AllMilestones() (*MilestoneList, error) {
+	r, err := p.Link("all_milestones_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MilestoneList{r}, nil
+}
+
 // AllSeries returns the list of series associated with the project.
 func (p *Project) AllSeries() (*ProjectSeriesList, error) {
 	r, err := p.Link("series_collection_link").Get(nil)
@@ -119,6 +367,31 @@ func (p *Project) FocusSeries() (*ProjectSeries, error) {
 	return &ProjectSeries{r}, nil
 }
 
+// SetFocusSeries changes the development series set as the current
+// development focus.
+// Patch must be called to commit all changes.
+func (p *Project) SetFocusSeries(series *ProjectSeries) {
+	p.SetField("development_focus_link", series.AbsLoc())
+}
+
+// NewSeries creates a new series in the project. The Bazaar branch
+// associated with the series may be nil.
+func (p *Project) NewSeries(name, summary string, branch *Branch) (*ProjectSeries, error) {
+	params := Params{
+		"ws.op":   "newSeries",
+		"name":    name,
+		"summary": summary,
+	}
+	if branch != nil {
+		params["branch"] = branch.AbsLoc()
+	}
+	r, err := p.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectSeries{r}, nil
+}
+
 // BlueprintTarget marks *Project as being a target for blueprints. 
 func (p *Project) BlueprintTarget() {}
 
@@ -196,6 +469,20 @@ func (ms *Milestone) SetDate(date string) {
 	ms.SetField("date_targeted", date)
 }
 
+// SearchTasks returns the list of bug tasks targeted at the milestone
+// with the given status, or with any status if status is empty.
+func (ms *Milestone) SearchTasks(status BugStatus) (*BugTaskList, error) {
+	params := Params{"ws.op": "searchTasks"}
+	if status != "" {
+		params["status"] = string(status)
+	}
+	v, err := ms.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &BugTaskList{v}, nil
+}
+
 // The MilestoneList type represents a list of milestones that
 // may be iterated over.
 type MilestoneList struct {
@@ -252,6 +539,23 @@ func (s *ProjectSeries) Branch() (*Branch, error) {
 	return &Branch{r}, nil
 }
 
+// ReleaseFinderURLPattern returns the URL pattern Launchpad uses to
+// find release tarballs for the series on other sites, such as
+// "http://example.com/downloads/foo-*.tar.gz".
+func (s *ProjectSeries) ReleaseFinderURLPattern() string {
+	return s.StringField("release_finder_url_pattern")
+}
+
+// GitRepository returns the Git repository associated with this
+// project series.
+func (s *ProjectSeries) GitRepository() (*GitRepository, error) {
+	r, err := s.Link("git_repository_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRepository{r}, nil
+}
+
 // SetName changes the series name, which must consists of only letters,
 // numbers, and simple punctuation. For example: "2.0" or "trunk".
 func (s *ProjectSeries) SetName(name string) {
@@ -278,6 +582,42 @@ func (s *ProjectSeries) SetBranch(branch *Branch) {
 	s.SetField("branch_link", branch.AbsLoc())
 }
 
+// SetReleaseFinderURLPattern changes the URL pattern Launchpad uses
+// to find release tarballs for the series on other sites.
+func (s *ProjectSeries) SetReleaseFinderURLPattern(pattern string) {
+	s.SetField("release_finder_url_pattern", pattern)
+}
+
+// SetGitRepository changes the Git repository associated with this
+// project series.
+func (s *ProjectSeries) SetGitRepository(repo *GitRepository) {
+	s.SetField("git_repository_link", repo.AbsLoc())
+}
+
+// NewMilestone creates a new milestone in the series. The date is
+// the target date for the milestone in the "2006-01-02" format, and
+// may be empty along with codeName and summary.
+func (s *ProjectSeries) NewMilestone(name, date, codeName, summary string) (*Milestone, error) {
+	params := Params{
+		"ws.op": "newMilestone",
+		"name":  name,
+	}
+	if date != "" {
+		params["date_targeted"] = date
+	}
+	if codeName != "" {
+		params["code_name"] = codeName
+	}
+	if summary != "" {
+		params["summary"] = summary
+	}
+	v, err := s.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Milestone{v}, nil
+}
+
 // The ProjectSeriesList represents a list of project series.
 type ProjectSeriesList struct {
 	*Value
@@ -195,3 +195,248 @@ func (s *ModelS) TestProjectAllSeries(c *C) {
 	c.Assert(req.Method, Equals, "GET")
 	c.Assert(req.URL.Path, Equals, "/col_link")
 }
+
+func (s *ModelS) TestProjectAdmin(c *C) {
+	m := M{
+		"licenses":             []interface{}{"GNU GPL v3", "MIT / X / Expat Licence"},
+		"homepage_url":         "http://home",
+		"programming_language": "Go, C",
+		"official_bug_tags":    []interface{}{"a", "b"},
+		"official_bugs":        true,
+		"official_codehosting": false,
+		"official_answers":     true,
+		"translations_usage":   "Launchpad",
+	}
+	project := &lpad.Project{lpad.NewValue(nil, "", "", m)}
+	c.Assert(project.Licenses(), DeepEquals, []lpad.License{lpad.LicenseGPL3, lpad.LicenseMIT})
+	c.Assert(project.HomepageURL(), Equals, "http://home")
+	c.Assert(project.ProgrammingLanguages(), DeepEquals, []string{"Go", "C"})
+	c.Assert(project.OfficialBugTags(), DeepEquals, []string{"a", "b"})
+	c.Assert(project.UsesLaunchpadBugs(), Equals, true)
+	c.Assert(project.UsesLaunchpadCode(), Equals, false)
+	c.Assert(project.UsesLaunchpadAnswers(), Equals, true)
+	c.Assert(project.TranslationsUsage(), Equals, lpad.UsageLaunchpad)
+
+	project.SetLicenses([]lpad.License{lpad.LicenseApache})
+	project.SetHomepageURL("http://newhome")
+	project.SetProgrammingLanguages([]string{"Python"})
+	project.SetOfficialBugTags([]string{"c"})
+	project.SetUsesLaunchpadBugs(false)
+	project.SetUsesLaunchpadCode(true)
+	project.SetUsesLaunchpadAnswers(false)
+	project.SetTranslationsUsage(lpad.UsageExternal)
+	c.Assert(project.Licenses(), DeepEquals, []lpad.License{lpad.LicenseApache})
+	c.Assert(project.HomepageURL(), Equals, "http://newhome")
+	c.Assert(project.ProgrammingLanguages(), DeepEquals, []string{"Python"})
+	c.Assert(project.OfficialBugTags(), DeepEquals, []string{"c"})
+	c.Assert(project.UsesLaunchpadBugs(), Equals, false)
+	c.Assert(project.UsesLaunchpadCode(), Equals, true)
+	c.Assert(project.UsesLaunchpadAnswers(), Equals, false)
+	c.Assert(project.TranslationsUsage(), Equals, lpad.UsageExternal)
+	c.Assert(m["translations_usage"], Equals, "External")
+
+	// Clearing lists sends an empty list rather than null.
+	project.SetLicenses(nil)
+	project.SetOfficialBugTags(nil)
+	c.Assert(m["licenses"], DeepEquals, []interface{}{})
+	c.Assert(m["official_bug_tags"], DeepEquals, []interface{}{})
+
+	person := &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)}
+	project.SetOwner(person)
+	project.SetDriver(person)
+	project.SetBugSupervisor(person)
+	c.Assert(m["owner_link"], Equals, "http://joe")
+	c.Assert(m["driver_link"], Equals, "http://joe")
+	c.Assert(m["bug_supervisor_link"], Equals, "http://joe")
+}
+
+func (s *ModelS) TestProjectOwner(c *C) {
+	m := M{
+		"owner_link":          testServer.URL + "/owner_link",
+		"driver_link":         testServer.URL + "/driver_link",
+		"bug_supervisor_link": testServer.URL + "/supervisor_link",
+	}
+	project := &lpad.Project{lpad.NewValue(nil, "", "", m)}
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "team", "is_team": true}`)
+	owner, err := project.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "team")
+	_, ok := owner.(*lpad.Team)
+	c.Assert(ok, Equals, true)
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	driver, err := project.Driver()
+	c.Assert(err, IsNil)
+	c.Assert(driver.Name(), Equals, "joe")
+	_, ok = driver.(*lpad.Person)
+	c.Assert(ok, Equals, true)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/driver_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "bob"}`)
+	supervisor, err := project.BugSupervisor()
+	c.Assert(err, IsNil)
+	c.Assert(supervisor.Name(), Equals, "bob")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/supervisor_link")
+}
+
+func (s *ModelS) TestRootCreateProject(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "thename"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	stub := &lpad.ProjectStub{
+		Name:        "thename",
+		DisplayName: "Display Name",
+		Title:       "Title",
+		Summary:     "Summary",
+		Licenses:    []lpad.License{lpad.LicenseGPL3, lpad.LicenseMIT},
+		HomepageURL: "http://home",
+	}
+	project, err := root.CreateProject(stub)
+	c.Assert(err, IsNil)
+	c.Assert(project.Name(), Equals, "thename")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/projects")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"new_project"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"thename"})
+	c.Assert(req.Form["display_name"], DeepEquals, []string{"Display Name"})
+	c.Assert(req.Form["title"], DeepEquals, []string{"Title"})
+	c.Assert(req.Form["summary"], DeepEquals, []string{"Summary"})
+	c.Assert(req.Form["licenses"], DeepEquals, []string{`["GNU GPL v3","MIT / X / Expat Licence"]`})
+	c.Assert(req.Form["home_page_url"], DeepEquals, []string{"http://home"})
+
+	_, ok := req.Form["description"]
+	c.Assert(ok, Equals, false)
+}
+
+func (s *ModelS) TestProjectAllMilestones(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"name": "Name0"
+		}, {
+			"self_link": "http://self1",
+			"name": "Name1"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"all_milestones_collection_link": testServer.URL + "/col_link"}
+	project := &lpad.Project{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := project.AllMilestones()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	names := []string{}
+	list.For(func(ms *lpad.Milestone) error {
+		names = append(names, ms.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"Name0", "Name1"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/col_link")
+}
+
+func (s *ModelS) TestProjectSeriesNewMilestone(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "1.0"}`)
+	series := &lpad.ProjectSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/series", nil)}
+	ms, err := series.NewMilestone("1.0", "2011-08-31", "", "Summary")
+	c.Assert(err, IsNil)
+	c.Assert(ms.Name(), Equals, "1.0")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/series")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"newMilestone"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"1.0"})
+	c.Assert(req.Form["date_targeted"], DeepEquals, []string{"2011-08-31"})
+	c.Assert(req.Form["summary"], DeepEquals, []string{"Summary"})
+
+	_, ok := req.Form["code_name"]
+	c.Assert(ok, Equals, false)
+}
+
+func (s *ModelS) TestMilestoneSearchTasks(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"status": "New"
+		}, {
+			"self_link": "http://self1",
+			"status": "New"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	ms := &lpad.Milestone{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ms", nil)}
+	list, err := ms.SearchTasks(lpad.StNew)
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/ms")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"searchTasks"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"New"})
+}
+
+func (s *ModelS) TestProjectNewSeries(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "2.0"}`)
+	project := &lpad.Project{lpad.NewValue(nil, testServer.URL, testServer.URL+"/proj", nil)}
+	b := &lpad.Branch{lpad.NewValue(nil, "", "http://branch", nil)}
+	series, err := project.NewSeries("2.0", "Summary", b)
+	c.Assert(err, IsNil)
+	c.Assert(series.Name(), Equals, "2.0")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/proj")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"newSeries"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"2.0"})
+	c.Assert(req.Form["summary"], DeepEquals, []string{"Summary"})
+	c.Assert(req.Form["branch"], DeepEquals, []string{"http://branch"})
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "3.0"}`)
+	_, err = project.NewSeries("3.0", "Summary", nil)
+	c.Assert(err, IsNil)
+
+	req = testServer.WaitRequest()
+	_, ok := req.Form["branch"]
+	c.Assert(ok, Equals, false)
+
+	project.SetFocusSeries(series)
+	c.Assert(project.StringField("development_focus_link"), Equals, series.AbsLoc())
+}
+
+func (s *ModelS) TestProjectSeriesReleaseFinderAndGit(c *C) {
+	m := M{
+		"release_finder_url_pattern": "http://example.com/foo-*.tar.gz",
+		"git_repository_link":        testServer.URL + "/git_link",
+	}
+	series := &lpad.ProjectSeries{lpad.NewValue(nil, "", "", m)}
+	c.Assert(series.ReleaseFinderURLPattern(), Equals, "http://example.com/foo-*.tar.gz")
+	series.SetReleaseFinderURLPattern("http://example.com/bar-*.tar.gz")
+	c.Assert(series.ReleaseFinderURLPattern(), Equals, "http://example.com/bar-*.tar.gz")
+
+	testServer.PrepareResponse(200, jsonType, `{"unique_name": "~joe/proj/+git/proj"}`)
+	repo, err := series.GitRepository()
+	c.Assert(err, IsNil)
+	c.Assert(repo.UniqueName(), Equals, "~joe/proj/+git/proj")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/git_link")
+
+	repo = &lpad.GitRepository{lpad.NewValue(nil, "", "http://new_git_link", nil)}
+	series.SetGitRepository(repo)
+	c.Assert(series.StringField("git_repository_link"), Equals, "http://new_git_link")
+}
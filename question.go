@@ -0,0 +1,304 @@
+package lpad
+
+import (
+	"strconv"
+)
+
+// Question returns the question with the given id.
+func (root *Root) Question(id int) (*Question, error) {
+	v, err := root.Location("/questions/" + strconv.Itoa(id)).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Question{v}, nil
+}
+
+// A QuestionStatus holds the state of a question in Launchpad Answers.
+type QuestionStatus string
+
+const (
+	QuestionAny       QuestionStatus = ""
+	QuestionOpen      QuestionStatus = "Open"
+	QuestionNeedsInfo QuestionStatus = "Needs information"
+	QuestionAnswered  QuestionStatus = "Answered"
+	QuestionSolved    QuestionStatus = "Solved"
+	QuestionExpired   QuestionStatus = "Expired"
+	QuestionInvalid   QuestionStatus = "Invalid"
+)
+
+func searchQuestions(v *Value, text string, status QuestionStatus) (*QuestionList, error) {
+	params := Params{"ws.op": "searchQuestions"}
+	if text != "" {
+		params["search_text"] = text
+	}
+	if status != QuestionAny {
+		params["status"] = string(status)
+	}
+	v, err := v.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &QuestionList{v}, nil
+}
+
+func newQuestion(v *Value, owner *Person, title, description string) (*Question, error) {
+	params := Params{
+		"ws.op":       "newQuestion",
+		"owner":       owner.AbsLoc(),
+		"title":       title,
+		"description": description,
+	}
+	v, err := v.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Question{v}, nil
+}
+
+// SearchQuestions returns the questions asked about the project that
+// match text and have the given status. Either filter may be empty.
+func (p *Project) SearchQuestions(text string, status QuestionStatus) (*QuestionList, error) {
+	return searchQuestions(p.Value, text, status)
+}
+
+// CreateQuestion asks a new question about the project on behalf of owner.
+func (p *Project) CreateQuestion(owner *Person, title, description string) (*Question, error) {
+	return newQuestion(p.Value, owner, title, description)
+}
+
+// SearchQuestions returns the questions asked about the distribution
+// that match text and have the given status. Either filter may be empty.
+func (d *Distro) SearchQuestions(text string, status QuestionStatus) (*QuestionList, error) {
+	return searchQuestions(d.Value, text, status)
+}
+
+// CreateQuestion asks a new question about the distribution on
+// behalf of owner.
+func (d *Distro) CreateQuestion(owner *Person, title, description string) (*Question, error) {
+	return newQuestion(d.Value, owner, title, description)
+}
+
+// SearchQuestions returns the questions asked about the source package
+// that match text and have the given status. Either filter may be empty.
+func (s *DistroSourcePackage) SearchQuestions(text string, status QuestionStatus) (*QuestionList, error) {
+	return searchQuestions(s.Value, text, status)
+}
+
+// CreateQuestion asks a new question about the source package on
+// behalf of owner.
+func (s *DistroSourcePackage) CreateQuestion(owner *Person, title, description string) (*Question, error) {
+	return newQuestion(s.Value, owner, title, description)
+}
+
+// The Question type represents a question asked in Launchpad Answers.
+type Question struct {
+	*Value
+}
+
+// Id returns the question number.
+func (q *Question) Id() int {
+	return q.IntField("id")
+}
+
+// Title returns the question title.
+func (q *Question) Title() string {
+	return q.StringField("title")
+}
+
+// Description returns the full description of the question.
+func (q *Question) Description() string {
+	return q.StringField("description")
+}
+
+// Status returns the current status of the question.
+func (q *Question) Status() QuestionStatus {
+	return QuestionStatus(q.StringField("status"))
+}
+
+// DateCreated returns the time the question was asked.
+func (q *Question) DateCreated() string {
+	return q.StringField("date_created")
+}
+
+// WebPage returns the URL for accessing this question in a browser.
+func (q *Question) WebPage() string {
+	return q.StringField("web_link")
+}
+
+// SetTitle changes the question title.
+// Patch must be called to commit all changes.
+func (q *Question) SetTitle(title string) {
+	q.SetField("title", title)
+}
+
+// SetDescription changes the full description of the question.
+// Patch must be called to commit all changes.
+func (q *Question) SetDescription(description string) {
+	q.SetField("description", description)
+}
+
+// Owner returns the person who asked the question.
+func (q *Question) Owner() (*Person, error) {
+	v, err := q.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// Assignee returns the person assigned to answer the question.
+func (q *Question) Assignee() (*Person, error) {
+	v, err := q.Link("assignee_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// SetAssignee changes the person assigned to answer the question.
+// Patch must be called to commit all changes.
+func (q *Question) SetAssignee(person *Person) {
+	q.SetField("assignee_link", person.AbsLoc())
+}
+
+// Answerer returns the person who provided the accepted answer.
+func (q *Question) Answerer() (*Person, error) {
+	v, err := q.Link("answerer_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// Messages returns the list of messages posted in the question.
+func (q *Question) Messages() (*QuestionMessageList, error) {
+	v, err := q.Link("messages_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &QuestionMessageList{v}, nil
+}
+
+// AddMessage posts a new comment in the question.
+func (q *Question) AddMessage(content string) error {
+	_, err := q.Post(Params{"ws.op": "addComment", "comment": content})
+	return err
+}
+
+// FAQ returns the FAQ linked to the question as its answer.
+func (q *Question) FAQ() (*FAQ, error) {
+	v, err := q.Link("faq_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &FAQ{v}, nil
+}
+
+// LinkFAQ links faq to the question as its answer, recording
+// comment in the question's messages.
+func (q *Question) LinkFAQ(faq *FAQ, comment string) error {
+	params := Params{
+		"ws.op":   "linkFAQ",
+		"faq":     faq.AbsLoc(),
+		"comment": comment,
+	}
+	_, err := q.Post(params)
+	return err
+}
+
+// LinkBug associates a bug with the question.
+func (q *Question) LinkBug(bug *Bug) error {
+	_, err := q.Post(Params{"ws.op": "linkBug", "bug": bug.AbsLoc()})
+	return err
+}
+
+// UnlinkBug removes the association between a bug and the question.
+func (q *Question) UnlinkBug(bug *Bug) error {
+	_, err := q.Post(Params{"ws.op": "unlinkBug", "bug": bug.AbsLoc()})
+	return err
+}
+
+// The QuestionList type represents a list of Question objects.
+type QuestionList struct {
+	*Value
+}
+
+// For iterates over the list of questions and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *QuestionList) For(f func(q *Question) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&Question{v})
+	})
+}
+
+// The QuestionMessage type represents a message posted in a question.
+type QuestionMessage struct {
+	*Value
+}
+
+// Content returns the text of the message.
+func (m *QuestionMessage) Content() string {
+	return m.StringField("content")
+}
+
+// Action returns the action performed by the message, such as
+// "Comment", "Request for more information" or "Answer".
+func (m *QuestionMessage) Action() string {
+	return m.StringField("action")
+}
+
+// NewStatus returns the status of the question after the message
+// was posted.
+func (m *QuestionMessage) NewStatus() QuestionStatus {
+	return QuestionStatus(m.StringField("new_status"))
+}
+
+// DateCreated returns the time the message was posted.
+func (m *QuestionMessage) DateCreated() string {
+	return m.StringField("date_created")
+}
+
+// Owner returns the person who posted the message.
+func (m *QuestionMessage) Owner() (*Person, error) {
+	v, err := m.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// The QuestionMessageList type represents a list of QuestionMessage objects.
+type QuestionMessageList struct {
+	*Value
+}
+
+// For iterates over the list of messages and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *QuestionMessageList) For(f func(m *QuestionMessage) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&QuestionMessage{v})
+	})
+}
+
+// The FAQ type represents a frequently asked question documented
+// in Launchpad Answers.
+type FAQ struct {
+	*Value
+}
+
+// Title returns the FAQ title.
+func (faq *FAQ) Title() string {
+	return faq.StringField("title")
+}
+
+// Content returns the answer documented in the FAQ.
+func (faq *FAQ) Content() string {
+	return faq.StringField("content")
+}
+
+// WebPage returns the URL for accessing this FAQ in a browser.
+func (faq *FAQ) WebPage() string {
+	return faq.StringField("web_link")
+}
@@ -0,0 +1,237 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestRootQuestion(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"id": 123, "title": "Title"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	q, err := root.Question(123)
+	c.Assert(err, IsNil)
+	c.Assert(q.Id(), Equals, 123)
+	c.Assert(q.Title(), Equals, "Title")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/questions/123")
+}
+
+func (s *ModelS) TestQuestion(c *C) {
+	m := M{
+		"id":            123.0,
+		"title":         "Title",
+		"description":   "Description",
+		"status":        "Open",
+		"date_created":  "2011-08-31",
+		"web_link":      "http://page",
+		"owner_link":    testServer.URL + "/owner_link",
+		"assignee_link": testServer.URL + "/assignee_link",
+		"answerer_link": testServer.URL + "/answerer_link",
+		"faq_link":      testServer.URL + "/faq_link",
+	}
+	q := &lpad.Question{lpad.NewValue(nil, "", "", m)}
+	c.Assert(q.Id(), Equals, 123)
+	c.Assert(q.Title(), Equals, "Title")
+	c.Assert(q.Description(), Equals, "Description")
+	c.Assert(q.Status(), Equals, lpad.QuestionOpen)
+	c.Assert(q.DateCreated(), Equals, "2011-08-31")
+	c.Assert(q.WebPage(), Equals, "http://page")
+	q.SetTitle("New title")
+	q.SetDescription("New description")
+	c.Assert(q.Title(), Equals, "New title")
+	c.Assert(q.Description(), Equals, "New description")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "bob"}`)
+	testServer.PrepareResponse(200, jsonType, `{"name": "ann"}`)
+	testServer.PrepareResponse(200, jsonType, `{"title": "FAQ", "content": "Content", "web_link": "http://faq"}`)
+
+	owner, err := q.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "joe")
+	assignee, err := q.Assignee()
+	c.Assert(err, IsNil)
+	c.Assert(assignee.Name(), Equals, "bob")
+	answerer, err := q.Answerer()
+	c.Assert(err, IsNil)
+	c.Assert(answerer.Name(), Equals, "ann")
+	faq, err := q.FAQ()
+	c.Assert(err, IsNil)
+	c.Assert(faq.Title(), Equals, "FAQ")
+	c.Assert(faq.Content(), Equals, "Content")
+	c.Assert(faq.WebPage(), Equals, "http://faq")
+
+	for _, path := range []string{"/owner_link", "/assignee_link", "/answerer_link", "/faq_link"} {
+		req := testServer.WaitRequest()
+		c.Assert(req.URL.Path, Equals, path)
+	}
+
+	person := &lpad.Person{lpad.NewValue(nil, "", "http://person", nil)}
+	q.SetAssignee(person)
+	c.Assert(q.StringField("assignee_link"), Equals, "http://person")
+}
+
+func (s *ModelS) TestQuestionMessages(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"content": "Content0",
+			"action": "Comment",
+			"new_status": "Open",
+			"date_created": "2011-08-31"
+		}, {
+			"self_link": "http://self1",
+			"content": "Content1",
+			"action": "Answer",
+			"new_status": "Answered"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"messages_collection_link": testServer.URL + "/col_link"}
+	q := &lpad.Question{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := q.Messages()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	var msgs []*lpad.QuestionMessage
+	list.For(func(m *lpad.QuestionMessage) error {
+		msgs = append(msgs, m)
+		return nil
+	})
+	c.Assert(msgs, HasLen, 2)
+	c.Assert(msgs[0].Content(), Equals, "Content0")
+	c.Assert(msgs[0].Action(), Equals, "Comment")
+	c.Assert(msgs[0].NewStatus(), Equals, lpad.QuestionOpen)
+	c.Assert(msgs[0].DateCreated(), Equals, "2011-08-31")
+	c.Assert(msgs[1].NewStatus(), Equals, lpad.QuestionAnswered)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/col_link")
+}
+
+func (s *ModelS) TestQuestionOperations(c *C) {
+	q := &lpad.Question{lpad.NewValue(nil, testServer.URL, testServer.URL+"/proj/+question/123", nil)}
+	faq := &lpad.FAQ{lpad.NewValue(nil, "", "http://faq", nil)}
+	bug := &lpad.Bug{lpad.NewValue(nil, "", "http://bug", nil)}
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err := q.AddMessage("Hello")
+	c.Assert(err, IsNil)
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/proj/+question/123")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"addComment"})
+	c.Assert(req.Form["comment"], DeepEquals, []string{"Hello"})
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = q.LinkFAQ(faq, "See this")
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"linkFAQ"})
+	c.Assert(req.Form["faq"], DeepEquals, []string{"http://faq"})
+	c.Assert(req.Form["comment"], DeepEquals, []string{"See this"})
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = q.LinkBug(bug)
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"linkBug"})
+	c.Assert(req.Form["bug"], DeepEquals, []string{"http://bug"})
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = q.UnlinkBug(bug)
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"unlinkBug"})
+	c.Assert(req.Form["bug"], DeepEquals, []string{"http://bug"})
+}
+
+func (s *ModelS) TestSearchQuestions(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"title": "Title0"
+		}, {
+			"self_link": "http://self1",
+			"title": "Title1"
+		}]
+	}`
+	project := &lpad.Project{lpad.NewValue(nil, testServer.URL, testServer.URL+"/proj", nil)}
+	distro := &lpad.Distro{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu", nil)}
+	dsp := &lpad.DistroSourcePackage{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu/+source/pkg", nil)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := project.SearchQuestions("crash", lpad.QuestionOpen)
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+	titles := []string{}
+	list.For(func(q *lpad.Question) error {
+		titles = append(titles, q.Title())
+		return nil
+	})
+	c.Assert(titles, DeepEquals, []string{"Title0", "Title1"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/proj")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"searchQuestions"})
+	c.Assert(req.Form["search_text"], DeepEquals, []string{"crash"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"Open"})
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = distro.SearchQuestions("", lpad.QuestionAny)
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ubuntu")
+	_, ok := req.Form["search_text"]
+	c.Assert(ok, Equals, false)
+	_, ok = req.Form["status"]
+	c.Assert(ok, Equals, false)
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = dsp.SearchQuestions("", lpad.QuestionSolved)
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ubuntu/+source/pkg")
+	c.Assert(req.Form["status"], DeepEquals, []string{"Solved"})
+}
+
+func (s *ModelS) TestCreateQuestion(c *C) {
+	owner := &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)}
+	project := &lpad.Project{lpad.NewValue(nil, testServer.URL, testServer.URL+"/proj", nil)}
+	distro := &lpad.Distro{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu", nil)}
+	dsp := &lpad.DistroSourcePackage{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu/+source/pkg", nil)}
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "Title"}`)
+	q, err := project.CreateQuestion(owner, "Title", "Description")
+	c.Assert(err, IsNil)
+	c.Assert(q.Title(), Equals, "Title")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/proj")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"newQuestion"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["title"], DeepEquals, []string{"Title"})
+	c.Assert(req.Form["description"], DeepEquals, []string{"Description"})
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "Title"}`)
+	_, err = distro.CreateQuestion(owner, "Title", "Description")
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ubuntu")
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "Title"}`)
+	_, err = dsp.CreateQuestion(owner, "Title", "Description")
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ubuntu/+source/pkg")
+}
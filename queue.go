@@ -0,0 +1,193 @@
+package lpad
+
+import (
+	"fmt"
+)
+
+// A PackageUploadStatus holds the state of an upload in a distribution
+// series queue.
+type PackageUploadStatus string
+
+const (
+	UploadAny        PackageUploadStatus = ""
+	UploadNew        PackageUploadStatus = "New"
+	UploadUnapproved PackageUploadStatus = "Unapproved"
+	UploadAccepted   PackageUploadStatus = "Accepted"
+	UploadDone       PackageUploadStatus = "Done"
+	UploadRejected   PackageUploadStatus = "Rejected"
+)
+
+// PackageUploads returns the uploads in the queues of this distribution
+// series matching the given criteria.  If status is UploadAny or pocket
+// is PocketAny, uploads are not filtered by that criteria.  If name is
+// non-empty, only uploads of packages with that name are returned, and
+// exactMatch defines whether the name must match exactly or may be
+// just a substring of the package name.
+func (s *DistroSeries) PackageUploads(status PackageUploadStatus, pocket Pocket, name string, exactMatch bool) (*PackageUploadList, error) {
+	params := Params{"ws.op": "getPackageUploads"}
+	if status != UploadAny {
+		params["status"] = string(status)
+	}
+	if pocket != PocketAny {
+		params["pocket"] = string(pocket)
+	}
+	if name != "" {
+		params["name"] = name
+		if exactMatch {
+			params["exact_match"] = "true"
+		} else {
+			params["exact_match"] = "false"
+		}
+	}
+	v, err := s.Location("").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &PackageUploadList{v}, nil
+}
+
+// The PackageUpload type represents an upload waiting in or
+// processed through the queues of a distribution series.
+type PackageUpload struct {
+	*Value
+}
+
+// Status returns the current status of the upload in the queue.
+func (u *PackageUpload) Status() PackageUploadStatus {
+	return PackageUploadStatus(u.StringField("status"))
+}
+
+// Pocket returns the pocket the upload is targeted at.
+func (u *PackageUpload) Pocket() Pocket {
+	return Pocket(u.StringField("pocket"))
+}
+
+// PackageName returns the name of the source package in the upload,
+// or the empty string if the upload contains no source.
+func (u *PackageUpload) PackageName() string {
+	return u.StringField("package_name")
+}
+
+// PackageVersion returns the version of the source package in the upload,
+// or the empty string if the upload contains no source.
+func (u *PackageUpload) PackageVersion() string {
+	return u.StringField("package_version")
+}
+
+// DisplayName returns the name of the upload as displayed in the queue
+// listing.  For source uploads this is the source package name, while
+// for binary and custom uploads it lists the file names involved.
+func (u *PackageUpload) DisplayName() string {
+	return u.StringField("display_name")
+}
+
+// DisplayVersion returns the version of the upload as displayed in
+// the queue listing.
+func (u *PackageUpload) DisplayVersion() string {
+	return u.StringField("display_version")
+}
+
+// DisplayArches returns the architectures of the upload as displayed
+// in the queue listing (e.g. "source, i386").
+func (u *PackageUpload) DisplayArches() string {
+	return u.StringField("display_arches")
+}
+
+// ContainsSource returns true if the upload contains a source package.
+func (u *PackageUpload) ContainsSource() bool {
+	return u.BoolField("contains_source")
+}
+
+// ContainsBuild returns true if the upload contains binary packages.
+func (u *PackageUpload) ContainsBuild() bool {
+	return u.BoolField("contains_build")
+}
+
+// CustomFileURLs returns the URLs for the custom files in the upload,
+// such as installer images or translation tarballs.
+func (u *PackageUpload) CustomFileURLs() []string {
+	return u.StringListField("custom_file_urls")
+}
+
+// ChangesFileURL returns the URL for the .changes file of the upload.
+func (u *PackageUpload) ChangesFileURL() string {
+	return u.StringField("changes_file_url")
+}
+
+// Created returns the timestamp when the upload entered the queue.
+func (u *PackageUpload) Created() string {
+	return u.StringField("date_created")
+}
+
+// Archive returns the archive the upload is targeted at.
+func (u *PackageUpload) Archive() (*Archive, error) {
+	v, err := u.Link("archive_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{v}, nil
+}
+
+// DistroSeries returns the distribution series the upload is targeted at.
+func (u *PackageUpload) DistroSeries() (*DistroSeries, error) {
+	v, err := u.Link("distroseries_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroSeries{v}, nil
+}
+
+// BinaryNames returns the names of the binary packages in the upload.
+func (u *PackageUpload) BinaryNames() (names []string, err error) {
+	v, err := u.Location("").Get(Params{"ws.op": "getBinaryProperties"})
+	if err != nil {
+		return nil, err
+	}
+	l, ok := v.Map()["value"].([]interface{})
+	if !ok {
+		return nil, fmt.Errorf(`map is missing "value" field`)
+	}
+	for i := range l {
+		props, ok := l[i].(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("unsupported binary properties item: %#v", l[i])
+		}
+		name, ok := props["name"].(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported binary properties item: %#v", l[i])
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// Accept accepts the upload into its target archive.
+func (u *PackageUpload) Accept() error {
+	_, err := u.Post(Params{"ws.op": "acceptFromQueue"})
+	return err
+}
+
+// Reject rejects the upload, notifying the uploader with the
+// given comment.
+func (u *PackageUpload) Reject(comment string) error {
+	params := Params{"ws.op": "rejectFromQueue"}
+	if comment != "" {
+		params["comment"] = comment
+	}
+	_, err := u.Post(params)
+	return err
+}
+
+// The PackageUploadList type represents a list of PackageUpload objects.
+type PackageUploadList struct {
+	*Value
+}
+
+// For iterates over the list of uploads and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *PackageUploadList) For(f func(u *PackageUpload) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&PackageUpload{v})
+	})
+}
@@ -0,0 +1,145 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestPackageUpload(c *C) {
+	m := M{
+		"status":            "Unapproved",
+		"pocket":            "Proposed",
+		"package_name":      "pkgname",
+		"package_version":   "1.0-1",
+		"display_name":      "pkgname",
+		"display_version":   "1.0-1",
+		"display_arches":    "source",
+		"contains_source":   true,
+		"contains_build":    false,
+		"custom_file_urls":  []interface{}{"http://custom1", "http://custom2"},
+		"changes_file_url":  "http://changes",
+		"date_created":      "2011-10-10T00:00:00",
+		"archive_link":      testServer.URL + "/archive_link",
+		"distroseries_link": testServer.URL + "/distroseries_link",
+	}
+	upload := &lpad.PackageUpload{lpad.NewValue(nil, "", "", m)}
+	c.Assert(upload.Status(), Equals, lpad.UploadUnapproved)
+	c.Assert(upload.Pocket(), Equals, lpad.PocketProposed)
+	c.Assert(upload.PackageName(), Equals, "pkgname")
+	c.Assert(upload.PackageVersion(), Equals, "1.0-1")
+	c.Assert(upload.DisplayName(), Equals, "pkgname")
+	c.Assert(upload.DisplayVersion(), Equals, "1.0-1")
+	c.Assert(upload.DisplayArches(), Equals, "source")
+	c.Assert(upload.ContainsSource(), Equals, true)
+	c.Assert(upload.ContainsBuild(), Equals, false)
+	c.Assert(upload.CustomFileURLs(), DeepEquals, []string{"http://custom1", "http://custom2"})
+	c.Assert(upload.ChangesFileURL(), Equals, "http://changes")
+	c.Assert(upload.Created(), Equals, "2011-10-10T00:00:00")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "archivename"}`)
+	archive, err := upload.Archive()
+	c.Assert(err, IsNil)
+	c.Assert(archive.Name(), Equals, "archivename")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/archive_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "seriesname"}`)
+	series, err := upload.DistroSeries()
+	c.Assert(err, IsNil)
+	c.Assert(series.Name(), Equals, "seriesname")
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/distroseries_link")
+}
+
+func (s *ModelS) TestDistroSeriesPackageUploads(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"display_name": "Name0"
+		}, {
+			"self_link": "http://self1",
+			"display_name": "Name1"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	series := &lpad.DistroSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/series", nil)}
+	list, err := series.PackageUploads(lpad.UploadNew, lpad.PocketUpdates, "pkgname", true)
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	names := []string{}
+	list.For(func(u *lpad.PackageUpload) error {
+		names = append(names, u.DisplayName())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"Name0", "Name1"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/series")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getPackageUploads"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"New"})
+	c.Assert(req.Form["pocket"], DeepEquals, []string{"Updates"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"pkgname"})
+	c.Assert(req.Form["exact_match"], DeepEquals, []string{"true"})
+}
+
+func (s *ModelS) TestDistroSeriesPackageUploadsAny(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"total_size": 0, "start": 0, "entries": []}`)
+	series := &lpad.DistroSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/series", nil)}
+	_, err := series.PackageUploads(lpad.UploadAny, lpad.PocketAny, "", false)
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getPackageUploads"})
+	c.Assert(req.Form["status"], IsNil)
+	c.Assert(req.Form["pocket"], IsNil)
+	c.Assert(req.Form["name"], IsNil)
+	c.Assert(req.Form["exact_match"], IsNil)
+}
+
+func (s *ModelS) TestPackageUploadBinaryNames(c *C) {
+	data := `[{"name": "bin1", "version": "1.0"}, {"name": "bin2", "version": "1.0"}]`
+	testServer.PrepareResponse(200, jsonType, data)
+	upload := &lpad.PackageUpload{lpad.NewValue(nil, testServer.URL, testServer.URL+"/upload", nil)}
+	names, err := upload.BinaryNames()
+	c.Assert(err, IsNil)
+	c.Assert(names, DeepEquals, []string{"bin1", "bin2"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/upload")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getBinaryProperties"})
+}
+
+func (s *ModelS) TestPackageUploadAccept(c *C) {
+	testServer.PrepareResponse(200, jsonType, "{}")
+	upload := &lpad.PackageUpload{lpad.NewValue(nil, testServer.URL, testServer.URL+"/upload", nil)}
+	err := upload.Accept()
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/upload")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"acceptFromQueue"})
+}
+
+func (s *ModelS) TestPackageUploadReject(c *C) {
+	testServer.PrepareResponse(200, jsonType, "{}")
+	upload := &lpad.PackageUpload{lpad.NewValue(nil, testServer.URL, testServer.URL+"/upload", nil)}
+	err := upload.Reject("Bad version")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/upload")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"rejectFromQueue"})
+	c.Assert(req.Form["comment"], DeepEquals, []string{"Bad version"})
+}
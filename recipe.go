@@ -0,0 +1,223 @@
+package lpad
+
+// Recipes returns the list of source package recipes owned by the person.
+func (person *Person) Recipes() (*SourcePackageRecipeList, error) {
+	v, err := person.Link("recipes_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SourcePackageRecipeList{v}, nil
+}
+
+// Recipe returns the source package recipe with the given name
+// owned by the person.
+func (person *Person) Recipe(name string) (*SourcePackageRecipe, error) {
+	v, err := person.Location("").Get(Params{"ws.op": "getRecipe", "name": name})
+	if err != nil {
+		return nil, err
+	}
+	return &SourcePackageRecipe{v}, nil
+}
+
+// The SourcePackageRecipe type represents a recipe for building source
+// packages out of one or more branches.
+type SourcePackageRecipe struct {
+	*Value
+}
+
+// Name returns the recipe name.
+func (r *SourcePackageRecipe) Name() string {
+	return r.StringField("name")
+}
+
+// Description returns the recipe description.
+func (r *SourcePackageRecipe) Description() string {
+	return r.StringField("description")
+}
+
+// WebPage returns the URL for accessing this recipe in a browser.
+func (r *SourcePackageRecipe) WebPage() string {
+	return r.StringField("web_link")
+}
+
+// Owner returns the Person or Team that owns the recipe.
+func (r *SourcePackageRecipe) Owner() (Member, error) {
+	v, err := r.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// Text returns the recipe text, describing how the branches are
+// combined into a source package.
+func (r *SourcePackageRecipe) Text() string {
+	return r.StringField("recipe_text")
+}
+
+// SetText changes the recipe text. Unlike field setters, the change
+// is sent to Launchpad immediately.
+func (r *SourcePackageRecipe) SetText(text string) error {
+	_, err := r.Post(Params{"ws.op": "setRecipeText", "recipe_text": text})
+	return err
+}
+
+// BuildDaily returns true if the recipe is built automatically every
+// day when its branches change.
+func (r *SourcePackageRecipe) BuildDaily() bool {
+	return r.BoolField("build_daily")
+}
+
+// SetBuildDaily changes whether the recipe is built automatically every
+// day when its branches change.
+// Patch must be called to commit all changes.
+func (r *SourcePackageRecipe) SetBuildDaily(daily bool) {
+	r.SetField("build_daily", daily)
+}
+
+// DailyBuildArchive returns the archive daily builds are uploaded to.
+func (r *SourcePackageRecipe) DailyBuildArchive() (*Archive, error) {
+	v, err := r.Link("daily_build_archive_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{v}, nil
+}
+
+// SetDailyBuildArchive changes the archive daily builds are uploaded to.
+// Patch must be called to commit all changes.
+func (r *SourcePackageRecipe) SetDailyBuildArchive(archive *Archive) {
+	r.SetField("daily_build_archive_link", archive.AbsLoc())
+}
+
+// DistroSeries returns the list of distribution series daily builds
+// are made for.
+func (r *SourcePackageRecipe) DistroSeries() (*DistroSeriesList, error) {
+	v, err := r.Link("distroseries_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &DistroSeriesList{v}, nil
+}
+
+// RequestBuild requests a build of the recipe for series, to be
+// uploaded to the given pocket of archive.
+func (r *SourcePackageRecipe) RequestBuild(archive *Archive, series *DistroSeries, pocket Pocket) (*RecipeBuild, error) {
+	params := Params{
+		"ws.op":        "requestBuild",
+		"archive":      archive.AbsLoc(),
+		"distroseries": series.AbsLoc(),
+		"pocket":       string(pocket),
+	}
+	v, err := r.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeBuild{v}, nil
+}
+
+// Builds returns the list of all builds of the recipe.
+func (r *SourcePackageRecipe) Builds() (*RecipeBuildList, error) {
+	v, err := r.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeBuildList{v}, nil
+}
+
+// PendingBuilds returns the list of builds of the recipe that
+// haven't finished yet.
+func (r *SourcePackageRecipe) PendingBuilds() (*RecipeBuildList, error) {
+	v, err := r.Link("pending_builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &RecipeBuildList{v}, nil
+}
+
+// The SourcePackageRecipeList type represents a list of
+// SourcePackageRecipe objects.
+type SourcePackageRecipeList struct {
+	*Value
+}
+
+// For iterates over the list of recipes and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *SourcePackageRecipeList) For(f func(r *SourcePackageRecipe) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&SourcePackageRecipe{v})
+	})
+}
+
+// The RecipeBuild type describes a build of a source package recipe.
+type RecipeBuild struct {
+	*Value
+}
+
+// Title returns the build title.
+func (build *RecipeBuild) Title() string {
+	return build.StringField("title")
+}
+
+// WebPage returns the URL for accessing this build in a browser.
+func (build *RecipeBuild) WebPage() string {
+	return build.StringField("web_link")
+}
+
+// State returns the state of build.
+func (build *RecipeBuild) State() BuildState {
+	return BuildState(build.StringField("buildstate"))
+}
+
+// BuildLogURL returns the URL for the build log file.
+func (build *RecipeBuild) BuildLogURL() string {
+	return build.StringField("build_log_url")
+}
+
+// UploadLogURL returns the URL for the upload log if there was an upload failure.
+func (build *RecipeBuild) UploadLogURL() string {
+	return build.StringField("upload_log_url")
+}
+
+// Created returns the timestamp when the build farm job was created.
+func (build *RecipeBuild) Created() string {
+	return build.StringField("datecreated")
+}
+
+// Finished returns the timestamp when the build farm job was finished.
+func (build *RecipeBuild) Finished() string {
+	return build.StringField("datebuilt")
+}
+
+// Archive returns the archive the build is uploaded to.
+func (build *RecipeBuild) Archive() (*Archive, error) {
+	v, err := build.Link("archive_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Archive{v}, nil
+}
+
+// Recipe returns the recipe being built.
+func (build *RecipeBuild) Recipe() (*SourcePackageRecipe, error) {
+	v, err := build.Link("recipe_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SourcePackageRecipe{v}, nil
+}
+
+// The RecipeBuildList type represents a list of RecipeBuild objects.
+type RecipeBuildList struct {
+	*Value
+}
+
+// For iterates over the list of recipe builds and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *RecipeBuildList) For(f func(b *RecipeBuild) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&RecipeBuild{v})
+	})
+}
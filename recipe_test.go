@@ -0,0 +1,203 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestSourcePackageRecipe(c *C) {
+	m := M{
+		"name":        "daily",
+		"description": "Description",
+		"web_link":    "http://page",
+		"owner_link":  testServer.URL + "/owner_link",
+	}
+	recipe := &lpad.SourcePackageRecipe{lpad.NewValue(nil, "", "", m)}
+	c.Assert(recipe.Name(), Equals, "daily")
+	c.Assert(recipe.Description(), Equals, "Description")
+	c.Assert(recipe.WebPage(), Equals, "http://page")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "ensemble", "is_team": true}`)
+	owner, err := recipe.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "ensemble")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+}
+
+func (s *ModelS) TestPersonRecipes(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"name": "daily"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"recipes_collection_link": testServer.URL + "/recipes_link"}
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := person.Recipes()
+	c.Assert(err, IsNil)
+
+	names := []string{}
+	list.For(func(r *lpad.SourcePackageRecipe) error {
+		names = append(names, r.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"daily"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/recipes_link")
+}
+
+func (s *ModelS) TestPersonRecipe(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "daily"}`)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	recipe, err := person.Recipe("daily")
+	c.Assert(err, IsNil)
+	c.Assert(recipe.Name(), Equals, "daily")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getRecipe"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"daily"})
+}
+
+func (s *ModelS) TestSourcePackageRecipeSettings(c *C) {
+	m := M{
+		"recipe_text":                  "# bzr-builder format 0.3 deb-version {debupstream}-0~{revno}\nlp:proj",
+		"build_daily":                  true,
+		"daily_build_archive_link":     testServer.URL + "/archive_link",
+		"distroseries_collection_link": testServer.URL + "/series_link",
+	}
+	recipe := &lpad.SourcePackageRecipe{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe/+recipe/daily", m)}
+	c.Assert(recipe.Text(), Equals, m["recipe_text"])
+	c.Assert(recipe.BuildDaily(), Equals, true)
+	recipe.SetBuildDaily(false)
+	c.Assert(recipe.BuildDaily(), Equals, false)
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "ppa"}`)
+	archive, err := recipe.DailyBuildArchive()
+	c.Assert(err, IsNil)
+	c.Assert(archive.Name(), Equals, "ppa")
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/archive_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"total_size": 1, "start": 0, "entries": [{"name": "oneiric"}]}`)
+	list, err := recipe.DistroSeries()
+	c.Assert(err, IsNil)
+	names := []string{}
+	list.For(func(s *lpad.DistroSeries) error {
+		names = append(names, s.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"oneiric"})
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/series_link")
+
+	other := &lpad.Archive{lpad.NewValue(nil, "", "http://other_archive", nil)}
+	recipe.SetDailyBuildArchive(other)
+	c.Assert(recipe.StringField("daily_build_archive_link"), Equals, "http://other_archive")
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = recipe.SetText("new text")
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~joe/+recipe/daily")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"setRecipeText"})
+	c.Assert(req.Form["recipe_text"], DeepEquals, []string{"new text"})
+}
+
+func (s *ModelS) TestSourcePackageRecipeRequestBuild(c *C) {
+	recipe := &lpad.SourcePackageRecipe{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe/+recipe/daily", nil)}
+	archive := &lpad.Archive{lpad.NewValue(nil, "", "http://archive", nil)}
+	series := &lpad.DistroSeries{lpad.NewValue(nil, "", "http://series", nil)}
+
+	testServer.PrepareResponse(200, jsonType, `{"title": "Build"}`)
+	build, err := recipe.RequestBuild(archive, series, lpad.PocketRelease)
+	c.Assert(err, IsNil)
+	c.Assert(build.Title(), Equals, "Build")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~joe/+recipe/daily")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"requestBuild"})
+	c.Assert(req.Form["archive"], DeepEquals, []string{"http://archive"})
+	c.Assert(req.Form["distroseries"], DeepEquals, []string{"http://series"})
+	c.Assert(req.Form["pocket"], DeepEquals, []string{"Release"})
+}
+
+func (s *ModelS) TestSourcePackageRecipeBuilds(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"title": "Build0",
+			"buildstate": "Successfully built"
+		}, {
+			"self_link": "http://self1",
+			"title": "Build1",
+			"buildstate": "Needs building"
+		}]
+	}`
+	m := M{
+		"builds_collection_link":         testServer.URL + "/builds",
+		"pending_builds_collection_link": testServer.URL + "/pending",
+	}
+	recipe := &lpad.SourcePackageRecipe{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := recipe.Builds()
+	c.Assert(err, IsNil)
+	states := []lpad.BuildState{}
+	list.For(func(b *lpad.RecipeBuild) error {
+		states = append(states, b.State())
+		return nil
+	})
+	c.Assert(states, DeepEquals, []lpad.BuildState{lpad.BSSuccessfullyBuilt, lpad.BSNeedsBuilding})
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/builds")
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = recipe.PendingBuilds()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/pending")
+}
+
+func (s *ModelS) TestRecipeBuild(c *C) {
+	m := M{
+		"title":          "Title",
+		"web_link":       "http://page",
+		"buildstate":     "Failed to build",
+		"build_log_url":  "http://log",
+		"upload_log_url": "http://upload_log",
+		"datecreated":    "2011-10-10T00:00:00",
+		"datebuilt":      "2011-10-10T00:00:10",
+		"archive_link":   testServer.URL + "/archive_link",
+		"recipe_link":    testServer.URL + "/recipe_link",
+	}
+	build := &lpad.RecipeBuild{lpad.NewValue(nil, "", "", m)}
+	c.Assert(build.Title(), Equals, "Title")
+	c.Assert(build.WebPage(), Equals, "http://page")
+	c.Assert(build.State(), Equals, lpad.BSFailedToBuild)
+	c.Assert(build.BuildLogURL(), Equals, "http://log")
+	c.Assert(build.UploadLogURL(), Equals, "http://upload_log")
+	c.Assert(build.Created(), Equals, "2011-10-10T00:00:00")
+	c.Assert(build.Finished(), Equals, "2011-10-10T00:00:10")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "ppa"}`)
+	archive, err := build.Archive()
+	c.Assert(err, IsNil)
+	c.Assert(archive.Name(), Equals, "ppa")
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/archive_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "daily"}`)
+	recipe, err := build.Recipe()
+	c.Assert(err, IsNil)
+	c.Assert(recipe.Name(), Equals, "daily")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/recipe_link")
+}
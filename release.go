@@ -0,0 +1,291 @@
+package lpad
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// CreateRelease publishes a release of the project for the milestone.
+// The date is the time the release happened, in the format
+// "2006-01-02T15:04:05Z", and changelog and notes may be empty.
+func (ms *Milestone) CreateRelease(date, changelog, notes string) (*ProjectRelease, error) {
+	params := Params{
+		"ws.op":         "createProductRelease",
+		"date_released": date,
+	}
+	if changelog != "" {
+		params["changelog"] = changelog
+	}
+	if notes != "" {
+		params["release_notes"] = notes
+	}
+	v, err := ms.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectRelease{v}, nil
+}
+
+// Release returns the project release made for the milestone, or
+// ErrNotFound if the milestone wasn't released yet.
+func (ms *Milestone) Release() (*ProjectRelease, error) {
+	v, err := ms.Link("release_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectRelease{v}, nil
+}
+
+// Releases returns the list of releases made for the project.
+func (p *Project) Releases() (*ProjectReleaseList, error) {
+	v, err := p.Link("releases_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectReleaseList{v}, nil
+}
+
+// The ProjectRelease type represents a release of a project
+// made for one of its milestones.
+type ProjectRelease struct {
+	*Value
+}
+
+// Version returns the version of the release, which is the name
+// of the milestone it was made for.
+func (r *ProjectRelease) Version() string {
+	return r.StringField("version")
+}
+
+// Title returns the release context title for pages.
+func (r *ProjectRelease) Title() string {
+	return r.StringField("title")
+}
+
+// Date returns the time the release happened.
+func (r *ProjectRelease) Date() string {
+	return r.StringField("date_released")
+}
+
+// Changelog returns the detailed list of changes in the release.
+func (r *ProjectRelease) Changelog() string {
+	return r.StringField("changelog")
+}
+
+// Notes returns the release notes.
+func (r *ProjectRelease) Notes() string {
+	return r.StringField("release_notes")
+}
+
+// WebPage returns the URL for accessing this release in a browser.
+func (r *ProjectRelease) WebPage() string {
+	return r.StringField("web_link")
+}
+
+// SetChangelog changes the detailed list of changes in the release.
+// Patch must be called to commit all changes.
+func (r *ProjectRelease) SetChangelog(changelog string) {
+	r.SetField("changelog", changelog)
+}
+
+// SetNotes changes the release notes.
+// Patch must be called to commit all changes.
+func (r *ProjectRelease) SetNotes(notes string) {
+	r.SetField("release_notes", notes)
+}
+
+// Milestone returns the milestone the release was made for.
+func (r *ProjectRelease) Milestone() (*Milestone, error) {
+	v, err := r.Link("milestone_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Milestone{v}, nil
+}
+
+// Project returns the project the release was made for.
+func (r *ProjectRelease) Project() (*Project, error) {
+	v, err := r.Link("project_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Project{v}, nil
+}
+
+// Files returns the list of files published with the release.
+func (r *ProjectRelease) Files() (*ProjectReleaseFileList, error) {
+	v, err := r.Link("files_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectReleaseFileList{v}, nil
+}
+
+// AddReleaseFile uploads a file to be published with the release.
+// The content type is the MIME type of the file, such as
+// "application/x-gzip". If signature is not nil, its content is
+// uploaded as the detached GPG signature for the file, under the
+// name filename + ".asc".
+func (r *ProjectRelease) AddReleaseFile(filename, contentType string, fileType ReleaseFileType, description string, content, signature io.Reader) (*ProjectReleaseFile, error) {
+	params := Params{
+		"ws.op":        "add_file",
+		"filename":     filename,
+		"content_type": contentType,
+		"file_type":    string(fileType),
+	}
+	if description != "" {
+		params["description"] = description
+	}
+	files := []formFile{{"file_content", filename, content}}
+	if signature != nil {
+		params["signature_filename"] = filename + ".asc"
+		files = append(files, formFile{"signature_content", filename + ".asc", signature})
+	}
+	v, err := r.postFiles(params, files)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectReleaseFile{v}, nil
+}
+
+// The ProjectReleaseList type represents a list of ProjectRelease objects.
+type ProjectReleaseList struct {
+	*Value
+}
+
+// For iterates over the list of releases and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *ProjectReleaseList) For(f func(r *ProjectRelease) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&ProjectRelease{v})
+	})
+}
+
+// A ReleaseFileType holds the kind of a file published with a release.
+type ReleaseFileType string
+
+const (
+	FileCodeTarball  ReleaseFileType = "Code Release Tarball"
+	FileReadme       ReleaseFileType = "README File"
+	FileReleaseNotes ReleaseFileType = "Release Notes"
+	FileChangelog    ReleaseFileType = "ChangeLog File"
+	FileInstaller    ReleaseFileType = "Installer file"
+)
+
+// The ProjectReleaseFile type represents a file published with
+// a project release.
+type ProjectReleaseFile struct {
+	*Value
+}
+
+// Type returns the kind of file.
+func (f *ProjectReleaseFile) Type() ReleaseFileType {
+	return ReleaseFileType(f.StringField("file_type"))
+}
+
+// Description returns the description of the file.
+func (f *ProjectReleaseFile) Description() string {
+	return f.StringField("description")
+}
+
+// DateUploaded returns the time the file was uploaded.
+func (f *ProjectReleaseFile) DateUploaded() string {
+	return f.StringField("date_uploaded")
+}
+
+// FileURL returns the URL the file content may be downloaded from.
+func (f *ProjectReleaseFile) FileURL() string {
+	return f.StringField("file_link")
+}
+
+// SignatureURL returns the URL the detached signature for the file
+// may be downloaded from, or the empty string if there's no signature.
+func (f *ProjectReleaseFile) SignatureURL() string {
+	return f.StringField("signature_link")
+}
+
+// Name returns the name of the file.
+func (f *ProjectReleaseFile) Name() string {
+	name, err := url.QueryUnescape(path.Base(f.AbsLoc()))
+	if err != nil {
+		return path.Base(f.AbsLoc())
+	}
+	return name
+}
+
+// Release returns the project release the file was published with.
+func (f *ProjectReleaseFile) Release() (*ProjectRelease, error) {
+	v, err := f.Link("project_release_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &ProjectRelease{v}, nil
+}
+
+// MD5 returns the hex-encoded MD5 checksum published by Launchpad
+// for the file content.
+func (f *ProjectReleaseFile) MD5() (string, error) {
+	r, err := f.Release()
+	if err != nil {
+		return "", err
+	}
+	// The checksum is only published on the web site, so it's fetched
+	// through a value without an API base, which is never signed.
+	loc := strings.TrimRight(r.WebPage(), "/") + "/+download/" + url.QueryEscape(f.Name()) + "/+md5"
+	data, err := (&Value{session: f.session, loc: loc}).download()
+	if err != nil {
+		return "", err
+	}
+	// The content is formatted as the output of md5sum.
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return "", errors.New("empty MD5 checksum for " + f.Name())
+	}
+	return fields[0], nil
+}
+
+// Download copies the content of the file into w as it arrives, and
+// verifies that it matches the MD5 checksum published by Launchpad.
+// The checksum can only be verified once the whole content has been
+// copied, so w must be discarded if an error is returned.
+func (f *ProjectReleaseFile) Download(w io.Writer) error {
+	sum, err := f.MD5()
+	if err != nil {
+		return err
+	}
+	h := md5.New()
+	if err := f.Link("file_link").downloadTo(io.MultiWriter(w, h)); err != nil {
+		return err
+	}
+	if hex.EncodeToString(h.Sum(nil)) != strings.ToLower(sum) {
+		return errors.New("MD5 checksum mismatch for " + f.Name())
+	}
+	return nil
+}
+
+// DownloadSignature returns the detached GPG signature for the file,
+// or ErrNotFound if the file has no signature.
+func (f *ProjectReleaseFile) DownloadSignature() ([]byte, error) {
+	return f.Link("signature_link").download()
+}
+
+// The ProjectReleaseFileList type represents a list of
+// ProjectReleaseFile objects.
+type ProjectReleaseFileList struct {
+	*Value
+}
+
+// For iterates over the list of files and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *ProjectReleaseFileList) For(f func(f *ProjectReleaseFile) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&ProjectReleaseFile{v})
+	})
+}
@@ -0,0 +1,275 @@
+package lpad_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestProjectRelease(c *C) {
+	m := M{
+		"version":        "1.0",
+		"title":          "Title",
+		"date_released":  "2011-08-31T10:00:00Z",
+		"changelog":      "Changelog",
+		"release_notes":  "Notes",
+		"web_link":       "http://page",
+		"milestone_link": testServer.URL + "/milestone_link",
+		"project_link":   testServer.URL + "/project_link",
+	}
+	r := &lpad.ProjectRelease{lpad.NewValue(nil, "", "", m)}
+	c.Assert(r.Version(), Equals, "1.0")
+	c.Assert(r.Title(), Equals, "Title")
+	c.Assert(r.Date(), Equals, "2011-08-31T10:00:00Z")
+	c.Assert(r.Changelog(), Equals, "Changelog")
+	c.Assert(r.Notes(), Equals, "Notes")
+	c.Assert(r.WebPage(), Equals, "http://page")
+	r.SetChangelog("New changelog")
+	r.SetNotes("New notes")
+	c.Assert(r.Changelog(), Equals, "New changelog")
+	c.Assert(r.Notes(), Equals, "New notes")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "1.0"}`)
+	ms, err := r.Milestone()
+	c.Assert(err, IsNil)
+	c.Assert(ms.Name(), Equals, "1.0")
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/milestone_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "proj"}`)
+	project, err := r.Project()
+	c.Assert(err, IsNil)
+	c.Assert(project.Name(), Equals, "proj")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/project_link")
+}
+
+func (s *ModelS) TestMilestoneCreateRelease(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"version": "1.0"}`)
+	ms := &lpad.Milestone{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ms", nil)}
+	r, err := ms.CreateRelease("2011-08-31T10:00:00Z", "Changelog", "")
+	c.Assert(err, IsNil)
+	c.Assert(r.Version(), Equals, "1.0")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/ms")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"createProductRelease"})
+	c.Assert(req.Form["date_released"], DeepEquals, []string{"2011-08-31T10:00:00Z"})
+	c.Assert(req.Form["changelog"], DeepEquals, []string{"Changelog"})
+
+	_, ok := req.Form["release_notes"]
+	c.Assert(ok, Equals, false)
+}
+
+func (s *ModelS) TestMilestoneRelease(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"version": "1.0"}`)
+	m := M{"release_link": testServer.URL + "/release_link"}
+	ms := &lpad.Milestone{lpad.NewValue(nil, "", "", m)}
+	r, err := ms.Release()
+	c.Assert(err, IsNil)
+	c.Assert(r.Version(), Equals, "1.0")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/release_link")
+}
+
+func (s *ModelS) TestProjectReleases(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"version": "1.0"
+		}, {
+			"self_link": "http://self1",
+			"version": "1.1"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"releases_collection_link": testServer.URL + "/col_link"}
+	project := &lpad.Project{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := project.Releases()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	versions := []string{}
+	list.For(func(r *lpad.ProjectRelease) error {
+		versions = append(versions, r.Version())
+		return nil
+	})
+	c.Assert(versions, DeepEquals, []string{"1.0", "1.1"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/col_link")
+}
+
+func (s *ModelS) TestProjectReleaseFiles(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"file_type": "Code Release Tarball",
+			"description": "Tarball",
+			"date_uploaded": "2011-08-31T10:00:00Z",
+			"file_link": "http://file0",
+			"signature_link": "http://sig0"
+		}, {
+			"self_link": "http://self1",
+			"file_type": "README File",
+			"description": "Readme",
+			"file_link": "http://file1"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"files_collection_link": testServer.URL + "/col_link"}
+	r := &lpad.ProjectRelease{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := r.Files()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+
+	var files []*lpad.ProjectReleaseFile
+	list.For(func(f *lpad.ProjectReleaseFile) error {
+		files = append(files, f)
+		return nil
+	})
+	c.Assert(files, HasLen, 2)
+	c.Assert(files[0].Type(), Equals, lpad.FileCodeTarball)
+	c.Assert(files[0].Description(), Equals, "Tarball")
+	c.Assert(files[0].DateUploaded(), Equals, "2011-08-31T10:00:00Z")
+	c.Assert(files[0].FileURL(), Equals, "http://file0")
+	c.Assert(files[0].SignatureURL(), Equals, "http://sig0")
+	c.Assert(files[1].Type(), Equals, lpad.FileReadme)
+	c.Assert(files[1].SignatureURL(), Equals, "")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/col_link")
+}
+
+func (s *ModelS) TestProjectReleaseAddReleaseFile(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"description": "Tarball"}`)
+	r := &lpad.ProjectRelease{lpad.NewValue(nil, testServer.URL, testServer.URL+"/release", nil)}
+	content := strings.NewReader("tarball content")
+	signature := strings.NewReader("signature content")
+	f, err := r.AddReleaseFile("proj-1.0.tar.gz", "application/x-gzip", lpad.FileCodeTarball, "Tarball", content, signature)
+	c.Assert(err, IsNil)
+	c.Assert(f.Description(), Equals, "Tarball")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/release")
+	c.Assert(req.Header.Get("Content-Type"), Matches, "multipart/form-data; boundary=.*")
+
+	err = req.ParseMultipartForm(1 << 20)
+	c.Assert(err, IsNil)
+	form := req.MultipartForm
+	c.Assert(form.Value["ws.op"], DeepEquals, []string{"add_file"})
+	c.Assert(form.Value["filename"], DeepEquals, []string{"proj-1.0.tar.gz"})
+	c.Assert(form.Value["content_type"], DeepEquals, []string{"application/x-gzip"})
+	c.Assert(form.Value["file_type"], DeepEquals, []string{"Code Release Tarball"})
+	c.Assert(form.Value["description"], DeepEquals, []string{"Tarball"})
+	c.Assert(form.Value["signature_filename"], DeepEquals, []string{"proj-1.0.tar.gz.asc"})
+
+	checkPart := func(field, filename, content string) {
+		c.Assert(form.File[field], HasLen, 1)
+		fh := form.File[field][0]
+		c.Assert(fh.Filename, Equals, filename)
+		file, err := fh.Open()
+		c.Assert(err, IsNil)
+		defer file.Close()
+		data, err := ioutil.ReadAll(file)
+		c.Assert(err, IsNil)
+		c.Assert(string(data), Equals, content)
+	}
+	checkPart("file_content", "proj-1.0.tar.gz", "tarball content")
+	checkPart("signature_content", "proj-1.0.tar.gz.asc", "signature content")
+}
+
+func (s *ModelS) TestProjectReleaseAddReleaseFileNoSignature(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"description": "Readme"}`)
+	r := &lpad.ProjectRelease{lpad.NewValue(nil, testServer.URL, testServer.URL+"/release", nil)}
+	_, err := r.AddReleaseFile("README", "text/plain", lpad.FileReadme, "", strings.NewReader("readme"), nil)
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	err = req.ParseMultipartForm(1 << 20)
+	c.Assert(err, IsNil)
+	form := req.MultipartForm
+	c.Assert(form.File["file_content"], HasLen, 1)
+	c.Assert(form.File["signature_content"], HasLen, 0)
+	_, ok := form.Value["signature_filename"]
+	c.Assert(ok, Equals, false)
+	_, ok = form.Value["description"]
+	c.Assert(ok, Equals, false)
+}
+
+var textType = map[string]string{"Content-Type": "text/plain"}
+
+func (s *ModelS) TestProjectReleaseFileDownload(c *C) {
+	m := M{
+		"file_link":            testServer.URL + "/file/proj-1.0.tar.gz/file",
+		"signature_link":       testServer.URL + "/file/proj-1.0.tar.gz/signature",
+		"project_release_link": testServer.URL + "/release",
+	}
+	f := &lpad.ProjectReleaseFile{lpad.NewValue(nil, testServer.URL, testServer.URL+"/file/proj-1.0.tar.gz", m)}
+	c.Assert(f.Name(), Equals, "proj-1.0.tar.gz")
+
+	var buf bytes.Buffer
+	testServer.PrepareResponse(200, jsonType, `{"web_link": "`+testServer.URL+`/web/release"}`)
+	testServer.PrepareResponse(200, textType, "bac18c6a7f8ab1e3b7ab7f6bc8a4e1b6  proj-1.0.tar.gz\n")
+	testServer.PrepareResponse(200, textType, "orig")
+	err := f.Download(&buf)
+	c.Assert(err, ErrorMatches, "MD5 checksum mismatch for proj-1.0.tar.gz")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/release")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/web/release/+download/proj-1.0.tar.gz/+md5")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/file/proj-1.0.tar.gz/file")
+
+	buf.Reset()
+	testServer.PrepareResponse(200, jsonType, `{"web_link": "`+testServer.URL+`/web/release"}`)
+	testServer.PrepareResponse(200, textType, "025f253325b46929cd34f2a7c3c55e7c  proj-1.0.tar.gz\n")
+	testServer.PrepareResponse(200, textType, "orig")
+	err = f.Download(&buf)
+	c.Assert(err, IsNil)
+	c.Assert(buf.String(), Equals, "orig")
+	testServer.WaitRequest()
+	testServer.WaitRequest()
+	testServer.WaitRequest()
+
+	// The checksum is fetched from the web site without credentials.
+	auth := &dummyAuth{}
+	signed := &lpad.ProjectReleaseFile{lpad.NewValue(lpad.NewSession(auth), testServer.URL, testServer.URL+"/file/proj-1.0.tar.gz", m)}
+	testServer.PrepareResponse(200, jsonType, `{"web_link": "`+testServer.URL+`/web/release"}`)
+	testServer.PrepareResponse(200, textType, "025f253325b46929cd34f2a7c3c55e7c  proj-1.0.tar.gz\n")
+	sum, err := signed.MD5()
+	c.Assert(err, IsNil)
+	c.Assert(sum, Equals, "025f253325b46929cd34f2a7c3c55e7c")
+	testServer.WaitRequest()
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/web/release/+download/proj-1.0.tar.gz/+md5")
+	c.Assert(auth.signReq.URL.Path, Equals, "/release")
+
+	testServer.PrepareResponse(200, textType, "signature")
+
+	data, err := f.DownloadSignature()
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, "signature")
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/file/proj-1.0.tar.gz/signature")
+
+	testServer.PrepareResponse(404, textType, "")
+	_, err = f.DownloadSignature()
+	c.Assert(err, Equals, lpad.ErrNotFound)
+	testServer.WaitRequest()
+}
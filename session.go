@@ -13,7 +13,10 @@
 //
 package lpad
 
-import "net/http"
+import (
+	"net/http"
+	"sync"
+)
 
 // The Auth interface is implemented by types which are able to Login and
 // authenticate requests made against Launchpad.
@@ -35,20 +38,31 @@ const (
 // See the Login method for a convenient way to use lpad to access the
 // Launchpad API.
 type Session struct {
-	auth Auth
+	auth      Auth
+	transport http.RoundTripper
+
+	mu    sync.Mutex
+	descs map[string]*ServiceDescription
 }
 
 // Create a new session using the auth authenticator.  Creating sessions
 // explicitly is generally not necessary.  See the Login method for a
 // convenient way to use lpad to access the Launchpad API.
 func NewSession(auth Auth) *Session {
-	return &Session{auth}
+	return &Session{auth: auth}
 }
 
 func (s *Session) Sign(req *http.Request) (err error) {
 	return s.auth.Sign(req)
 }
 
+// SetTransport changes the transport used to deliver the requests made
+// in the session, such as one that records them for replaying in tests.
+// If t is nil, http.DefaultTransport is used.
+func (s *Session) SetTransport(t http.RoundTripper) {
+	s.transport = t
+}
+
 // Login returns a Root object with a new session authenticated in Launchpad
 // using the auth authenticator. This is the primary method to start using
 // the Launchpad API.
@@ -60,13 +60,37 @@ func (s *SessionS) TestLogin(c *C) {
 	c.Assert(req.URL.Path, Equals, "/")
 }
 
+type transportFunc func(req *http.Request) (*http.Response, error)
+
+func (f transportFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func (s *SessionS) TestSetTransport(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"ok": true}`)
+
+	root, err := lpad.Login(lpad.APIBase(testServer.URL), &dummyAuth{})
+	c.Assert(err, IsNil)
+	var urls []string
+	root.Session().SetTransport(transportFunc(func(req *http.Request) (*http.Response, error) {
+		urls = append(urls, req.URL.String())
+		return http.DefaultTransport.RoundTrip(req)
+	}))
+
+	_, err = root.Get(nil)
+	c.Assert(err, IsNil)
+	c.Assert(root.Map()["ok"], Equals, true)
+	c.Assert(urls, DeepEquals, []string{testServer.URL})
+	testServer.WaitRequest()
+}
+
 var lpadAuth = &lpad.OAuth{
 	Token:       "SfVJpl7pJgSLJX9cm0wj",
 	TokenSecret: "CXJGg1t5gTdjDqtFG0HNBFQn8WLWq8QQ3B2sHh9NmgLxQ6kGl9m123gQLZpDF8HFxQzk8HV78c9sGHQb",
 }
 
 func (s *SessionI) TestLogin(c *C) {
-	root, err := lpad.Login(lpad.Production, lpadAuth)
+	root := loginI(c, lpadAuth)
 	me, err := root.Me()
 	c.Assert(err, IsNil)
 	c.Assert(me.DisplayName(), Equals, "Lpad Test User")
@@ -0,0 +1,283 @@
+package lpad
+
+import "encoding/json"
+
+// Snap returns the snap package with the given name owned by owner.
+func (root *Root) Snap(owner Member, name string) (*Snap, error) {
+	params := Params{
+		"ws.op": "getByName",
+		"owner": owner.AbsLoc(),
+		"name":  name,
+	}
+	v, err := root.Location("/+snaps").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Snap{v}, nil
+}
+
+// The SnapStub type must be used for creating new snap packages
+// via Root.CreateSnap.
+type SnapStub struct {
+	Owner       Member  // Required
+	Name        string  // Required
+	GitRef      *GitRef // Required
+	Description string
+
+	// Processors holds the names of the architectures to build for,
+	// such as "amd64". If empty, Launchpad uses its defaults.
+	Processors []string
+
+	// AutoBuild enables building the snap automatically when the
+	// Git reference changes.
+	AutoBuild bool
+
+	// StoreUpload enables uploading successful builds to the store,
+	// under StoreName and releasing them to StoreChannels.
+	StoreUpload   bool
+	StoreName     string
+	StoreChannels []string
+}
+
+// CreateSnap registers a new snap package built from a Git reference.
+func (root *Root) CreateSnap(stub *SnapStub) (*Snap, error) {
+	params := Params{
+		"ws.op":        "new",
+		"owner":        stub.Owner.AbsLoc(),
+		"name":         stub.Name,
+		"git_ref":      stub.GitRef.AbsLoc(),
+		"auto_build":   "false",
+		"store_upload": "false",
+	}
+	if stub.Description != "" {
+		params["description"] = stub.Description
+	}
+	if len(stub.Processors) > 0 {
+		var processors []string
+		for _, name := range stub.Processors {
+			processors = append(processors, root.Location("/+processors/"+name).AbsLoc())
+		}
+		params["processors"] = jsonList(processors)
+	}
+	if stub.AutoBuild {
+		params["auto_build"] = "true"
+	}
+	if stub.StoreUpload {
+		params["store_upload"] = "true"
+		params["store_name"] = stub.StoreName
+		params["store_channels"] = jsonList(stub.StoreChannels)
+	}
+	v, err := root.Location("/+snaps").Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Snap{v}, nil
+}
+
+// Snaps returns the list of snap packages owned by the person.
+func (person *Person) Snaps() (*SnapList, error) {
+	params := Params{
+		"ws.op": "findByOwner",
+		"owner": person.AbsLoc(),
+	}
+	v, err := person.Location("/+snaps").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapList{v}, nil
+}
+
+// The Snap type represents a snap package built by Launchpad.
+type Snap struct {
+	*Value
+}
+
+// Name returns the snap name.
+func (snap *Snap) Name() string {
+	return snap.StringField("name")
+}
+
+// Description returns the snap description.
+func (snap *Snap) Description() string {
+	return snap.StringField("description")
+}
+
+// WebPage returns the URL for accessing this snap in a browser.
+func (snap *Snap) WebPage() string {
+	return snap.StringField("web_link")
+}
+
+// Owner returns the Person or Team that owns the snap.
+func (snap *Snap) Owner() (Member, error) {
+	v, err := snap.Link("owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// GitRef returns the Git reference the snap is built from.
+func (snap *Snap) GitRef() (*GitRef, error) {
+	v, err := snap.Link("git_ref_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &GitRef{v}, nil
+}
+
+// AutoBuild returns true if the snap is built automatically when
+// its source changes.
+func (snap *Snap) AutoBuild() bool {
+	return snap.BoolField("auto_build")
+}
+
+// SetAutoBuild changes whether the snap is built automatically when
+// its source changes.
+// Patch must be called to commit all changes.
+func (snap *Snap) SetAutoBuild(auto bool) {
+	snap.SetField("auto_build", auto)
+}
+
+// StoreUpload returns true if successful builds of the snap are
+// uploaded to the store.
+func (snap *Snap) StoreUpload() bool {
+	return snap.BoolField("store_upload")
+}
+
+// SetStoreUpload changes whether successful builds of the snap are
+// uploaded to the store.
+// Patch must be called to commit all changes.
+func (snap *Snap) SetStoreUpload(upload bool) {
+	snap.SetField("store_upload", upload)
+}
+
+// StoreName returns the name the snap is registered under in the store.
+func (snap *Snap) StoreName() string {
+	return snap.StringField("store_name")
+}
+
+// SetStoreName changes the name the snap is registered under in the store.
+// Patch must be called to commit all changes.
+func (snap *Snap) SetStoreName(name string) {
+	snap.SetField("store_name", name)
+}
+
+// StoreChannels returns the store channels uploaded builds are
+// released to.
+func (snap *Snap) StoreChannels() []string {
+	return snap.StringListField("store_channels")
+}
+
+// SetStoreChannels changes the store channels uploaded builds are
+// released to.
+// Patch must be called to commit all changes.
+func (snap *Snap) SetStoreChannels(channels []string) {
+	snap.SetField("store_channels", channels)
+}
+
+// RequestBuilds requests builds of the snap for all its architectures,
+// using packages from archive in the given pocket. The channels map
+// holds the store channel to use for each snap needed by the build,
+// such as {"core": "stable", "snapcraft": "edge"}, and may be nil.
+// Builds are created asynchronously. See the SnapBuildRequest type.
+func (snap *Snap) RequestBuilds(archive *Archive, pocket Pocket, channels map[string]string) (*SnapBuildRequest, error) {
+	params := Params{
+		"ws.op":   "requestBuilds",
+		"archive": archive.AbsLoc(),
+		"pocket":  string(pocket),
+	}
+	if len(channels) > 0 {
+		data, err := json.Marshal(channels)
+		if err != nil {
+			return nil, err
+		}
+		params["channels"] = string(data)
+	}
+	v, err := snap.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapBuildRequest{BuildRequest{v}}, nil
+}
+
+// Builds returns the list of all builds of the snap.
+func (snap *Snap) Builds() (*SnapBuildList, error) {
+	v, err := snap.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapBuildList{v}, nil
+}
+
+// PendingBuilds returns the list of builds of the snap that
+// haven't finished yet.
+func (snap *Snap) PendingBuilds() (*SnapBuildList, error) {
+	v, err := snap.Link("pending_builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapBuildList{v}, nil
+}
+
+// The SnapList type represents a list of Snap objects.
+type SnapList struct {
+	*Value
+}
+
+// For iterates over the list of snaps and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *SnapList) For(f func(snap *Snap) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&Snap{v})
+	})
+}
+
+// The SnapBuildRequest type represents a request for building a snap
+// for all its architectures. See the BuildRequest type for details.
+type SnapBuildRequest struct {
+	BuildRequest
+}
+
+// Builds returns the list of builds created by the request.
+func (req *SnapBuildRequest) Builds() (*SnapBuildList, error) {
+	v, err := req.Link("builds_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &SnapBuildList{v}, nil
+}
+
+// The SnapBuild type describes a build of a snap package for a
+// single architecture.
+type SnapBuild struct {
+	BuildFarmJob
+}
+
+// StoreUploadStatus returns the status of the upload of the build to the store.
+func (build *SnapBuild) StoreUploadStatus() string {
+	return build.StringField("store_upload_status")
+}
+
+// Snap returns the snap package being built.
+func (build *SnapBuild) Snap() (*Snap, error) {
+	v, err := build.Link("snap_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Snap{v}, nil
+}
+
+// The SnapBuildList type represents a list of SnapBuild objects.
+type SnapBuildList struct {
+	*Value
+}
+
+// For iterates over the list of snap builds and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *SnapBuildList) For(f func(b *SnapBuild) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&SnapBuild{BuildFarmJob{v}})
+	})
+}
@@ -0,0 +1,239 @@
+package lpad_test
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestSnap(c *C) {
+	m := M{
+		"name":        "mysnap",
+		"description": "Description",
+		"web_link":    "http://page",
+		"owner_link":  testServer.URL + "/owner_link",
+	}
+	snap := &lpad.Snap{lpad.NewValue(nil, "", "", m)}
+	c.Assert(snap.Name(), Equals, "mysnap")
+	c.Assert(snap.Description(), Equals, "Description")
+	c.Assert(snap.WebPage(), Equals, "http://page")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	owner, err := snap.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "joe")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+}
+
+func (s *ModelS) TestPersonSnaps(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"name": "mysnap"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	list, err := person.Snaps()
+	c.Assert(err, IsNil)
+
+	names := []string{}
+	list.For(func(snap *lpad.Snap) error {
+		names = append(names, snap.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"mysnap"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/+snaps")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"findByOwner"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{testServer.URL + "/~joe"})
+}
+
+func (s *ModelS) TestRootSnap(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "mysnap"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	owner := &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)}
+	snap, err := root.Snap(owner, "mysnap")
+	c.Assert(err, IsNil)
+	c.Assert(snap.Name(), Equals, "mysnap")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/+snaps")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getByName"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"mysnap"})
+}
+
+func (s *ModelS) TestRootCreateSnap(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "mysnap"}`)
+	root := &lpad.Root{lpad.NewValue(nil, testServer.URL, "", nil)}
+	stub := &lpad.SnapStub{
+		Owner:         &lpad.Person{lpad.NewValue(nil, "", "http://joe", nil)},
+		Name:          "mysnap",
+		GitRef:        &lpad.GitRef{lpad.NewValue(nil, "", "http://ref", nil)},
+		Processors:    []string{"amd64", "arm64"},
+		StoreUpload:   true,
+		StoreName:     "my-snap",
+		StoreChannels: []string{"edge", "beta"},
+	}
+	snap, err := root.CreateSnap(stub)
+	c.Assert(err, IsNil)
+	c.Assert(snap.Name(), Equals, "mysnap")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/+snaps")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"new"})
+	c.Assert(req.Form["owner"], DeepEquals, []string{"http://joe"})
+	c.Assert(req.Form["name"], DeepEquals, []string{"mysnap"})
+	c.Assert(req.Form["git_ref"], DeepEquals, []string{"http://ref"})
+	c.Assert(req.Form["processors"], DeepEquals, []string{`["` + testServer.URL + `/+processors/amd64","` + testServer.URL + `/+processors/arm64"]`})
+	c.Assert(req.Form["auto_build"], DeepEquals, []string{"false"})
+	c.Assert(req.Form["store_upload"], DeepEquals, []string{"true"})
+	c.Assert(req.Form["store_name"], DeepEquals, []string{"my-snap"})
+	c.Assert(req.Form["store_channels"], DeepEquals, []string{`["edge","beta"]`})
+
+	_, ok := req.Form["description"]
+	c.Assert(ok, Equals, false)
+}
+
+func (s *ModelS) TestSnapSettings(c *C) {
+	m := M{
+		"auto_build":     true,
+		"store_upload":   true,
+		"store_name":     "my-snap",
+		"store_channels": []interface{}{"edge"},
+		"git_ref_link":   testServer.URL + "/ref_link",
+	}
+	snap := &lpad.Snap{lpad.NewValue(nil, "", "", m)}
+	c.Assert(snap.AutoBuild(), Equals, true)
+	c.Assert(snap.StoreUpload(), Equals, true)
+	c.Assert(snap.StoreName(), Equals, "my-snap")
+	c.Assert(snap.StoreChannels(), DeepEquals, []string{"edge"})
+	snap.SetAutoBuild(false)
+	snap.SetStoreUpload(false)
+	snap.SetStoreName("other-snap")
+	snap.SetStoreChannels([]string{"stable"})
+	c.Assert(snap.AutoBuild(), Equals, false)
+	c.Assert(snap.StoreUpload(), Equals, false)
+	c.Assert(snap.StoreName(), Equals, "other-snap")
+	c.Assert(snap.StoreChannels(), DeepEquals, []string{"stable"})
+
+	testServer.PrepareResponse(200, jsonType, `{"path": "refs/heads/master"}`)
+	ref, err := snap.GitRef()
+	c.Assert(err, IsNil)
+	c.Assert(ref.Path(), Equals, "refs/heads/master")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ref_link")
+}
+
+func (s *ModelS) TestSnapRequestBuilds(c *C) {
+	snap := &lpad.Snap{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe/+snap/mysnap", nil)}
+	archive := &lpad.Archive{lpad.NewValue(nil, "", "http://archive", nil)}
+
+	testServer.PrepareResponse(200, jsonType, `{"status": "Pending"}`)
+	breq, err := snap.RequestBuilds(archive, lpad.PocketUpdates, map[string]string{"core": "stable"})
+	c.Assert(err, IsNil)
+	c.Assert(breq.Status(), Equals, lpad.BuildRequestPending)
+	c.Assert(breq.Done(), Equals, false)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~joe/+snap/mysnap")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"requestBuilds"})
+	c.Assert(req.Form["archive"], DeepEquals, []string{"http://archive"})
+	c.Assert(req.Form["pocket"], DeepEquals, []string{"Updates"})
+	c.Assert(req.Form["channels"], DeepEquals, []string{`{"core":"stable"}`})
+
+	m := M{"status": "Pending"}
+	breq = &lpad.SnapBuildRequest{lpad.BuildRequest{lpad.NewValue(nil, testServer.URL, testServer.URL+"/breq", m)}}
+	testServer.PrepareResponse(200, jsonType, `{"status": "Pending"}`)
+	testServer.PrepareResponse(200, jsonType, `{"status": "Completed", "builds_collection_link": "`+testServer.URL+`/builds"}`)
+	err = breq.Wait(time.Millisecond, nil)
+	c.Assert(err, IsNil)
+	c.Assert(breq.Done(), Equals, true)
+	c.Assert(breq.Status(), Equals, lpad.BuildRequestCompleted)
+	testServer.WaitRequest()
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/breq")
+
+	data := `{"total_size": 1, "start": 0, "entries": [{"title": "Build", "arch_tag": "amd64"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := breq.Builds()
+	c.Assert(err, IsNil)
+	archs := []string{}
+	list.For(func(b *lpad.SnapBuild) error {
+		archs = append(archs, b.Arch())
+		return nil
+	})
+	c.Assert(archs, DeepEquals, []string{"amd64"})
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/builds")
+}
+
+func (s *ModelS) TestSnapBuild(c *C) {
+	m := M{
+		"title":               "Title",
+		"arch_tag":            "amd64",
+		"web_link":            "http://page",
+		"buildstate":          "Failed to build",
+		"build_log_url":       "http://log",
+		"upload_log_url":      "http://upload_log",
+		"store_upload_status": "Unscheduled",
+		"datecreated":         "2011-10-10T00:00:00",
+		"datebuilt":           "2011-10-10T00:00:10",
+		"snap_link":           testServer.URL + "/snap_link",
+	}
+	build := &lpad.SnapBuild{lpad.BuildFarmJob{lpad.NewValue(nil, testServer.URL, testServer.URL+"/build", m)}}
+	c.Assert(build.Title(), Equals, "Title")
+	c.Assert(build.Arch(), Equals, "amd64")
+	c.Assert(build.WebPage(), Equals, "http://page")
+	c.Assert(build.State(), Equals, lpad.BSFailedToBuild)
+	c.Assert(build.BuildLogURL(), Equals, "http://log")
+	c.Assert(build.UploadLogURL(), Equals, "http://upload_log")
+	c.Assert(build.StoreUploadStatus(), Equals, "Unscheduled")
+	c.Assert(build.Created(), Equals, "2011-10-10T00:00:00")
+	c.Assert(build.Finished(), Equals, "2011-10-10T00:00:10")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "mysnap"}`)
+	snap, err := build.Snap()
+	c.Assert(err, IsNil)
+	c.Assert(snap.Name(), Equals, "mysnap")
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/snap_link")
+
+	testServer.PrepareResponse(200, jsonType, "{}")
+	err = build.Retry()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/build")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"retry"})
+}
+
+func (s *ModelS) TestSnapBuilds(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"title": "Build"}]}`
+	m := M{
+		"builds_collection_link":         testServer.URL + "/builds",
+		"pending_builds_collection_link": testServer.URL + "/pending",
+	}
+	snap := &lpad.Snap{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := snap.Builds()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 1)
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/builds")
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err = snap.PendingBuilds()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 1)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/pending")
+}
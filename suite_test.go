@@ -12,6 +12,9 @@ import (
 	"time"
 
 	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+	"github.com/canonical/lpad/lpadtest"
 )
 
 func Test(t *testing.T) {
@@ -19,13 +22,50 @@ func Test(t *testing.T) {
 }
 
 var integration = flag.Bool("i", false, "Enable integration tests")
+var cassette = flag.String("cassette", "", "Record integration tests into file with -i, or replay them from it")
+
+// defaultCassette holds the recorded integration tests, which are
+// replayed when neither -i nor -cassette is provided.
+const defaultCassette = "testdata/integration.json"
 
 type SuiteI struct{}
 
 func (s *SuiteI) SetUpSuite(c *C) {
-	if !*integration {
-		c.Skip("Integration tests not enabled (-i flag)")
+	if !*integration && *cassette == "" {
+		if _, err := os.Stat(defaultCassette); err != nil {
+			c.Skip("Integration tests not enabled (-i or -cassette flags)")
+		}
+		*cassette = defaultCassette
+	}
+}
+
+func (s *SuiteI) TearDownSuite(c *C) {
+	if recorder != nil {
+		c.Assert(recorder.Save(), IsNil)
+	}
+}
+
+var recorder *lpadtest.Recorder
+
+// loginI logs into Launchpad for an integration test. With -cassette,
+// the interactions are recorded into the cassette file when -i is
+// provided, and replayed from it otherwise.
+func loginI(c *C, auth lpad.Auth) *lpad.Root {
+	root, err := lpad.Login(lpad.Production, auth)
+	c.Assert(err, IsNil)
+	if *cassette == "" {
+		return root
+	}
+	if recorder == nil {
+		mode := lpadtest.Replay
+		if *integration {
+			mode = lpadtest.Record
+		}
+		recorder, err = lpadtest.NewRecorder(*cassette, mode)
+		c.Assert(err, IsNil)
 	}
+	root.Session().SetTransport(recorder)
+	return root
 }
 
 type HTTPSuite struct{}
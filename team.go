@@ -0,0 +1,216 @@
+package lpad
+
+import (
+	"errors"
+	"net/url"
+)
+
+// A MembershipStatus holds the state of a member in a team.
+type MembershipStatus string
+
+const (
+	MembershipProposed           MembershipStatus = "Proposed"
+	MembershipApproved           MembershipStatus = "Approved"
+	MembershipAdministrator      MembershipStatus = "Administrator"
+	MembershipDeactivated        MembershipStatus = "Deactivated"
+	MembershipExpired            MembershipStatus = "Expired"
+	MembershipDeclined           MembershipStatus = "Declined"
+	MembershipInvited            MembershipStatus = "Invited"
+	MembershipInvitationDeclined MembershipStatus = "Invitation declined"
+)
+
+var membersLinks = map[MembershipStatus]string{
+	MembershipProposed:      "proposed_members_collection_link",
+	MembershipApproved:      "members_collection_link",
+	MembershipAdministrator: "admins_collection_link",
+	MembershipDeactivated:   "deactivated_members_collection_link",
+	MembershipExpired:       "expired_members_collection_link",
+	MembershipInvited:       "invited_members_collection_link",
+}
+
+// Members returns the list of direct members of the team with the given
+// membership status.  Listing members with MembershipApproved returns all
+// the active members, including administrators.  Declined proposals and
+// invitations can't be listed.
+func (team *Team) Members(status MembershipStatus) (*MemberList, error) {
+	link, ok := membersLinks[status]
+	if !ok {
+		return nil, errors.New("can't list members with status " + string(status))
+	}
+	v, err := team.Link(link).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MemberList{v}, nil
+}
+
+// Memberships returns the details for the memberships of all the
+// active members of the team.
+func (team *Team) Memberships() (*TeamMembershipList, error) {
+	v, err := team.Link("members_details_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamMembershipList{v}, nil
+}
+
+// Membership returns the details for the membership of member in the team.
+func (team *Team) Membership(member Member) (*TeamMembership, error) {
+	v, err := team.Location("+member/" + url.QueryEscape(member.Name())).Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamMembership{v}, nil
+}
+
+// AddMember adds member to the team with the given membership status.
+// Teams are not added directly but rather invited, and must accept
+// the invitation before becoming members.
+func (team *Team) AddMember(member Member, status MembershipStatus, comment string) error {
+	params := Params{
+		"ws.op":  "addMember",
+		"person": member.AbsLoc(),
+		"status": string(status),
+	}
+	if comment != "" {
+		params["comment"] = comment
+	}
+	_, err := team.Post(params)
+	return err
+}
+
+// SetMembershipStatus changes the membership status of member in the team.
+func (team *Team) SetMembershipStatus(member Member, status MembershipStatus, comment string) error {
+	tm, err := team.Membership(member)
+	if err != nil {
+		return err
+	}
+	return tm.SetStatus(status, comment)
+}
+
+// Owner returns the Person or Team that owns the team.
+func (team *Team) Owner() (Member, error) {
+	v, err := team.Link("team_owner_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// SubTeams returns the list of teams that are members of the team.
+func (team *Team) SubTeams() (*TeamList, error) {
+	v, err := team.Link("sub_teams_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamList{v}, nil
+}
+
+// SuperTeams returns the list of teams the team is a member of.
+func (team *Team) SuperTeams() (*TeamList, error) {
+	v, err := team.Link("super_teams_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamList{v}, nil
+}
+
+// Teams returns the list of teams the person is a direct member of.
+func (person *Person) Teams() (*TeamList, error) {
+	v, err := person.Link("super_teams_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamList{v}, nil
+}
+
+// Memberships returns the details for all the active team memberships
+// of the person.
+func (person *Person) Memberships() (*TeamMembershipList, error) {
+	v, err := person.Link("memberships_details_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &TeamMembershipList{v}, nil
+}
+
+// The TeamMembership type holds the details of the membership of
+// a person or team in a team.
+type TeamMembership struct {
+	*Value
+}
+
+// Status returns the membership status.
+func (tm *TeamMembership) Status() MembershipStatus {
+	return MembershipStatus(tm.StringField("status"))
+}
+
+// DateJoined returns the timestamp when the member joined the team.
+func (tm *TeamMembership) DateJoined() string {
+	return tm.StringField("date_joined")
+}
+
+// DateExpires returns the timestamp when the membership expires,
+// or the empty string if it doesn't expire.
+func (tm *TeamMembership) DateExpires() string {
+	return tm.StringField("date_expires")
+}
+
+// LastChangeComment returns the comment provided when the membership
+// was last changed.
+func (tm *TeamMembership) LastChangeComment() string {
+	return tm.StringField("last_change_comment")
+}
+
+// Member returns the Person or Team that is a member of the team.
+func (tm *TeamMembership) Member() (Member, error) {
+	v, err := tm.Link("member_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return personOrTeam(v), nil
+}
+
+// Team returns the team the membership is for.
+func (tm *TeamMembership) Team() (*Team, error) {
+	v, err := tm.Link("team_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Team{v}, nil
+}
+
+// SetStatus changes the membership status, recording comment as the
+// reason for the change.
+func (tm *TeamMembership) SetStatus(status MembershipStatus, comment string) error {
+	params := Params{
+		"ws.op":  "setStatus",
+		"status": string(status),
+	}
+	if comment != "" {
+		params["comment"] = comment
+	}
+	_, err := tm.Post(params)
+	return err
+}
+
+// SetExpirationDate changes the timestamp when the membership expires.
+func (tm *TeamMembership) SetExpirationDate(date string) error {
+	_, err := tm.Post(Params{"ws.op": "setExpirationDate", "date": date})
+	return err
+}
+
+// The TeamMembershipList type encapsulates a list of TeamMembership
+// elements for iteration.
+type TeamMembershipList struct {
+	*Value
+}
+
+// For iterates over the list of memberships and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will be
+// returned as the result of For.
+func (list *TeamMembershipList) For(f func(tm *TeamMembership) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&TeamMembership{v})
+	})
+}
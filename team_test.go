@@ -0,0 +1,251 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestTeamMembers(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"name": "joe",
+			"is_team": false
+		}, {
+			"self_link": "http://self1",
+			"name": "ensemble",
+			"is_team": true
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"invited_members_collection_link": testServer.URL + "/invited_link"}
+	team := &lpad.Team{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := team.Members(lpad.MembershipInvited)
+	c.Assert(err, IsNil)
+
+	names := []string{}
+	list.For(func(m lpad.Member) error {
+		names = append(names, m.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"joe", "ensemble"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/invited_link")
+
+	_, err = team.Members(lpad.MembershipDeclined)
+	c.Assert(err, ErrorMatches, "can't list members with status Declined")
+}
+
+func (s *ModelS) TestTeamMemberships(c *C) {
+	data := `{
+		"total_size": 1,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"status": "Administrator",
+			"date_joined": "2011-10-10T00:00:00",
+			"date_expires": "2012-10-10T00:00:00",
+			"last_change_comment": "Welcome"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"members_details_collection_link": testServer.URL + "/details_link"}
+	team := &lpad.Team{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := team.Memberships()
+	c.Assert(err, IsNil)
+
+	var tms []*lpad.TeamMembership
+	list.For(func(tm *lpad.TeamMembership) error {
+		tms = append(tms, tm)
+		return nil
+	})
+	c.Assert(tms, HasLen, 1)
+	c.Assert(tms[0].Status(), Equals, lpad.MembershipAdministrator)
+	c.Assert(tms[0].DateJoined(), Equals, "2011-10-10T00:00:00")
+	c.Assert(tms[0].DateExpires(), Equals, "2012-10-10T00:00:00")
+	c.Assert(tms[0].LastChangeComment(), Equals, "Welcome")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/details_link")
+}
+
+func (s *ModelS) TestTeamMembershipLinks(c *C) {
+	m := M{
+		"member_link": testServer.URL + "/member_link",
+		"team_link":   testServer.URL + "/team_link",
+	}
+	tm := &lpad.TeamMembership{lpad.NewValue(nil, "", "", m)}
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	member, err := tm.Member()
+	c.Assert(err, IsNil)
+	c.Assert(member.Name(), Equals, "joe")
+	_, ok := member.(*lpad.Person)
+	c.Assert(ok, Equals, true)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/member_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "ensemble", "is_team": true}`)
+	team, err := tm.Team()
+	c.Assert(err, IsNil)
+	c.Assert(team.Name(), Equals, "ensemble")
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/team_link")
+}
+
+func (s *ModelS) TestTeamMembershipSetStatus(c *C) {
+	testServer.PrepareResponse(200, jsonType, "{}")
+	tm := &lpad.TeamMembership{lpad.NewValue(nil, testServer.URL, testServer.URL+"/membership", nil)}
+	err := tm.SetStatus(lpad.MembershipDeactivated, "Left the project")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/membership")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"setStatus"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"Deactivated"})
+	c.Assert(req.Form["comment"], DeepEquals, []string{"Left the project"})
+}
+
+func (s *ModelS) TestTeamMembershipSetExpirationDate(c *C) {
+	testServer.PrepareResponse(200, jsonType, "{}")
+	tm := &lpad.TeamMembership{lpad.NewValue(nil, testServer.URL, testServer.URL+"/membership", nil)}
+	err := tm.SetExpirationDate("2012-10-10T00:00:00")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/membership")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"setExpirationDate"})
+	c.Assert(req.Form["date"], DeepEquals, []string{"2012-10-10T00:00:00"})
+}
+
+func (s *ModelS) TestTeamAddMember(c *C) {
+	testServer.PrepareResponse(200, jsonType, "{}")
+	team := &lpad.Team{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~ensemble", nil)}
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", nil)}
+	err := team.AddMember(person, lpad.MembershipApproved, "Welcome")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~ensemble")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"addMember"})
+	c.Assert(req.Form["person"], DeepEquals, []string{testServer.URL + "/~joe"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"Approved"})
+	c.Assert(req.Form["comment"], DeepEquals, []string{"Welcome"})
+}
+
+func (s *ModelS) TestTeamSetMembershipStatus(c *C) {
+	membership := `{"self_link": "` + testServer.URL + `/~ensemble/+member/joe", "status": "Approved"}`
+	testServer.PrepareResponse(200, jsonType, membership)
+	testServer.PrepareResponse(200, jsonType, "{}")
+	team := &lpad.Team{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~ensemble", nil)}
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, testServer.URL+"/~joe", M{"name": "joe"})}
+	err := team.SetMembershipStatus(person, lpad.MembershipAdministrator, "Promoted")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/~ensemble/+member/joe")
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/~ensemble/+member/joe")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"setStatus"})
+	c.Assert(req.Form["status"], DeepEquals, []string{"Administrator"})
+	c.Assert(req.Form["comment"], DeepEquals, []string{"Promoted"})
+}
+
+func (s *ModelS) TestTeamOwner(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"name": "owners", "is_team": true}`)
+	m := M{"team_owner_link": testServer.URL + "/owner_link"}
+	team := &lpad.Team{lpad.NewValue(nil, testServer.URL, "", m)}
+	owner, err := team.Owner()
+	c.Assert(err, IsNil)
+	c.Assert(owner.Name(), Equals, "owners")
+	_, ok := owner.(*lpad.Team)
+	c.Assert(ok, Equals, true)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/owner_link")
+}
+
+func (s *ModelS) TestTeamSubAndSuperTeams(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"name": "other", "is_team": true}]}`
+	m := M{
+		"sub_teams_collection_link":   testServer.URL + "/sub_link",
+		"super_teams_collection_link": testServer.URL + "/super_link",
+	}
+	team := &lpad.Team{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := team.SubTeams()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 1)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/sub_link")
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err = team.SuperTeams()
+	c.Assert(err, IsNil)
+	names := []string{}
+	list.For(func(t *lpad.Team) error {
+		names = append(names, t.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"other"})
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/super_link")
+}
+
+func (s *ModelS) TestPersonTeamsAndMemberships(c *C) {
+	m := M{
+		"super_teams_collection_link":         testServer.URL + "/super_link",
+		"memberships_details_collection_link": testServer.URL + "/details_link",
+	}
+	person := &lpad.Person{lpad.NewValue(nil, testServer.URL, "", m)}
+
+	testServer.PrepareResponse(200, jsonType, `{"total_size": 1, "start": 0, "entries": [{"name": "ensemble"}]}`)
+	teams, err := person.Teams()
+	c.Assert(err, IsNil)
+	names := []string{}
+	teams.For(func(t *lpad.Team) error {
+		names = append(names, t.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"ensemble"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/super_link")
+
+	testServer.PrepareResponse(200, jsonType, `{"total_size": 1, "start": 0, "entries": [{"status": "Approved"}]}`)
+	memberships, err := person.Memberships()
+	c.Assert(err, IsNil)
+	statuses := []lpad.MembershipStatus{}
+	memberships.For(func(tm *lpad.TeamMembership) error {
+		statuses = append(statuses, tm.Status())
+		return nil
+	})
+	c.Assert(statuses, DeepEquals, []lpad.MembershipStatus{lpad.MembershipApproved})
+
+	req = testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/details_link")
+}
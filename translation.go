@@ -0,0 +1,257 @@
+package lpad
+
+import "path"
+
+func translationTemplates(v *Value) (*POTemplateList, error) {
+	v, err := v.Location("").Get(Params{"ws.op": "getTranslationTemplates"})
+	if err != nil {
+		return nil, err
+	}
+	return &POTemplateList{v}, nil
+}
+
+// translationImports lists the import queue entries targeting v. Files
+// can't be added to the queue through the API, only through uploads in
+// the web pages or imports from the translations branch of the series.
+func translationImports(v *Value, status ImportStatus) (*TranslationImportEntryList, error) {
+	params := Params{
+		"ws.op":  "getAllEntries",
+		"target": v.AbsLoc(),
+	}
+	if status != ImportAny {
+		params["import_status"] = string(status)
+	}
+	v, err := v.Location("/+imports").Get(params)
+	if err != nil {
+		return nil, err
+	}
+	return &TranslationImportEntryList{v}, nil
+}
+
+// TranslationTemplates returns the list of translation templates
+// associated with the project series.
+func (s *ProjectSeries) TranslationTemplates() (*POTemplateList, error) {
+	return translationTemplates(s.Value)
+}
+
+// TranslationImports returns the entries in the translation import
+// queue for the project series with the given status, or with any
+// status if status is ImportAny.
+func (s *ProjectSeries) TranslationImports(status ImportStatus) (*TranslationImportEntryList, error) {
+	return translationImports(s.Value, status)
+}
+
+// TranslationTemplates returns the list of translation templates
+// associated with the distribution series.
+func (s *DistroSeries) TranslationTemplates() (*POTemplateList, error) {
+	return translationTemplates(s.Value)
+}
+
+// TranslationImports returns the entries in the translation import
+// queue for the distribution series with the given status, or with
+// any status if status is ImportAny.
+func (s *DistroSeries) TranslationImports(status ImportStatus) (*TranslationImportEntryList, error) {
+	return translationImports(s.Value, status)
+}
+
+// The POTemplate type represents a translation template.
+type POTemplate struct {
+	*Value
+}
+
+// Name returns the template name.
+func (t *POTemplate) Name() string {
+	return t.StringField("name")
+}
+
+// TranslationDomain returns the gettext translation domain of the template.
+func (t *POTemplate) TranslationDomain() string {
+	return t.StringField("translation_domain")
+}
+
+// Description returns the template description.
+func (t *POTemplate) Description() string {
+	return t.StringField("description")
+}
+
+// Path returns the location of the template in the source tree.
+func (t *POTemplate) Path() string {
+	return t.StringField("path")
+}
+
+// Active returns true if the template is currently being translated.
+func (t *POTemplate) Active() bool {
+	return t.BoolField("iscurrent")
+}
+
+// Priority returns the priority of the template relative to other
+// templates in the same series.
+func (t *POTemplate) Priority() int {
+	return t.IntField("priority")
+}
+
+// MessageCount returns the number of translatable messages in the template.
+func (t *POTemplate) MessageCount() int {
+	return t.IntField("message_count")
+}
+
+// DateUpdated returns the time the template was last updated.
+func (t *POTemplate) DateUpdated() string {
+	return t.StringField("date_last_updated")
+}
+
+// WebPage returns the URL for accessing this template in a browser.
+func (t *POTemplate) WebPage() string {
+	return t.StringField("web_link")
+}
+
+// TranslationFiles returns the list of translations of the template.
+func (t *POTemplate) TranslationFiles() (*POFileList, error) {
+	v, err := t.Link("translation_files_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &POFileList{v}, nil
+}
+
+// The POTemplateList type represents a list of POTemplate objects.
+type POTemplateList struct {
+	*Value
+}
+
+// For iterates over the list of templates and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *POTemplateList) For(f func(t *POTemplate) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&POTemplate{v})
+	})
+}
+
+// The POFile type represents the translation of a template into
+// a single language.
+type POFile struct {
+	*Value
+}
+
+// Title returns the translation context title for pages.
+func (f *POFile) Title() string {
+	return f.StringField("title")
+}
+
+// Path returns the location of the translation file in the source tree.
+func (f *POFile) Path() string {
+	return f.StringField("path")
+}
+
+// Language returns the code of the language the file translates
+// the template into, such as "pt_BR".
+func (f *POFile) Language() string {
+	if link := f.StringField("language_link"); link != "" {
+		return path.Base(link)
+	}
+	return ""
+}
+
+// WebPage returns the URL for accessing this translation in a browser.
+func (f *POFile) WebPage() string {
+	return f.StringField("web_link")
+}
+
+// Template returns the template the file is a translation of.
+func (f *POFile) Template() (*POTemplate, error) {
+	v, err := f.Link("potemplate_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &POTemplate{v}, nil
+}
+
+// The POFileList type represents a list of POFile objects.
+type POFileList struct {
+	*Value
+}
+
+// For iterates over the list of translation files and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *POFileList) For(f func(pf *POFile) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&POFile{v})
+	})
+}
+
+// An ImportStatus holds the state of an entry in the translation
+// import queue.
+type ImportStatus string
+
+const (
+	ImportAny         ImportStatus = ""
+	ImportNeedsReview ImportStatus = "Needs Review"
+	ImportApproved    ImportStatus = "Approved"
+	ImportImported    ImportStatus = "Imported"
+	ImportFailed      ImportStatus = "Failed"
+	ImportDeleted     ImportStatus = "Deleted"
+	ImportBlocked     ImportStatus = "Blocked"
+	ImportNeedsInfo   ImportStatus = "Needs Information"
+)
+
+// The TranslationImportEntry type represents a file in the
+// translation import queue.
+type TranslationImportEntry struct {
+	*Value
+}
+
+// Path returns the location of the uploaded file in the source tree.
+func (e *TranslationImportEntry) Path() string {
+	return e.StringField("path")
+}
+
+// Status returns the current status of the entry.
+func (e *TranslationImportEntry) Status() ImportStatus {
+	return ImportStatus(e.StringField("status"))
+}
+
+// Format returns the file format of the uploaded file.
+func (e *TranslationImportEntry) Format() string {
+	return e.StringField("format")
+}
+
+// DateCreated returns the time the file was uploaded.
+func (e *TranslationImportEntry) DateCreated() string {
+	return e.StringField("date_created")
+}
+
+// DateStatusChanged returns the time the status of the entry last changed.
+func (e *TranslationImportEntry) DateStatusChanged() string {
+	return e.StringField("date_status_changed")
+}
+
+// ErrorOutput returns the details of the problem in a failed import.
+func (e *TranslationImportEntry) ErrorOutput() string {
+	return e.StringField("error_output")
+}
+
+// Importer returns the person who uploaded the file.
+func (e *TranslationImportEntry) Importer() (*Person, error) {
+	v, err := e.Link("importer_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// The TranslationImportEntryList type represents a list of
+// TranslationImportEntry objects.
+type TranslationImportEntryList struct {
+	*Value
+}
+
+// For iterates over the list of import queue entries and calls f for
+// each one. If f returns a non-nil error, iteration will stop and the
+// error will be returned as the result of For.
+func (list *TranslationImportEntryList) For(f func(e *TranslationImportEntry) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&TranslationImportEntry{v})
+	})
+}
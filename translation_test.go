@@ -0,0 +1,163 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestTranslationTemplates(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"name": "Name0"
+		}, {
+			"self_link": "http://self1",
+			"name": "Name1"
+		}]
+	}`
+	pseries := &lpad.ProjectSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/proj/trunk", nil)}
+	dseries := &lpad.DistroSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/ubuntu/oneiric", nil)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := pseries.TranslationTemplates()
+	c.Assert(err, IsNil)
+	c.Assert(list.TotalSize(), Equals, 2)
+	names := []string{}
+	list.For(func(t *lpad.POTemplate) error {
+		names = append(names, t.Name())
+		return nil
+	})
+	c.Assert(names, DeepEquals, []string{"Name0", "Name1"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/proj/trunk")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getTranslationTemplates"})
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = dseries.TranslationTemplates()
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/ubuntu/oneiric")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getTranslationTemplates"})
+}
+
+func (s *ModelS) TestPOTemplate(c *C) {
+	m := M{
+		"name":               "thename",
+		"translation_domain": "domain",
+		"description":        "Description",
+		"path":               "po/thename.pot",
+		"iscurrent":          true,
+		"priority":           10.0,
+		"message_count":      42.0,
+		"date_last_updated":  "2011-08-31",
+		"web_link":           "http://page",
+	}
+	t := &lpad.POTemplate{lpad.NewValue(nil, "", "", m)}
+	c.Assert(t.Name(), Equals, "thename")
+	c.Assert(t.TranslationDomain(), Equals, "domain")
+	c.Assert(t.Description(), Equals, "Description")
+	c.Assert(t.Path(), Equals, "po/thename.pot")
+	c.Assert(t.Active(), Equals, true)
+	c.Assert(t.Priority(), Equals, 10)
+	c.Assert(t.MessageCount(), Equals, 42)
+	c.Assert(t.DateUpdated(), Equals, "2011-08-31")
+	c.Assert(t.WebPage(), Equals, "http://page")
+}
+
+func (s *ModelS) TestPOTemplateTranslationFiles(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"title": "German",
+			"path": "po/de.po",
+			"language_link": "http://api/+languages/de",
+			"web_link": "http://page0"
+		}, {
+			"self_link": "http://self1",
+			"title": "Brazilian Portuguese",
+			"path": "po/pt_BR.po",
+			"language_link": "http://api/+languages/pt_BR"
+		}]
+	}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"translation_files_collection_link": testServer.URL + "/col_link"}
+	t := &lpad.POTemplate{lpad.NewValue(nil, testServer.URL, "", m)}
+	list, err := t.TranslationFiles()
+	c.Assert(err, IsNil)
+
+	var files []*lpad.POFile
+	list.For(func(f *lpad.POFile) error {
+		files = append(files, f)
+		return nil
+	})
+	c.Assert(files, HasLen, 2)
+	c.Assert(files[0].Title(), Equals, "German")
+	c.Assert(files[0].Path(), Equals, "po/de.po")
+	c.Assert(files[0].Language(), Equals, "de")
+	c.Assert(files[0].WebPage(), Equals, "http://page0")
+	c.Assert(files[1].Language(), Equals, "pt_BR")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/col_link")
+}
+
+func (s *ModelS) TestTranslationImports(c *C) {
+	data := `{
+		"total_size": 2,
+		"start": 0,
+		"entries": [{
+			"self_link": "http://self0",
+			"path": "po/de.po",
+			"status": "Imported",
+			"format": "PO format",
+			"date_created": "2011-08-30",
+			"date_status_changed": "2011-08-31"
+		}, {
+			"self_link": "http://self1",
+			"path": "po/fr.po",
+			"status": "Failed",
+			"error_output": "Syntax error"
+		}]
+	}`
+	series := &lpad.ProjectSeries{lpad.NewValue(nil, testServer.URL, testServer.URL+"/proj/trunk", nil)}
+
+	testServer.PrepareResponse(200, jsonType, data)
+	list, err := series.TranslationImports(lpad.ImportAny)
+	c.Assert(err, IsNil)
+
+	var entries []*lpad.TranslationImportEntry
+	list.For(func(e *lpad.TranslationImportEntry) error {
+		entries = append(entries, e)
+		return nil
+	})
+	c.Assert(entries, HasLen, 2)
+	c.Assert(entries[0].Path(), Equals, "po/de.po")
+	c.Assert(entries[0].Status(), Equals, lpad.ImportImported)
+	c.Assert(entries[0].Format(), Equals, "PO format")
+	c.Assert(entries[0].DateCreated(), Equals, "2011-08-30")
+	c.Assert(entries[0].DateStatusChanged(), Equals, "2011-08-31")
+	c.Assert(entries[1].Status(), Equals, lpad.ImportFailed)
+	c.Assert(entries[1].ErrorOutput(), Equals, "Syntax error")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/+imports")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"getAllEntries"})
+	c.Assert(req.Form["target"], DeepEquals, []string{series.AbsLoc()})
+	_, ok := req.Form["import_status"]
+	c.Assert(ok, Equals, false)
+
+	testServer.PrepareResponse(200, jsonType, data)
+	_, err = series.TranslationImports(lpad.ImportFailed)
+	c.Assert(err, IsNil)
+	req = testServer.WaitRequest()
+	c.Assert(req.Form["import_status"], DeepEquals, []string{"Failed"})
+}
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
+	"mime/multipart"
 	"net/http"
 	"net/http/httputil"
 	"net/url"
@@ -13,6 +15,7 @@ import (
 	"path"
 	"strconv"
 	"strings"
+	"time"
 )
 
 // The Params type is a helper to pass parameter into the Value request
@@ -22,6 +25,19 @@ import (
 //
 type Params map[string]string
 
+// jsonList encodes l in the JSON format that Launchpad expects for
+// list parameters in named operations.
+func jsonList(l []string) string {
+	if l == nil {
+		l = []string{}
+	}
+	data, err := json.Marshal(l)
+	if err != nil {
+		panic(err)
+	}
+	return string(data)
+}
+
 type Error struct {
 	StatusCode int    // HTTP status code (500, 403, ...)
 	Body       []byte // Body of response
@@ -181,7 +197,8 @@ func (v *Value) SetField(key string, value interface{}) {
 	case bool:
 		newv = v
 	case []string:
-		var l []interface{}
+		// Launchpad expects an empty list rather than null.
+		l := []interface{}{}
 		for _, item := range v {
 			l = append(l, item)
 		}
@@ -245,14 +262,103 @@ var ErrNotFound = errors.New("resource not found")
 //     v, err := other.Link("some_link").Get(nil)
 //
 func (v *Value) Get(params Params) (same *Value, err error) {
-	return v.do("GET", params, nil)
+	return v.do("GET", params, "", nil)
 }
 
 // Post issues an HTTP POST to perform a given action at the URL
 // specified by this value.  If params is not nil, it will
 // provided as the parameters for the POST request.
 func (v *Value) Post(params Params) (other *Value, err error) {
-	return v.do("POST", params, nil)
+	return v.do("POST", params, "", nil)
+}
+
+// A formFile holds the content of a file to be sent as a field
+// of a multipart POST request.
+type formFile struct {
+	field    string
+	filename string
+	content  io.Reader
+}
+
+// postFiles issues an HTTP POST with a multipart/form-data body
+// holding both params and the content of files, as required by
+// Launchpad for operations that take binary data.
+func (v *Value) postFiles(params Params, files []formFile) (other *Value, err error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for key, value := range params {
+		if err := w.WriteField(key, value); err != nil {
+			return nil, err
+		}
+	}
+	for _, f := range files {
+		part, err := w.CreateFormFile(f.field, f.filename)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := io.Copy(part, f.content); err != nil {
+			return nil, err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return v.do("POST", nil, w.FormDataContentType(), buf.Bytes())
+}
+
+// download issues an HTTP GET to retrieve the raw content at the
+// URL specified by this value, rather than a JSON representation.
+func (v *Value) download() (data []byte, err error) {
+	var buf bytes.Buffer
+	if err := v.downloadTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// downloadTo works like download, but copies the content into w as
+// it arrives, so that large files needn't be held in memory.
+func (v *Value) downloadTo(w io.Writer) error {
+	return v.downloadAs(w, "")
+}
+
+// downloadAs works like downloadTo, but requests the content in the
+// given media type via the Accept header, if it's not empty.
+func (v *Value) downloadAs(w io.Writer, accept string) error {
+	if v == nil {
+		return ErrNotFound
+	}
+	req, err := http.NewRequest("GET", v.AbsLoc(), nil)
+	if err != nil {
+		return err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if v.session != nil && v.onAPIHost(req.URL) {
+		if err := v.session.Sign(req); err != nil {
+			return err
+		}
+	}
+	if debugOn {
+		if err := printRequestDump(req); err != nil {
+			return err
+		}
+	}
+	resp, err := v.client().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		if resp.StatusCode == 404 {
+			return ErrNotFound
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		return &Error{resp.StatusCode, body}
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
 }
 
 // Patch issues an HTTP PATCH request to modify the server value
@@ -265,10 +371,32 @@ func (v *Value) Patch() error {
 	if err != nil {
 		return err
 	}
-	_, err = v.do("PATCH", nil, data)
+	_, err = v.do("PATCH", nil, "", data)
 	return err
 }
 
+// Delete issues an HTTP DELETE request to remove the server value.
+func (v *Value) Delete() error {
+	_, err := v.do("DELETE", nil, "", nil)
+	return err
+}
+
+// poll refreshes the value from Launchpad every interval until done
+// returns true or a value is received from stop.
+func (v *Value) poll(interval time.Duration, stop <-chan bool, done func() bool) error {
+	for !done() {
+		select {
+		case <-stop:
+			return nil
+		case <-time.After(interval):
+		}
+		if _, err := v.Get(nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // TotalSize returns the total number of entries in a collection.
 func (v *Value) TotalSize() int {
 	return v.IntField("total_size")
@@ -312,7 +440,7 @@ func (v *Value) For(f func(*Value) error) (err error) {
 	return nil
 }
 
-func (v *Value) do(method string, params Params, body []byte) (value *Value, err error) {
+func (v *Value) do(method string, params Params, ctype string, body []byte) (value *Value, err error) {
 	if v == nil {
 		return nil, ErrNotFound
 	}
@@ -328,7 +456,7 @@ func (v *Value) do(method string, params Params, body []byte) (value *Value, err
 		return nil, err
 	}
 
-	err = v.prepare(req, params, body)
+	err = v.prepare(req, params, ctype, body)
 	if err != nil {
 		return nil, err
 	}
@@ -339,12 +467,11 @@ func (v *Value) do(method string, params Params, body []byte) (value *Value, err
 		}
 	}
 
-	client := http.Client{
-		CheckRedirect: func(req *http.Request, via []*http.Request) error {
-			value.loc = req.URL.String()
-			v.prepare(req, nil, nil)
-			return nil
-		},
+	client := v.client()
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		value.loc = req.URL.String()
+		v.prepare(req, nil, "", nil)
+		return nil
 	}
 
 	resp, err := client.Do(req)
@@ -366,7 +493,7 @@ func (v *Value) do(method string, params Params, body []byte) (value *Value, err
 		if value.loc == "" {
 			return nil, errors.New("Server returned 201 without Location")
 		}
-		return value.do("GET", nil, nil)
+		return value.do("GET", nil, "", nil)
 	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != 209 {
 		if resp.StatusCode == 404 {
@@ -377,9 +504,11 @@ func (v *Value) do(method string, params Params, body []byte) (value *Value, err
 	if method == "PATCH" && resp.StatusCode != 209 {
 		return nil, nil
 	}
-	ctype := resp.Header.Get("Content-Type")
-	if ctype != "application/json" {
-		return nil, errors.New("Non-JSON content-type: " + ctype)
+	if method == "DELETE" {
+		return nil, nil
+	}
+	if rtype := resp.Header.Get("Content-Type"); rtype != "application/json" {
+		return nil, errors.New("Non-JSON content-type: " + rtype)
 	}
 	if method == "GET" && len(body) > 0 && body[0] == 'n' && string(body) == "null" {
 		return nil, ErrNotFound
@@ -396,16 +525,36 @@ func (v *Value) do(method string, params Params, body []byte) (value *Value, err
 	return value, json.Unmarshal(body, &value.m)
 }
 
-func (v *Value) prepare(req *http.Request, params Params, body []byte) error {
+// onAPIHost returns whether u is served by the API the value was
+// obtained from. Requests to other hosts must not be signed, as the
+// PLAINTEXT signature gives away the session credentials.
+func (v *Value) onAPIHost(u *url.URL) bool {
+	base, err := url.Parse(v.baseloc)
+	return err == nil && base.Host != "" && base.Scheme == u.Scheme && base.Host == u.Host
+}
+
+// client returns an HTTP client that delivers requests with the
+// transport of the value's session.
+func (v *Value) client() *http.Client {
+	client := &http.Client{}
+	if v.session != nil {
+		client.Transport = v.session.transport
+	}
+	return client
+}
+
+func (v *Value) prepare(req *http.Request, params Params, ctype string, body []byte) error {
 	req.Header["Accept"] = []string{"application/json"}
 
 	query := multimap(params).Encode()
-	ctype := "application/json"
-	if req.Method == "POST" {
+	if ctype == "" && req.Method == "POST" {
 		body = []byte(query)
 		query = ""
 		ctype = "application/x-www-form-urlencoded"
-	} else if req.URL.RawQuery == "" {
+	} else if ctype == "" {
+		ctype = "application/json"
+	}
+	if req.URL.RawQuery == "" {
 		req.URL.RawQuery = query
 	} else if query != "" {
 		req.URL.RawQuery += "&" + query
@@ -371,6 +371,23 @@ func (s *ValueS) TestPatch(c *C) {
 	c.Assert(m, DeepEquals, M{"a": 3.0, "c": "string", "d": true, "e": []interface{}{"a", "b"}})
 }
 
+func (s *ValueS) TestDelete(c *C) {
+	testServer.PrepareResponse(200, nil, "")
+
+	v := lpad.NewValue(nil, "", testServer.URL+"/myvalue", nil)
+	err := v.Delete()
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "DELETE")
+	c.Assert(req.URL.Path, Equals, "/myvalue")
+
+	testServer.PrepareResponse(404, nil, "")
+	err = v.Delete()
+	c.Assert(err, Equals, lpad.ErrNotFound)
+	testServer.WaitRequest()
+}
+
 func (s *ValueS) TestPatchWithContent(c *C) {
 	testServer.PrepareResponse(200, jsonType, `{"a": 1, "b": 2}`)
 	testServer.PrepareResponse(209, jsonType, `{"new": "content"}`)
@@ -0,0 +1,376 @@
+package lpad
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+)
+
+// WADLType is the media type of the service description Launchpad
+// publishes at the root of each API version.
+const WADLType = "application/vnd.sun.wadl+xml"
+
+// The ServiceDescription type describes all the resource types
+// available in a version of the Launchpad API, as published in its
+// WADL document.
+type ServiceDescription struct {
+	Base          string                   // The API root, such as Production
+	ResourceTypes map[string]*ResourceType // Indexed by name, such as "project"
+}
+
+// The ResourceType type describes the fields, links and named operations
+// available in values of a given type, as returned by Value.Describe.
+type ResourceType struct {
+	Name       string // Such as "project" or "bug_task-page-resource"
+	Doc        string
+	Fields     []*Field
+	Operations []*Operation
+}
+
+// Field returns the field with the given name, or nil if there's none.
+func (rt *ResourceType) Field(name string) *Field {
+	for _, f := range rt.Fields {
+		if f.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// Operation returns the named operation with the given name, or nil
+// if there's none.
+func (rt *ResourceType) Operation(name string) *Operation {
+	for _, op := range rt.Operations {
+		if op.Name == name {
+			return op
+		}
+	}
+	return nil
+}
+
+// The Field type describes a field in the representation of a resource.
+//
+// Type is one of "string", "boolean", "int", "float", "date", "dateTime",
+// "binary" or "link". For links, LinkType holds the name of the resource
+// type linked to, if known. Options holds the accepted values for
+// enumerated fields, and Writable is true if the field may be changed
+// with SetField and Patch.
+type Field struct {
+	Name     string
+	Doc      string
+	Type     string
+	LinkType string
+	Options  []string
+	Writable bool
+}
+
+// The Operation type describes a named operation, invoked by passing
+// its name in the ws.op parameter of a GET or POST request. Returns
+// holds the name of the resource type returned, if known.
+type Operation struct {
+	Name    string
+	Doc     string
+	Method  string // "GET" or "POST"
+	Params  []*Param
+	Returns string
+}
+
+// Param returns the parameter with the given name, or nil if there's none.
+func (op *Operation) Param(name string) *Param {
+	for _, p := range op.Params {
+		if p.Name == name {
+			return p
+		}
+	}
+	return nil
+}
+
+// The Param type describes a parameter of a named operation. Type,
+// LinkType and Options have the same meaning as in the Field type.
+type Param struct {
+	Name     string
+	Doc      string
+	Type     string
+	LinkType string
+	Options  []string
+	Required bool
+}
+
+type wadlApplication struct {
+	Resources       []wadlResources      `xml:"resources"`
+	ResourceTypes   []wadlResourceType   `xml:"resource_type"`
+	Representations []wadlRepresentation `xml:"representation"`
+}
+
+type wadlResources struct {
+	Base string `xml:"base,attr"`
+}
+
+type wadlDoc struct {
+	Inner string `xml:",innerxml"`
+}
+
+type wadlResourceType struct {
+	Id      string       `xml:"id,attr"`
+	Doc     wadlDoc      `xml:"doc"`
+	Methods []wadlMethod `xml:"method"`
+}
+
+type wadlMethod struct {
+	Id       string      `xml:"id,attr"`
+	Name     string      `xml:"name,attr"`
+	Href     string      `xml:"href,attr"`
+	Doc      wadlDoc     `xml:"doc"`
+	Request  wadlMessage `xml:"request"`
+	Response wadlMessage `xml:"response"`
+}
+
+type wadlMessage struct {
+	Params          []wadlParam          `xml:"param"`
+	Representations []wadlRepresentation `xml:"representation"`
+}
+
+type wadlRepresentation struct {
+	Id     string      `xml:"id,attr"`
+	Href   string      `xml:"href,attr"`
+	Params []wadlParam `xml:"param"`
+}
+
+type wadlParam struct {
+	Name     string       `xml:"name,attr"`
+	Type     string       `xml:"type,attr"`
+	Fixed    string       `xml:"fixed,attr"`
+	Required bool         `xml:"required,attr"`
+	Doc      wadlDoc      `xml:"doc"`
+	Options  []wadlOption `xml:"option"`
+	Link     *wadlLink    `xml:"link"`
+}
+
+type wadlOption struct {
+	Value string `xml:"value,attr"`
+}
+
+type wadlLink struct {
+	ResourceType string `xml:"resource_type,attr"`
+}
+
+// ParseServiceDescription parses the WADL document describing a version
+// of the Launchpad API.
+func ParseServiceDescription(data []byte) (*ServiceDescription, error) {
+	var app wadlApplication
+	if err := xml.Unmarshal(data, &app); err != nil {
+		return nil, fmt.Errorf("cannot parse service description: %v", err)
+	}
+	desc := &ServiceDescription{ResourceTypes: make(map[string]*ResourceType)}
+	if len(app.Resources) > 0 {
+		desc.Base = app.Resources[0].Base
+	}
+	reprs := make(map[string]*wadlRepresentation)
+	for i := range app.Representations {
+		reprs[app.Representations[i].Id] = &app.Representations[i]
+	}
+	methods := make(map[string]*wadlMethod)
+	for i := range app.ResourceTypes {
+		for j := range app.ResourceTypes[i].Methods {
+			m := &app.ResourceTypes[i].Methods[j]
+			if m.Id != "" {
+				methods[m.Id] = m
+			}
+		}
+	}
+	for i := range app.ResourceTypes {
+		wrt := &app.ResourceTypes[i]
+		rt := &ResourceType{Name: wrt.Id, Doc: docText(wrt.Doc)}
+		writable := make(map[string]bool)
+		for j := range wrt.Methods {
+			m := &wrt.Methods[j]
+			if m.Href != "" {
+				if m = methods[fragment(m.Href)]; m == nil {
+					return nil, fmt.Errorf("resource type %q references unknown method %q", wrt.Id, wrt.Methods[j].Href)
+				}
+			}
+			if op := parseOperation(m); op != nil {
+				rt.Operations = append(rt.Operations, op)
+				continue
+			}
+			switch m.Name {
+			case "GET":
+				for _, r := range m.Response.Representations {
+					if repr := reprs[fragment(r.Href)]; repr != nil {
+						for k := range repr.Params {
+							rt.Fields = append(rt.Fields, parseField(&repr.Params[k]))
+						}
+					}
+				}
+			case "PATCH", "PUT":
+				for _, r := range m.Request.Representations {
+					if repr := reprs[fragment(r.Href)]; repr != nil {
+						for _, p := range repr.Params {
+							writable[p.Name] = true
+						}
+					}
+				}
+			}
+		}
+		for _, f := range rt.Fields {
+			f.Writable = writable[f.Name]
+		}
+		desc.ResourceTypes[rt.Name] = rt
+	}
+	return desc, nil
+}
+
+// parseOperation returns the named operation described by m, or nil
+// if m has no fixed ws.op parameter.
+func parseOperation(m *wadlMethod) *Operation {
+	params := m.Request.Params
+	for _, r := range m.Request.Representations {
+		params = append(params, r.Params...)
+	}
+	op := &Operation{Method: m.Name, Doc: docText(m.Doc)}
+	for i := range params {
+		p := &params[i]
+		if p.Name == "ws.op" {
+			op.Name = p.Fixed
+			continue
+		}
+		field := parseField(p)
+		op.Params = append(op.Params, &Param{
+			Name:     field.Name,
+			Doc:      field.Doc,
+			Type:     field.Type,
+			LinkType: field.LinkType,
+			Options:  field.Options,
+			Required: p.Required,
+		})
+	}
+	if op.Name == "" {
+		return nil
+	}
+	for _, r := range m.Response.Representations {
+		if r.Href != "" {
+			op.Returns = reprType(fragment(r.Href))
+		}
+	}
+	for _, p := range m.Response.Params {
+		if p.Link != nil && p.Link.ResourceType != "" {
+			op.Returns = fragment(p.Link.ResourceType)
+		}
+	}
+	return op
+}
+
+func parseField(p *wadlParam) *Field {
+	f := &Field{Name: p.Name, Doc: docText(p.Doc), Type: "string"}
+	if p.Link != nil {
+		f.Type = "link"
+		f.LinkType = fragment(p.Link.ResourceType)
+	} else if i := strings.Index(p.Type, ":"); i >= 0 {
+		f.Type = p.Type[i+1:]
+	} else if p.Type != "" {
+		f.Type = p.Type
+	}
+	for _, o := range p.Options {
+		f.Options = append(f.Options, o.Value)
+	}
+	return f
+}
+
+// reprType returns the name of the resource type for the representation
+// with the given id. Launchpad names the full representation of a
+// resource type "<name>-full", and the representation of a page of
+// entries "<name>-page", which is served by "<name>-page-resource".
+func reprType(id string) string {
+	if strings.HasSuffix(id, "-full") {
+		return strings.TrimSuffix(id, "-full")
+	}
+	if strings.HasSuffix(id, "-page") {
+		return id + "-resource"
+	}
+	return id
+}
+
+func fragment(ref string) string {
+	if i := strings.Index(ref, "#"); i >= 0 {
+		return ref[i+1:]
+	}
+	return ref
+}
+
+// docText returns the text in the XHTML content of a doc element,
+// with all markup removed and whitespace collapsed.
+func docText(doc wadlDoc) string {
+	var text []string
+	dec := xml.NewDecoder(strings.NewReader(doc.Inner))
+	dec.Strict = false
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			break
+		}
+		if data, ok := tok.(xml.CharData); ok {
+			text = append(text, string(data))
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(text, "")), " ")
+}
+
+// ServiceDescription returns the description of the API version the
+// value belongs to. The description is retrieved from Launchpad once
+// per session.
+func (v *Value) ServiceDescription() (*ServiceDescription, error) {
+	return v.serviceDescription(v.BaseLoc())
+}
+
+func (v *Value) serviceDescription(base string) (*ServiceDescription, error) {
+	s := v.session
+	if s != nil {
+		s.mu.Lock()
+		desc, ok := s.descs[base]
+		s.mu.Unlock()
+		if ok {
+			return desc, nil
+		}
+	}
+	// The lock isn't held while downloading, so concurrent callers may
+	// each fetch the description, and the last one retrieved is kept.
+	var buf bytes.Buffer
+	err := v.Location(base).downloadAs(&buf, WADLType)
+	if err != nil {
+		return nil, err
+	}
+	desc, err := ParseServiceDescription(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	if s != nil {
+		s.mu.Lock()
+		if s.descs == nil {
+			s.descs = make(map[string]*ServiceDescription)
+		}
+		s.descs[base] = desc
+		s.mu.Unlock()
+	}
+	return desc, nil
+}
+
+// Describe returns the resource type of the value, as referenced by its
+// resource_type_link field, so that its fields, links and named
+// operations may be inspected at runtime.
+func (v *Value) Describe() (*ResourceType, error) {
+	link := v.StringField("resource_type_link")
+	i := strings.Index(link, "#")
+	if i < 0 {
+		return nil, fmt.Errorf("value has no resource type: %s", v.AbsLoc())
+	}
+	desc, err := v.serviceDescription(link[:i])
+	if err != nil {
+		return nil, err
+	}
+	rt, ok := desc.ResourceTypes[link[i+1:]]
+	if !ok {
+		return nil, fmt.Errorf("service description has no resource type %q", link[i+1:])
+	}
+	return rt, nil
+}
@@ -0,0 +1,198 @@
+package lpad_test
+
+import (
+	"net/http"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+var _ = Suite(&WADLS{})
+
+type WADLS struct {
+	HTTPSuite
+}
+
+var wadlType = map[string]string{
+	"Content-Type": lpad.WADLType,
+}
+
+const testWADL = `<?xml version="1.0"?>
+<application xmlns="http://research.sun.com/wadl/2006/10">
+  <resources base="https://api.launchpad.net/devel/">
+    <resource path="" type="#service-root"/>
+  </resources>
+  <resource_type id="project">
+    <doc xmlns="http://www.w3.org/1999/xhtml"><p>A <em>project</em>.</p></doc>
+    <method id="project-get" name="GET">
+      <response><representation href="#project-full"/></response>
+    </method>
+    <method id="project-patch" name="PATCH">
+      <request><representation href="#project-diff"/></request>
+    </method>
+    <method id="project-searchTasks" name="GET">
+      <doc xmlns="http://www.w3.org/1999/xhtml"><p>Search tasks.</p></doc>
+      <request>
+        <param style="query" name="ws.op" required="true" fixed="searchTasks"/>
+        <param style="query" name="status" required="false">
+          <option value="New"/>
+          <option value="Fix Released"/>
+        </param>
+        <param style="query" name="assignee" required="false">
+          <link resource_type="https://api.launchpad.net/devel/#person"/>
+        </param>
+      </request>
+      <response><representation href="https://api.launchpad.net/devel/#bug_task-page"/></response>
+    </method>
+    <method id="project-newSeries" name="POST">
+      <request>
+        <representation mediaType="application/x-www-form-urlencoded">
+          <param style="query" name="ws.op" required="true" fixed="newSeries"/>
+          <param style="query" name="name" required="true"/>
+          <param style="query" name="summary" required="true"/>
+        </representation>
+      </request>
+    </method>
+  </resource_type>
+  <resource_type id="person">
+    <method href="#person-get"/>
+  </resource_type>
+  <resource_type id="shared">
+    <method id="person-get" name="GET">
+      <response><representation href="#person-full"/></response>
+    </method>
+  </resource_type>
+  <representation id="project-full" mediaType="application/json">
+    <param style="plain" name="self_link" path="$['self_link']"><link/></param>
+    <param style="plain" name="name" path="$['name']">
+      <doc xmlns="http://www.w3.org/1999/xhtml"><p>Name</p>
+      <p>The project   name.</p></doc>
+    </param>
+    <param style="plain" name="date_created" path="$['date_created']" type="xsd:dateTime"/>
+    <param style="plain" name="active" path="$['active']" type="xsd:boolean"/>
+    <param style="plain" name="owner_link" path="$['owner_link']">
+      <link resource_type="https://api.launchpad.net/devel/#person"/>
+    </param>
+  </representation>
+  <representation id="project-diff" mediaType="application/json">
+    <param style="plain" name="name" path="$['name']"/>
+    <param style="plain" name="owner_link" path="$['owner_link']"/>
+  </representation>
+  <representation id="person-full" mediaType="application/json">
+    <param style="plain" name="display_name" path="$['display_name']"/>
+  </representation>
+</application>
+`
+
+func (s *WADLS) TestParseServiceDescription(c *C) {
+	desc, err := lpad.ParseServiceDescription([]byte(testWADL))
+	c.Assert(err, IsNil)
+	c.Assert(desc.Base, Equals, "https://api.launchpad.net/devel/")
+
+	rt := desc.ResourceTypes["project"]
+	c.Assert(rt, NotNil)
+	c.Assert(rt.Name, Equals, "project")
+	c.Assert(rt.Doc, Equals, "A project.")
+	c.Assert(rt.Fields, HasLen, 5)
+
+	self := rt.Field("self_link")
+	c.Assert(self.Type, Equals, "link")
+	c.Assert(self.LinkType, Equals, "")
+	c.Assert(self.Writable, Equals, false)
+
+	name := rt.Field("name")
+	c.Assert(name.Type, Equals, "string")
+	c.Assert(name.Doc, Equals, "Name The project name.")
+	c.Assert(name.Writable, Equals, true)
+
+	c.Assert(rt.Field("date_created").Type, Equals, "dateTime")
+	c.Assert(rt.Field("active").Type, Equals, "boolean")
+
+	owner := rt.Field("owner_link")
+	c.Assert(owner.Type, Equals, "link")
+	c.Assert(owner.LinkType, Equals, "person")
+	c.Assert(owner.Writable, Equals, true)
+
+	c.Assert(rt.Field("missing"), IsNil)
+
+	c.Assert(rt.Operations, HasLen, 2)
+	op := rt.Operation("searchTasks")
+	c.Assert(op.Method, Equals, "GET")
+	c.Assert(op.Doc, Equals, "Search tasks.")
+	c.Assert(op.Returns, Equals, "bug_task-page-resource")
+	c.Assert(op.Params, HasLen, 2)
+	c.Assert(op.Param("status").Options, DeepEquals, []string{"New", "Fix Released"})
+	c.Assert(op.Param("status").Required, Equals, false)
+	c.Assert(op.Param("assignee").Type, Equals, "link")
+	c.Assert(op.Param("assignee").LinkType, Equals, "person")
+
+	op = rt.Operation("newSeries")
+	c.Assert(op.Method, Equals, "POST")
+	c.Assert(op.Params, HasLen, 2)
+	c.Assert(op.Param("name").Required, Equals, true)
+	c.Assert(op.Returns, Equals, "")
+
+	c.Assert(rt.Operation("missing"), IsNil)
+
+	person := desc.ResourceTypes["person"]
+	c.Assert(person.Fields, HasLen, 1)
+	c.Assert(person.Field("display_name"), NotNil)
+}
+
+func (s *WADLS) TestParseServiceDescriptionErrors(c *C) {
+	_, err := lpad.ParseServiceDescription([]byte("<application"))
+	c.Assert(err, ErrorMatches, "cannot parse service description: .*")
+
+	data := `<application><resource_type id="foo"><method href="#bar"/></resource_type></application>`
+	_, err = lpad.ParseServiceDescription([]byte(data))
+	c.Assert(err, ErrorMatches, `resource type "foo" references unknown method "#bar"`)
+}
+
+func (s *WADLS) TestDescribe(c *C) {
+	auth := &dummyAuth{}
+	session := lpad.NewSession(auth)
+	m := M{"resource_type_link": testServer.URL + "/devel/#project"}
+	v := lpad.NewValue(session, testServer.URL+"/devel/", testServer.URL+"/devel/foo", m)
+
+	testServer.PrepareResponse(200, wadlType, testWADL)
+	rt, err := v.Describe()
+	c.Assert(err, IsNil)
+	c.Assert(rt.Name, Equals, "project")
+	c.Assert(auth.signReq, NotNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/devel/")
+	c.Assert(req.Header.Get("Accept"), Equals, lpad.WADLType)
+
+	// The description is cached in the session.
+	m["resource_type_link"] = testServer.URL + "/devel/#person"
+	rt, err = v.Describe()
+	c.Assert(err, IsNil)
+	c.Assert(rt.Name, Equals, "person")
+
+	desc, err := v.ServiceDescription()
+	c.Assert(err, IsNil)
+	c.Assert(desc.ResourceTypes["project"], NotNil)
+
+	m["resource_type_link"] = testServer.URL + "/devel/#unknown"
+	_, err = v.Describe()
+	c.Assert(err, ErrorMatches, `service description has no resource type "unknown"`)
+}
+
+func (s *WADLS) TestDescribeWithoutResourceType(c *C) {
+	v := lpad.NewValue(nil, "", "http://example.com/foo", nil)
+	_, err := v.Describe()
+	c.Assert(err, ErrorMatches, "value has no resource type: http://example.com/foo")
+}
+
+func (s *WADLS) TestDescribeError(c *C) {
+	m := M{"resource_type_link": testServer.URL + "/devel/#project"}
+	v := lpad.NewValue(nil, "", "", m)
+	testServer.PrepareResponse(500, nil, "boom")
+	_, err := v.Describe()
+	c.Assert(err, FitsTypeOf, &lpad.Error{})
+	c.Assert(err.(*lpad.Error).StatusCode, Equals, http.StatusInternalServerError)
+	testServer.WaitRequest()
+}
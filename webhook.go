@@ -0,0 +1,321 @@
+package lpad
+
+import (
+	"time"
+)
+
+// A WebhookEvent holds the type of an event that may be delivered
+// to a webhook.
+type WebhookEvent string
+
+const (
+	EventBzrPush          WebhookEvent = "bzr:push:0.1"
+	EventGitPush          WebhookEvent = "git:push:0.1"
+	EventMergeProposal    WebhookEvent = "merge-proposal:0.1"
+	EventBug              WebhookEvent = "bug:0.1"
+	EventBugComment       WebhookEvent = "bug:comment:0.1"
+	EventSnapBuild        WebhookEvent = "snap:build:0.1"
+	EventCharmRecipeBuild WebhookEvent = "charm-recipe:build:0.1"
+	EventOCIRecipeBuild   WebhookEvent = "oci-recipe:build:0.1"
+	EventLiveFSBuild      WebhookEvent = "livefs:build:0.1"
+)
+
+// The WebhookStub type must be used for creating new webhooks via
+// the NewWebhook method of webhook targets.
+type WebhookStub struct {
+	DeliveryURL string         // Required
+	EventTypes  []WebhookEvent // Required
+	Active      bool
+
+	// Secret is used to sign deliveries with an HMAC in the
+	// X-Hub-Signature header. If empty, deliveries are unsigned.
+	Secret string
+}
+
+func webhooks(v *Value) (*WebhookList, error) {
+	v, err := v.Link("webhooks_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookList{v}, nil
+}
+
+func newWebhook(v *Value, stub *WebhookStub) (*Webhook, error) {
+	var events []string
+	for _, event := range stub.EventTypes {
+		events = append(events, string(event))
+	}
+	params := Params{
+		"ws.op":        "newWebhook",
+		"delivery_url": stub.DeliveryURL,
+		"event_types":  jsonList(events),
+		"active":       "false",
+	}
+	if stub.Active {
+		params["active"] = "true"
+	}
+	if stub.Secret != "" {
+		params["secret"] = stub.Secret
+	}
+	v, err := v.Post(params)
+	if err != nil {
+		return nil, err
+	}
+	return &Webhook{v}, nil
+}
+
+// Webhooks returns the list of webhooks registered for the project.
+func (p *Project) Webhooks() (*WebhookList, error) {
+	return webhooks(p.Value)
+}
+
+// NewWebhook registers a new webhook for the project.
+func (p *Project) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(p.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the distribution.
+func (d *Distro) Webhooks() (*WebhookList, error) {
+	return webhooks(d.Value)
+}
+
+// NewWebhook registers a new webhook for the distribution.
+func (d *Distro) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(d.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the Git repository.
+func (r *GitRepository) Webhooks() (*WebhookList, error) {
+	return webhooks(r.Value)
+}
+
+// NewWebhook registers a new webhook for the Git repository.
+func (r *GitRepository) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(r.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the branch.
+func (b *Branch) Webhooks() (*WebhookList, error) {
+	return webhooks(b.Value)
+}
+
+// NewWebhook registers a new webhook for the branch.
+func (b *Branch) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(b.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the snap package.
+func (snap *Snap) Webhooks() (*WebhookList, error) {
+	return webhooks(snap.Value)
+}
+
+// NewWebhook registers a new webhook for the snap package.
+func (snap *Snap) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(snap.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the charm recipe.
+func (r *CharmRecipe) Webhooks() (*WebhookList, error) {
+	return webhooks(r.Value)
+}
+
+// NewWebhook registers a new webhook for the charm recipe.
+func (r *CharmRecipe) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(r.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the OCI recipe.
+func (r *OCIRecipe) Webhooks() (*WebhookList, error) {
+	return webhooks(r.Value)
+}
+
+// NewWebhook registers a new webhook for the OCI recipe.
+func (r *OCIRecipe) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(r.Value, stub)
+}
+
+// Webhooks returns the list of webhooks registered for the live filesystem.
+func (fs *LiveFS) Webhooks() (*WebhookList, error) {
+	return webhooks(fs.Value)
+}
+
+// NewWebhook registers a new webhook for the live filesystem.
+func (fs *LiveFS) NewWebhook(stub *WebhookStub) (*Webhook, error) {
+	return newWebhook(fs.Value, stub)
+}
+
+// The Webhook type represents a URL that receives notifications of
+// events happening in a webhook target, such as a project or a Git
+// repository.
+type Webhook struct {
+	*Value
+}
+
+// DeliveryURL returns the URL events are delivered to.
+func (wh *Webhook) DeliveryURL() string {
+	return wh.StringField("delivery_url")
+}
+
+// SetDeliveryURL changes the URL events are delivered to.
+// Patch must be called to commit all changes.
+func (wh *Webhook) SetDeliveryURL(url string) {
+	wh.SetField("delivery_url", url)
+}
+
+// EventTypes returns the types of events delivered to the webhook.
+func (wh *Webhook) EventTypes() []WebhookEvent {
+	var events []WebhookEvent
+	for _, event := range wh.StringListField("event_types") {
+		events = append(events, WebhookEvent(event))
+	}
+	return events
+}
+
+// SetEventTypes changes the types of events delivered to the webhook.
+// Patch must be called to commit all changes.
+func (wh *Webhook) SetEventTypes(events []WebhookEvent) {
+	var l []string
+	for _, event := range events {
+		l = append(l, string(event))
+	}
+	wh.SetField("event_types", l)
+}
+
+// Active returns true if events are currently being delivered.
+func (wh *Webhook) Active() bool {
+	return wh.BoolField("active")
+}
+
+// SetActive changes whether events are currently being delivered.
+// Patch must be called to commit all changes.
+func (wh *Webhook) SetActive(active bool) {
+	wh.SetField("active", active)
+}
+
+// DateCreated returns the time the webhook was registered.
+func (wh *Webhook) DateCreated() string {
+	return wh.StringField("date_created")
+}
+
+// Registrant returns the person who registered the webhook.
+func (wh *Webhook) Registrant() (*Person, error) {
+	v, err := wh.Link("registrant_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &Person{v}, nil
+}
+
+// SetSecret changes the secret used to sign deliveries. An empty
+// secret disables signing. Unlike field setters, the change is sent
+// to Launchpad immediately.
+func (wh *Webhook) SetSecret(secret string) error {
+	params := Params{"ws.op": "setSecret"}
+	if secret != "" {
+		params["secret"] = secret
+	}
+	_, err := wh.Post(params)
+	return err
+}
+
+// Ping requests the delivery of a test event to the webhook.
+func (wh *Webhook) Ping() (*WebhookDelivery, error) {
+	v, err := wh.Post(Params{"ws.op": "ping"})
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDelivery{v}, nil
+}
+
+// Deliveries returns the list of recent deliveries to the webhook.
+func (wh *Webhook) Deliveries() (*WebhookDeliveryList, error) {
+	v, err := wh.Link("deliveries_collection_link").Get(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &WebhookDeliveryList{v}, nil
+}
+
+// The WebhookList type represents a list of Webhook objects.
+type WebhookList struct {
+	*Value
+}
+
+// For iterates over the list of webhooks and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *WebhookList) For(f func(wh *Webhook) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&Webhook{v})
+	})
+}
+
+// The WebhookDelivery type represents the delivery of a single event
+// to a webhook.
+type WebhookDelivery struct {
+	*Value
+}
+
+// EventType returns the type of the event delivered.
+func (d *WebhookDelivery) EventType() WebhookEvent {
+	return WebhookEvent(d.StringField("event_type"))
+}
+
+// Payload returns the content of the event delivered.
+func (d *WebhookDelivery) Payload() map[string]interface{} {
+	payload, _ := d.Map()["payload"].(map[string]interface{})
+	return payload
+}
+
+// Pending returns true if the delivery wasn't attempted yet or
+// will be retried.
+func (d *WebhookDelivery) Pending() bool {
+	return d.BoolField("pending")
+}
+
+// Successful returns true if the delivery was accepted by the
+// receiving end.
+func (d *WebhookDelivery) Successful() bool {
+	return d.BoolField("successful")
+}
+
+// ErrorMessage returns the reason why the delivery failed.
+func (d *WebhookDelivery) ErrorMessage() string {
+	return d.StringField("error_message")
+}
+
+// DateCreated returns the time the event happened.
+func (d *WebhookDelivery) DateCreated() string {
+	return d.StringField("date_created")
+}
+
+// DateSent returns the time the event was last sent.
+func (d *WebhookDelivery) DateSent() string {
+	return d.StringField("date_sent")
+}
+
+// Retry schedules the delivery to be attempted again.
+func (d *WebhookDelivery) Retry() error {
+	_, err := d.Post(Params{"ws.op": "retry"})
+	return err
+}
+
+// Wait polls Launchpad every interval for the status of the delivery
+// until it's no longer pending, or until a value is received from stop.
+func (d *WebhookDelivery) Wait(interval time.Duration, stop <-chan bool) error {
+	return d.poll(interval, stop, func() bool { return !d.Pending() })
+}
+
+// The WebhookDeliveryList type represents a list of WebhookDelivery objects.
+type WebhookDeliveryList struct {
+	*Value
+}
+
+// For iterates over the list of deliveries and calls f for each one.
+// If f returns a non-nil error, iteration will stop and the error will
+// be returned as the result of For.
+func (list *WebhookDeliveryList) For(f func(d *WebhookDelivery) error) error {
+	return list.Value.For(func(v *Value) error {
+		return f(&WebhookDelivery{v})
+	})
+}
@@ -0,0 +1,311 @@
+// The webhook package receives events delivered by Launchpad to
+// webhooks registered via lpad, such as pushes to a Git repository
+// or changes to a bug.
+//
+// This simple example demonstrates how to handle pushes:
+//
+//	handler := &webhook.Handler{
+//	    Secret: "s3cr3t",
+//	    OnGitPush: func(d *webhook.Delivery, e *webhook.GitPushEvent) error {
+//	        for path, change := range e.RefChanges {
+//	            fmt.Println(e.RepositoryPath, path, change.New.CommitSHA1)
+//	        }
+//	        return nil
+//	    },
+//	}
+//	http.ListenAndServe(":8080", handler)
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// The Delivery type holds the details common to all deliveries.
+type Delivery struct {
+	Id        string // From the X-Launchpad-Delivery header
+	EventType string // Such as "git:push:0.1"
+	Payload   []byte // The raw JSON payload
+}
+
+// Kind returns the event type without its version, such as "git:push".
+func (d *Delivery) Kind() string {
+	if i := strings.LastIndex(d.EventType, ":"); i >= 0 {
+		return d.EventType[:i]
+	}
+	return d.EventType
+}
+
+// The GitCommit type identifies a commit in a GitRefChange.
+type GitCommit struct {
+	CommitSHA1 string `json:"commit_sha1"`
+}
+
+// The GitRefChange type describes the change of a single Git reference.
+// Old is nil for created references and New is nil for deleted ones.
+type GitRefChange struct {
+	Old *GitCommit `json:"old"`
+	New *GitCommit `json:"new"`
+}
+
+// The GitPushEvent type is delivered for "git:push" events.
+type GitPushEvent struct {
+	Repository     string                  `json:"git_repository"`
+	RepositoryPath string                  `json:"git_repository_path"`
+	RefChanges     map[string]GitRefChange `json:"ref_changes"`
+}
+
+// The MergeProposalState type holds the state of a merge proposal before
+// or after a MergeProposalEvent.
+type MergeProposalState struct {
+	Registrant          string `json:"registrant"`
+	SourceBranch        string `json:"source_branch"`
+	SourceGitRepository string `json:"source_git_repository"`
+	SourceGitPath       string `json:"source_git_path"`
+	TargetBranch        string `json:"target_branch"`
+	TargetGitRepository string `json:"target_git_repository"`
+	TargetGitPath       string `json:"target_git_path"`
+	PrerequisiteBranch  string `json:"prerequisite_branch"`
+	QueueStatus         string `json:"queue_status"`
+	CommitMessage       string `json:"commit_message"`
+	Whiteboard          string `json:"whiteboard"`
+	Description         string `json:"description"`
+	PreviewDiff         string `json:"preview_diff"`
+}
+
+// The MergeProposalEvent type is delivered for "merge-proposal" events.
+// Action is one of "created", "modified" or "deleted", and Old or New is
+// nil when not applicable.
+type MergeProposalEvent struct {
+	MergeProposal string              `json:"merge_proposal"`
+	Action        string              `json:"action"`
+	Old           *MergeProposalState `json:"old"`
+	New           *MergeProposalState `json:"new"`
+}
+
+// The BugState type holds the state of a bug before or after a BugEvent.
+type BugState struct {
+	Title           string   `json:"title"`
+	Description     string   `json:"description"`
+	Status          string   `json:"status"`
+	Importance      string   `json:"importance"`
+	Assignee        string   `json:"assignee"`
+	Owner           string   `json:"owner"`
+	Tags            []string `json:"tags"`
+	DateCreated     string   `json:"date_created"`
+	InformationType string   `json:"information_type"`
+}
+
+// The BugComment type holds a comment delivered in a BugEvent.
+type BugComment struct {
+	Owner   string `json:"owner"`
+	Content string `json:"content"`
+}
+
+// The BugEvent type is delivered for "bug" and "bug:comment" events.
+// For comments, BugComment holds the comment URL and Comment its content.
+type BugEvent struct {
+	Target     string      `json:"target"`
+	Bug        string      `json:"bug"`
+	Action     string      `json:"action"`
+	Old        *BugState   `json:"-"`
+	New        *BugState   `json:"-"`
+	BugComment string      `json:"bug_comment"`
+	Comment    *BugComment `json:"-"`
+}
+
+// The SnapBuildEvent type is delivered for "snap:build" events.
+// Action is either "created" or "status-changed".
+type SnapBuildEvent struct {
+	SnapBuild         string `json:"snap_build"`
+	Action            string `json:"action"`
+	Snap              string `json:"snap"`
+	BuildRequest      string `json:"build_request"`
+	Status            string `json:"status"`
+	StoreUploadStatus string `json:"store_upload_status"`
+}
+
+// The Handler type is an http.Handler that receives webhook deliveries
+// from Launchpad, verifies their signature, and dispatches the decoded
+// events to the callback set for their type. Deliveries of events
+// without a callback are passed to Other, and are accepted and ignored
+// if Other is nil as well.
+//
+// A callback returning an error causes the delivery to fail with a 500
+// status, so that Launchpad will attempt it again later.
+type Handler struct {
+	// Secret is the secret set in the webhook. If non-empty, deliveries
+	// must carry a valid X-Hub-Signature header.
+	Secret string
+
+	OnGitPush       func(d *Delivery, e *GitPushEvent) error
+	OnMergeProposal func(d *Delivery, e *MergeProposalEvent) error
+	OnBug           func(d *Delivery, e *BugEvent) error
+	OnSnapBuild     func(d *Delivery, e *SnapBuildEvent) error
+	Other           func(d *Delivery) error
+}
+
+// maxPayloadSize is the largest delivery payload accepted by Handler.
+// Launchpad payloads are well under this limit.
+const maxPayloadSize = 1 << 20
+
+// ServeHTTP implements the http.Handler interface.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	// The body is read before its signature can be checked, so its
+	// size must be bounded.
+	payload, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxPayloadSize))
+	if _, ok := err.(*http.MaxBytesError); ok {
+		http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "Cannot read request body", http.StatusBadRequest)
+		return
+	}
+	if h.Secret != "" {
+		sig := req.Header.Get("X-Hub-Signature")
+		if sig == "" {
+			http.Error(w, "Missing X-Hub-Signature header", http.StatusUnauthorized)
+			return
+		}
+		if !validSignature(h.Secret, sig, payload) {
+			http.Error(w, "Invalid X-Hub-Signature header", http.StatusForbidden)
+			return
+		}
+	}
+	d := &Delivery{
+		Id:        req.Header.Get("X-Launchpad-Delivery"),
+		EventType: req.Header.Get("X-Launchpad-Event-Type"),
+		Payload:   payload,
+	}
+	err = h.dispatch(d)
+	if _, ok := err.(*payloadError); ok {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+type payloadError struct {
+	eventType string
+	err       error
+}
+
+func (e *payloadError) Error() string {
+	return fmt.Sprintf("Cannot decode %s payload: %v", e.eventType, e.err)
+}
+
+func (h *Handler) dispatch(d *Delivery) error {
+	switch d.Kind() {
+	case "git:push":
+		if h.OnGitPush != nil {
+			e := &GitPushEvent{}
+			if err := decode(d, e); err != nil {
+				return err
+			}
+			return h.OnGitPush(d, e)
+		}
+	case "merge-proposal":
+		if h.OnMergeProposal != nil {
+			e := &MergeProposalEvent{}
+			if err := decode(d, e); err != nil {
+				return err
+			}
+			return h.OnMergeProposal(d, e)
+		}
+	case "bug", "bug:comment":
+		if h.OnBug != nil {
+			e, err := decodeBug(d)
+			if err != nil {
+				return err
+			}
+			return h.OnBug(d, e)
+		}
+	case "snap:build":
+		if h.OnSnapBuild != nil {
+			e := &SnapBuildEvent{}
+			if err := decode(d, e); err != nil {
+				return err
+			}
+			return h.OnSnapBuild(d, e)
+		}
+	}
+	if h.Other != nil {
+		return h.Other(d)
+	}
+	return nil
+}
+
+func decode(d *Delivery, e interface{}) error {
+	if err := json.Unmarshal(d.Payload, e); err != nil {
+		return &payloadError{d.EventType, err}
+	}
+	return nil
+}
+
+// decodeBug decodes the payload of bug events, in which the "old" and
+// "new" entries hold either the bug state or, for comments, the comment.
+func decodeBug(d *Delivery) (*BugEvent, error) {
+	var raw struct {
+		BugEvent
+		Old json.RawMessage `json:"old"`
+		New json.RawMessage `json:"new"`
+	}
+	if err := decode(d, &raw); err != nil {
+		return nil, err
+	}
+	e := &raw.BugEvent
+	var err error
+	if e.BugComment != "" {
+		if hasData(raw.New) {
+			e.Comment = &BugComment{}
+			err = json.Unmarshal(raw.New, e.Comment)
+		}
+	} else {
+		if hasData(raw.Old) {
+			e.Old = &BugState{}
+			err = json.Unmarshal(raw.Old, e.Old)
+		}
+		if err == nil && hasData(raw.New) {
+			e.New = &BugState{}
+			err = json.Unmarshal(raw.New, e.New)
+		}
+	}
+	if err != nil {
+		return nil, &payloadError{d.EventType, err}
+	}
+	return e, nil
+}
+
+func hasData(data json.RawMessage) bool {
+	return len(data) > 0 && string(data) != "null"
+}
+
+// validSignature returns whether sig, in the "sha1=<hex digest>" form
+// used in the X-Hub-Signature header, is the HMAC of payload with secret.
+func validSignature(secret, sig string, payload []byte) bool {
+	if !strings.HasPrefix(sig, "sha1=") {
+		return false
+	}
+	got, err := hex.DecodeString(sig[5:])
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,248 @@
+package webhook_test
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad/webhook"
+)
+
+var _ = Suite(&HandlerS{})
+
+type HandlerS struct{}
+
+func sign(secret, payload string) string {
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write([]byte(payload))
+	return "sha1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func deliver(h http.Handler, eventType, payload, sig string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest("POST", "/hook", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Launchpad-Event-Type", eventType)
+	req.Header.Set("X-Launchpad-Delivery", "/api/devel/~joe/foo/+webhook/1/deliveries/2")
+	if sig != "" {
+		req.Header.Set("X-Hub-Signature", sig)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+const gitPushPayload = `{
+	"git_repository": "/~joe/foo/+git/bar",
+	"git_repository_path": "~joe/foo/+git/bar",
+	"ref_changes": {
+		"refs/heads/master": {"old": {"commit_sha1": "aaa"}, "new": {"commit_sha1": "bbb"}},
+		"refs/heads/gone": {"old": {"commit_sha1": "ccc"}, "new": null}
+	}
+}`
+
+func (s *HandlerS) TestGitPush(c *C) {
+	var delivery *webhook.Delivery
+	var event *webhook.GitPushEvent
+	h := &webhook.Handler{
+		Secret: "s3cr3t",
+		OnGitPush: func(d *webhook.Delivery, e *webhook.GitPushEvent) error {
+			delivery, event = d, e
+			return nil
+		},
+	}
+	rec := deliver(h, "git:push:0.1", gitPushPayload, sign("s3cr3t", gitPushPayload))
+	c.Assert(rec.Code, Equals, 200)
+
+	c.Assert(delivery.Id, Equals, "/api/devel/~joe/foo/+webhook/1/deliveries/2")
+	c.Assert(delivery.EventType, Equals, "git:push:0.1")
+	c.Assert(delivery.Kind(), Equals, "git:push")
+	c.Assert(string(delivery.Payload), Equals, gitPushPayload)
+
+	c.Assert(event.Repository, Equals, "/~joe/foo/+git/bar")
+	c.Assert(event.RepositoryPath, Equals, "~joe/foo/+git/bar")
+	c.Assert(event.RefChanges, HasLen, 2)
+	c.Assert(event.RefChanges["refs/heads/master"].Old.CommitSHA1, Equals, "aaa")
+	c.Assert(event.RefChanges["refs/heads/master"].New.CommitSHA1, Equals, "bbb")
+	c.Assert(event.RefChanges["refs/heads/gone"].New, IsNil)
+}
+
+func (s *HandlerS) TestMissingSignature(c *C) {
+	h := &webhook.Handler{Secret: "s3cr3t", Other: func(d *webhook.Delivery) error {
+		c.Fatalf("Other called")
+		return nil
+	}}
+	rec := deliver(h, "git:push:0.1", gitPushPayload, "")
+	c.Assert(rec.Code, Equals, 401)
+}
+
+func (s *HandlerS) TestBadSignature(c *C) {
+	h := &webhook.Handler{Secret: "s3cr3t", Other: func(d *webhook.Delivery) error {
+		c.Fatalf("Other called")
+		return nil
+	}}
+	for _, sig := range []string{sign("wrong", gitPushPayload), "sha1=zz", "md5=abc"} {
+		rec := deliver(h, "git:push:0.1", gitPushPayload, sig)
+		c.Assert(rec.Code, Equals, 403)
+	}
+}
+
+func (s *HandlerS) TestNoSecret(c *C) {
+	called := false
+	h := &webhook.Handler{OnGitPush: func(d *webhook.Delivery, e *webhook.GitPushEvent) error {
+		called = true
+		return nil
+	}}
+	rec := deliver(h, "git:push:0.1", gitPushPayload, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(called, Equals, true)
+}
+
+func (s *HandlerS) TestPayloadTooLarge(c *C) {
+	h := &webhook.Handler{Secret: "s3cr3t", Other: func(d *webhook.Delivery) error {
+		c.Fatalf("Other called")
+		return nil
+	}}
+	payload := strings.Repeat(" ", 1<<20+1)
+	rec := deliver(h, "git:push:0.1", payload, sign("s3cr3t", payload))
+	c.Assert(rec.Code, Equals, 413)
+}
+
+func (s *HandlerS) TestMethodNotAllowed(c *C) {
+	req := httptest.NewRequest("GET", "/hook", nil)
+	rec := httptest.NewRecorder()
+	(&webhook.Handler{}).ServeHTTP(rec, req)
+	c.Assert(rec.Code, Equals, 405)
+}
+
+func (s *HandlerS) TestMergeProposal(c *C) {
+	payload := `{
+		"merge_proposal": "/~joe/foo/+git/bar/+merge/1",
+		"action": "modified",
+		"old": {"queue_status": "Needs review", "source_git_path": "refs/heads/fix"},
+		"new": {"queue_status": "Approved", "source_git_path": "refs/heads/fix"}
+	}`
+	var event *webhook.MergeProposalEvent
+	h := &webhook.Handler{OnMergeProposal: func(d *webhook.Delivery, e *webhook.MergeProposalEvent) error {
+		event = e
+		return nil
+	}}
+	rec := deliver(h, "merge-proposal:0.1", payload, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(event.MergeProposal, Equals, "/~joe/foo/+git/bar/+merge/1")
+	c.Assert(event.Action, Equals, "modified")
+	c.Assert(event.Old.QueueStatus, Equals, "Needs review")
+	c.Assert(event.New.QueueStatus, Equals, "Approved")
+	c.Assert(event.New.SourceGitPath, Equals, "refs/heads/fix")
+}
+
+func (s *HandlerS) TestBug(c *C) {
+	payload := `{
+		"target": "/foo",
+		"bug": "/bugs/1",
+		"action": "created",
+		"new": {"title": "Broken", "status": "New", "tags": ["crash"]}
+	}`
+	var event *webhook.BugEvent
+	h := &webhook.Handler{OnBug: func(d *webhook.Delivery, e *webhook.BugEvent) error {
+		event = e
+		return nil
+	}}
+	rec := deliver(h, "bug:0.1", payload, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(event.Target, Equals, "/foo")
+	c.Assert(event.Bug, Equals, "/bugs/1")
+	c.Assert(event.Action, Equals, "created")
+	c.Assert(event.Old, IsNil)
+	c.Assert(event.New.Title, Equals, "Broken")
+	c.Assert(event.New.Tags, DeepEquals, []string{"crash"})
+	c.Assert(event.Comment, IsNil)
+}
+
+func (s *HandlerS) TestBugComment(c *C) {
+	payload := `{
+		"target": "/foo",
+		"bug": "/bugs/1",
+		"bug_comment": "/bugs/1/comments/1",
+		"action": "created",
+		"new": {"owner": "/~joe", "content": "Me too."}
+	}`
+	var event *webhook.BugEvent
+	h := &webhook.Handler{OnBug: func(d *webhook.Delivery, e *webhook.BugEvent) error {
+		event = e
+		return nil
+	}}
+	rec := deliver(h, "bug:comment:0.1", payload, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(event.BugComment, Equals, "/bugs/1/comments/1")
+	c.Assert(event.Comment.Owner, Equals, "/~joe")
+	c.Assert(event.Comment.Content, Equals, "Me too.")
+	c.Assert(event.New, IsNil)
+}
+
+func (s *HandlerS) TestSnapBuild(c *C) {
+	payload := `{
+		"snap_build": "/~joe/+snap/foo/+build/1",
+		"action": "status-changed",
+		"snap": "/~joe/+snap/foo",
+		"status": "Successfully built",
+		"store_upload_status": "Uploaded"
+	}`
+	var event *webhook.SnapBuildEvent
+	h := &webhook.Handler{OnSnapBuild: func(d *webhook.Delivery, e *webhook.SnapBuildEvent) error {
+		event = e
+		return nil
+	}}
+	rec := deliver(h, "snap:build:0.1", payload, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(event.SnapBuild, Equals, "/~joe/+snap/foo/+build/1")
+	c.Assert(event.Action, Equals, "status-changed")
+	c.Assert(event.Snap, Equals, "/~joe/+snap/foo")
+	c.Assert(event.Status, Equals, "Successfully built")
+	c.Assert(event.StoreUploadStatus, Equals, "Uploaded")
+}
+
+func (s *HandlerS) TestOther(c *C) {
+	var delivery *webhook.Delivery
+	h := &webhook.Handler{Other: func(d *webhook.Delivery) error {
+		delivery = d
+		return nil
+	}}
+	rec := deliver(h, "ping", `{"ping": true}`, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(delivery.EventType, Equals, "ping")
+	c.Assert(string(delivery.Payload), Equals, `{"ping": true}`)
+
+	rec = deliver(h, "git:push:0.1", gitPushPayload, "")
+	c.Assert(rec.Code, Equals, 200)
+	c.Assert(delivery.EventType, Equals, "git:push:0.1")
+}
+
+func (s *HandlerS) TestUnhandled(c *C) {
+	rec := deliver(&webhook.Handler{}, "bug:0.1", `{}`, "")
+	c.Assert(rec.Code, Equals, 200)
+}
+
+func (s *HandlerS) TestBadPayload(c *C) {
+	h := &webhook.Handler{OnGitPush: func(d *webhook.Delivery, e *webhook.GitPushEvent) error {
+		c.Fatalf("OnGitPush called")
+		return nil
+	}}
+	rec := deliver(h, "git:push:0.1", `{"ref_changes": []}`, "")
+	c.Assert(rec.Code, Equals, 400)
+	c.Assert(rec.Body.String(), Matches, "Cannot decode git:push:0.1 payload: .*\n")
+}
+
+func (s *HandlerS) TestCallbackError(c *C) {
+	h := &webhook.Handler{OnGitPush: func(d *webhook.Delivery, e *webhook.GitPushEvent) error {
+		return errors.New("boom")
+	}}
+	rec := deliver(h, "git:push:0.1", gitPushPayload, "")
+	c.Assert(rec.Code, Equals, 500)
+	c.Assert(rec.Body.String(), Equals, "boom\n")
+}
@@ -0,0 +1,183 @@
+package lpad_test
+
+import (
+	. "gopkg.in/check.v1"
+
+	"github.com/canonical/lpad"
+)
+
+func (s *ModelS) TestWebhook(c *C) {
+	m := M{
+		"delivery_url":    "http://example.com/hook",
+		"event_types":     []interface{}{"git:push:0.1", "merge-proposal:0.1"},
+		"active":          true,
+		"date_created":    "2020-01-01",
+		"registrant_link": testServer.URL + "/registrant_link",
+	}
+	wh := &lpad.Webhook{lpad.NewValue(nil, "", "", m)}
+	c.Assert(wh.DeliveryURL(), Equals, "http://example.com/hook")
+	c.Assert(wh.EventTypes(), DeepEquals, []lpad.WebhookEvent{lpad.EventGitPush, lpad.EventMergeProposal})
+	c.Assert(wh.Active(), Equals, true)
+	c.Assert(wh.DateCreated(), Equals, "2020-01-01")
+
+	wh.SetDeliveryURL("http://example.com/other")
+	wh.SetEventTypes([]lpad.WebhookEvent{lpad.EventBug})
+	wh.SetActive(false)
+	c.Assert(wh.DeliveryURL(), Equals, "http://example.com/other")
+	c.Assert(wh.EventTypes(), DeepEquals, []lpad.WebhookEvent{lpad.EventBug})
+	c.Assert(wh.Active(), Equals, false)
+
+	testServer.PrepareResponse(200, jsonType, `{"name": "joe"}`)
+	registrant, err := wh.Registrant()
+	c.Assert(err, IsNil)
+	c.Assert(registrant.Name(), Equals, "joe")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/registrant_link")
+}
+
+func (s *ModelS) TestProjectWebhooks(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"delivery_url": "http://hook"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"webhooks_collection_link": testServer.URL + "/webhooks_link"}
+	project := &lpad.Project{lpad.NewValue(nil, "", "", m)}
+	list, err := project.Webhooks()
+	c.Assert(err, IsNil)
+
+	urls := []string{}
+	list.For(func(wh *lpad.Webhook) error {
+		urls = append(urls, wh.DeliveryURL())
+		return nil
+	})
+	c.Assert(urls, DeepEquals, []string{"http://hook"})
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "GET")
+	c.Assert(req.URL.Path, Equals, "/webhooks_link")
+}
+
+func (s *ModelS) TestGitRepositoryNewWebhook(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"delivery_url": "http://hook"}`)
+	repo := &lpad.GitRepository{lpad.NewValue(nil, "", testServer.URL+"/repo", nil)}
+	stub := &lpad.WebhookStub{
+		DeliveryURL: "http://hook",
+		EventTypes:  []lpad.WebhookEvent{lpad.EventGitPush, lpad.EventMergeProposal},
+		Active:      true,
+		Secret:      "s3cr3t",
+	}
+	wh, err := repo.NewWebhook(stub)
+	c.Assert(err, IsNil)
+	c.Assert(wh.DeliveryURL(), Equals, "http://hook")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/repo")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"newWebhook"})
+	c.Assert(req.Form["delivery_url"], DeepEquals, []string{"http://hook"})
+	c.Assert(req.Form["event_types"], DeepEquals, []string{`["git:push:0.1","merge-proposal:0.1"]`})
+	c.Assert(req.Form["active"], DeepEquals, []string{"true"})
+	c.Assert(req.Form["secret"], DeepEquals, []string{"s3cr3t"})
+}
+
+func (s *ModelS) TestSnapNewWebhookWithoutSecret(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{}`)
+	snap := &lpad.Snap{lpad.NewValue(nil, "", testServer.URL+"/snap", nil)}
+	stub := &lpad.WebhookStub{
+		DeliveryURL: "http://hook",
+		EventTypes:  []lpad.WebhookEvent{lpad.EventSnapBuild},
+	}
+	_, err := snap.NewWebhook(stub)
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Form["active"], DeepEquals, []string{"false"})
+	c.Assert(req.Form["event_types"], DeepEquals, []string{`["snap:build:0.1"]`})
+	c.Assert(req.Form["secret"], IsNil)
+}
+
+func (s *ModelS) TestWebhookPing(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{"event_type": "ping", "pending": true}`)
+	wh := &lpad.Webhook{lpad.NewValue(nil, "", testServer.URL+"/hook", nil)}
+	d, err := wh.Ping()
+	c.Assert(err, IsNil)
+	c.Assert(d.EventType(), Equals, lpad.WebhookEvent("ping"))
+	c.Assert(d.Pending(), Equals, true)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/hook")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"ping"})
+}
+
+func (s *ModelS) TestWebhookSetSecret(c *C) {
+	testServer.PrepareResponse(200, jsonType, `{}`)
+	wh := &lpad.Webhook{lpad.NewValue(nil, "", testServer.URL+"/hook", nil)}
+	err := wh.SetSecret("s3cr3t")
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"setSecret"})
+	c.Assert(req.Form["secret"], DeepEquals, []string{"s3cr3t"})
+}
+
+func (s *ModelS) TestWebhookDelete(c *C) {
+	testServer.PrepareResponse(200, jsonType, "")
+	wh := &lpad.Webhook{lpad.NewValue(nil, "", testServer.URL+"/hook", nil)}
+	err := wh.Delete()
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "DELETE")
+	c.Assert(req.URL.Path, Equals, "/hook")
+}
+
+func (s *ModelS) TestWebhookDeliveries(c *C) {
+	data := `{"total_size": 1, "start": 0, "entries": [{"event_type": "bug:0.1", "successful": false, "error_message": "Bad HTTP response: 500"}]}`
+	testServer.PrepareResponse(200, jsonType, data)
+	m := M{"deliveries_collection_link": testServer.URL + "/deliveries_link"}
+	wh := &lpad.Webhook{lpad.NewValue(nil, "", "", m)}
+	list, err := wh.Deliveries()
+	c.Assert(err, IsNil)
+
+	var deliveries []*lpad.WebhookDelivery
+	list.For(func(d *lpad.WebhookDelivery) error {
+		deliveries = append(deliveries, d)
+		return nil
+	})
+	c.Assert(deliveries, HasLen, 1)
+	c.Assert(deliveries[0].EventType(), Equals, lpad.EventBug)
+	c.Assert(deliveries[0].Successful(), Equals, false)
+	c.Assert(deliveries[0].ErrorMessage(), Equals, "Bad HTTP response: 500")
+
+	req := testServer.WaitRequest()
+	c.Assert(req.URL.Path, Equals, "/deliveries_link")
+}
+
+func (s *ModelS) TestWebhookDelivery(c *C) {
+	m := M{
+		"event_type":   "git:push:0.1",
+		"pending":      false,
+		"successful":   true,
+		"date_created": "2020-01-01",
+		"date_sent":    "2020-01-02",
+		"payload":      map[string]interface{}{"git_repository_path": "~joe/foo"},
+	}
+	d := &lpad.WebhookDelivery{lpad.NewValue(nil, "", testServer.URL+"/delivery", m)}
+	c.Assert(d.EventType(), Equals, lpad.EventGitPush)
+	c.Assert(d.Pending(), Equals, false)
+	c.Assert(d.Successful(), Equals, true)
+	c.Assert(d.DateCreated(), Equals, "2020-01-01")
+	c.Assert(d.DateSent(), Equals, "2020-01-02")
+	c.Assert(d.Payload()["git_repository_path"], Equals, "~joe/foo")
+
+	testServer.PrepareResponse(200, jsonType, `{}`)
+	err := d.Retry()
+	c.Assert(err, IsNil)
+
+	req := testServer.WaitRequest()
+	c.Assert(req.Method, Equals, "POST")
+	c.Assert(req.URL.Path, Equals, "/delivery")
+	c.Assert(req.Form["ws.op"], DeepEquals, []string{"retry"})
+}